@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/arenzana/usfmp/pkg/concordance"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Concordance flags
+	concordanceFormat string
+	renderings        bool
+)
+
+// concordanceCmd lists the occurrences of a Strong's number or a tagged word
+var concordanceCmd = &cobra.Command{
+	Use:   "concordance STRONGS-OR-WORD input-file-or-directory...",
+	Short: "List where a Strong's number such as H0430 occurs, or how it is rendered",
+	Long: `concordance reads the Strong's numbers of tagged words (\w God|strong="H0430"\w*) and lists
+every occurrence of a Strong's number with the word it is rendered by. With --renderings, it lists
+the words that render the number instead, with how often each one does.
+
+A word instead of a number lists the occurrences of the word with their Strong's numbers, or with
+--renderings the Strong's numbers the word renders. Words match regardless of case and diacritics.`,
+	Example: `  usfmp concordance H0430 samples/eng-kjv_usfm
+  usfmp concordance --renderings H430 samples/eng-kjv_usfm
+  usfmp concordance --renderings LORD samples/eng-kjv_usfm
+  usfmp concordance -f tsv G0026 samples/eng-kjv_usfm > agape.tsv`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runConcordance,
+}
+
+func init() {
+	addInputFlags(concordanceCmd.Flags())
+
+	concordanceCmd.Flags().StringVarP(&concordanceFormat, "format", "f", "txt",
+		"Output format: txt, tsv, json")
+	concordanceCmd.Flags().StringVarP(&outputFile, "output", "o", "",
+		"Output file (default: stdout)")
+	concordanceCmd.Flags().BoolVar(&renderings, "renderings", false,
+		"List the words that render a Strong's number (or the numbers a word renders) with counts")
+
+	rootCmd.AddCommand(concordanceCmd)
+}
+
+// runConcordance indexes the inputs and writes the occurrences or renderings of a Strong's number or word
+func runConcordance(cmd *cobra.Command, args []string) error {
+	if quiet && verbose {
+		return fmt.Errorf("cannot use both --quiet and --verbose flags")
+	}
+	if !contains([]string{"txt", "tsv", "json"}, concordanceFormat) {
+		return fmt.Errorf("invalid output format: %s (valid: txt, tsv, json)", concordanceFormat)
+	}
+
+	query := strings.TrimSpace(args[0])
+	if query == "" {
+		return fmt.Errorf("invalid query: empty query")
+	}
+	isStrong := concordance.IsStrong(query)
+	if isStrong {
+		query, _ = concordance.ParseStrong(query)
+	}
+
+	bibles, err := parseInputs(args[1:])
+	if err != nil {
+		return err
+	}
+	index := concordance.Build(bibles...)
+	logInfo("Indexed %d tagged words", index.Len())
+	if index.Len() == 0 {
+		return fmt.Errorf("the input has no words tagged with Strong's numbers")
+	}
+
+	if renderings {
+		var counts []concordance.Rendering
+		if isStrong {
+			counts, _ = index.Renderings(query)
+		} else {
+			counts = index.Numbers(query)
+		}
+		logInfo("Found %d renderings", len(counts))
+		return streamOutput(func(w io.Writer) error {
+			return writeRenderings(w, counts, isStrong)
+		})
+	}
+
+	var occurrences []concordance.Occurrence
+	if isStrong {
+		occurrences, _ = index.Strong(query)
+	} else {
+		occurrences = index.Word(query)
+	}
+	logInfo("Found %d occurrences", len(occurrences))
+	return streamOutput(func(w io.Writer) error {
+		return writeOccurrences(w, occurrences, len(bibles) > 1)
+	})
+}
+
+// writeOccurrences writes occurrences in the --format of the concordance command,
+// naming the translation of each one when several were indexed
+func writeOccurrences(w io.Writer, occurrences []concordance.Occurrence, translations bool) error {
+	switch concordanceFormat {
+	case "json":
+		return writeJSONList(w, occurrences)
+
+	case "tsv":
+		if _, err := io.WriteString(w, "translation\treference\tbook\tchapter\tverse\tword\tstrong\n"); err != nil {
+			return err
+		}
+		for _, o := range occurrences {
+			if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\t%s\t%s\n", o.Translation, o.Range.String(),
+				o.Range.Start.Book, o.Range.Start.Chapter, o.Range.Start.Verse, o.Word, o.Strong); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, o := range occurrences {
+		reference := o.Range.String()
+		if translations {
+			reference += " (" + o.Translation + ")"
+		}
+		if _, err := fmt.Fprintf(w, "%-24s %-8s %s\n", reference, o.Strong, o.Word); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeRenderings writes rendering counts in the --format of the concordance command;
+// the text format names only the words when they all render the same Strong's number
+func writeRenderings(w io.Writer, counts []concordance.Rendering, wordsOnly bool) error {
+	switch concordanceFormat {
+	case "json":
+		return writeJSONList(w, counts)
+
+	case "tsv":
+		if _, err := io.WriteString(w, "strong\tword\tcount\n"); err != nil {
+			return err
+		}
+		for _, r := range counts {
+			if _, err := fmt.Fprintf(w, "%s\t%s\t%d\n", r.Strong, r.Word, r.Count); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, r := range counts {
+		name := r.Strong + " " + r.Word
+		if wordsOnly {
+			name = r.Word
+		}
+		if _, err := fmt.Fprintf(w, "%7d  %s\n", r.Count, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeJSONList writes a list as an indented JSON array, with [] for an empty list
+func writeJSONList[T any](w io.Writer, items []T) error {
+	if items == nil {
+		items = []T{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(items)
+}
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/arenzana/usfmp/pkg/diff"
+	"github.com/arenzana/usfmp/pkg/ref"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Diff flags
+	diffFormat string
+)
+
+// diffCmd compares two editions of the same books verse by verse
+var diffCmd = &cobra.Command{
+	Use:   "diff old-file-or-directory new-file-or-directory",
+	Short: "Show the verses, headings, and footnotes that differ between two editions",
+	Long: `diff aligns the books of two editions or translations by book, chapter, and verse, and
+reports added, removed, and modified verses, section headings, and footnotes, and added or
+removed books. Text is compared without markup; modified text is shown with a word-level
+diff, with removed words as [-word-] and added words as {+word+}.
+
+Formats:
+  txt      One line per change: "+" added, "-" removed, "~" modified (default)
+  unified  Removed and added lines under a header per chapter, like diff -u
+  json     A list of changes with the old and new text and the word-level diff`,
+	Example: `  usfmp diff bsb-2023/ bsb-2024/
+  usfmp diff -f unified old/01GENBSB.SFM new/01GENBSB.SFM
+  usfmp diff -f json old/ new/ | jq '.[] | select(.item == "footnote")'`,
+	Args: cobra.ExactArgs(2),
+	RunE: runDiff,
+}
+
+func init() {
+	addInputFlags(diffCmd.Flags())
+
+	diffCmd.Flags().StringVarP(&diffFormat, "format", "f", "txt",
+		"Output format: txt, unified, json")
+	diffCmd.Flags().StringVarP(&outputFile, "output", "o", "",
+		"Output file (default: stdout)")
+
+	rootCmd.AddCommand(diffCmd)
+}
+
+// runDiff parses both inputs and writes their differences
+func runDiff(cmd *cobra.Command, args []string) error {
+	if quiet && verbose {
+		return fmt.Errorf("cannot use both --quiet and --verbose flags")
+	}
+	if !contains([]string{"txt", "unified", "json"}, diffFormat) {
+		return fmt.Errorf("invalid output format: %s (valid: txt, unified, json)", diffFormat)
+	}
+
+	bibles, err := parseInputs(args)
+	if err != nil {
+		return err
+	}
+	changes := diff.Compare(bibles[0].Documents, bibles[1].Documents)
+
+	counts := make(map[string]int)
+	for _, change := range changes {
+		counts[change.Item]++
+	}
+	logInfo("%d changes: %d books, %d verses, %d headings, %d footnotes", len(changes),
+		counts[diff.Book], counts[diff.Verse], counts[diff.Heading], counts[diff.Footnote])
+
+	return streamOutput(func(w io.Writer) error {
+		switch diffFormat {
+		case "json":
+			return writeJSONList(w, changes)
+		case "unified":
+			return writeUnifiedDiff(w, args[0], args[1], changes)
+		}
+
+		for _, change := range changes {
+			if _, err := fmt.Fprintln(w, change); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// writeUnifiedDiff writes the changes as removed and added lines, with a "@@ BOOK CHAPTER @@"
+// header before the changes of each chapter
+func writeUnifiedDiff(w io.Writer, oldName, newName string, changes []diff.Change) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	if _, err := fmt.Fprintf(w, "--- %s\n+++ %s\n", oldName, newName); err != nil {
+		return err
+	}
+
+	var chapter ref.Reference
+	for _, change := range changes {
+		start := change.Reference.Start
+		if current := (ref.Reference{Book: start.Book, Chapter: start.Chapter}); current != chapter {
+			chapter = current
+			if _, err := fmt.Fprintf(w, "@@ %s @@\n", ref.Format([]ref.Range{{Start: chapter}}, ref.USFM)); err != nil {
+				return err
+			}
+		}
+
+		label := ref.Format([]ref.Range{change.Reference}, ref.USFM)
+		if change.Item != diff.Verse {
+			label += " " + change.Item
+		}
+		var lines []string
+		if change.Kind != diff.Added {
+			lines = append(lines, strings.TrimSpace("-"+label+" "+change.Old))
+		}
+		if change.Kind != diff.Removed {
+			lines = append(lines, strings.TrimSpace("+"+label+" "+change.New))
+		}
+		for _, line := range lines {
+			if _, err := fmt.Fprintln(w, line); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
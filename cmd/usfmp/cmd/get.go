@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/arenzana/usfmp/pkg/ref"
+	"github.com/arenzana/usfmp/pkg/usfm"
+	"github.com/spf13/cobra"
+)
+
+// getCmd prints a passage looked up by reference
+var getCmd = &cobra.Command{
+	Use:   "get REFERENCE input-file-or-directory...",
+	Short: "Print the verses of a passage such as \"JHN 3:16-18\"",
+	Long: `get looks up a passage in the parsed USFM input and prints its verses, with their
+section headings and footnotes, in any output format.
+
+The reference may list several passages and use book names, USFM codes, or abbreviations:
+"JHN 3:16-18", "Genesis 1:1–2:3", "Ps 23; 121", or "1 Cor 13:4-7, 13".`,
+	Example: `  usfmp get 'JHN 3:16-18' samples/bsb_usfm
+  usfmp get -f txt 'Genesis 1:1-5; John 1:1-5' samples/bsb_usfm
+  usfmp get -f html -o psalm23.html 'Ps 23' samples/bsb_usfm`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runGet,
+}
+
+func init() {
+	addInputFlags(getCmd.Flags())
+	addOutputFlags(getCmd.Flags())
+	rootCmd.AddCommand(getCmd)
+}
+
+// runGet parses the inputs and writes the verses of the requested passage
+func runGet(cmd *cobra.Command, args []string) error {
+	entry, output, err := newOutputFormatter(cmd.Flags())
+	if err != nil {
+		return err
+	}
+	if corpus {
+		return fmt.Errorf("--corpus is not supported by get")
+	}
+
+	ranges, err := ref.Parse(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid reference: %w", err)
+	}
+
+	documents, err := parseDocuments(args[1:])
+	if err != nil {
+		return err
+	}
+
+	passages := usfm.Passages(documents, ranges...)
+	if len(passages) == 0 {
+		return fmt.Errorf("no verses found for %s", ref.Format(ranges, ref.Default))
+	}
+
+	return outputResults(passages, entry, output)
+}
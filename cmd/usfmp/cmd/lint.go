@@ -0,0 +1,200 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/arenzana/usfmp/pkg/lint"
+	"github.com/arenzana/usfmp/pkg/versification"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Lint flags
+	lintFormat          string
+	ignoreRules         []string
+	lintVersification   string
+	customVersification string
+)
+
+// lintCmd checks USFM files for structural problems
+var lintCmd = &cobra.Command{
+	Use:   "lint input-file-or-directory...",
+	Short: "Check chapter and verse numbering, empty verses, footnotes, metadata, and markers",
+	Long: `lint checks the structure of USFM files and reports every problem with its file, line, and
+rule ID. Unlike --strict, which only rejects unknown markers, lint reads files that cannot be
+parsed as well. It exits with an error if it finds any problem.
+
+With --versification, lint also checks that every book has the chapters and verses of a
+versification: english, original, septuagint, vulgate, or a Paratext .vrs file. A Paratext
+custom.vrs file given with --custom-versification amends the scheme for a translation.
+
+Rules:
+` + ruleList(),
+	Example: `  usfmp lint samples/bsb_usfm
+  usfmp lint --ignore missing-metadata,verse-gap samples/bsb_usfm
+  usfmp lint --versification english --custom-versification samples/bsb_usfm/custom.vrs samples/bsb_usfm
+  usfmp lint -f json genesis.usfm | jq '.[].rule'`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runLint,
+}
+
+func init() {
+	lintCmd.Flags().BoolVarP(&verbose, "verbose", "v", false,
+		"Verbose output")
+	lintCmd.Flags().BoolVarP(&quiet, "quiet", "q", false,
+		"Quiet mode - minimal output")
+
+	lintCmd.Flags().StringVarP(&lintFormat, "format", "f", "txt",
+		"Output format: txt, tsv, json")
+	lintCmd.Flags().StringVarP(&outputFile, "output", "o", "",
+		"Output file (default: stdout)")
+	lintCmd.Flags().StringSliceVar(&ignoreRules, "ignore", nil,
+		"Rule IDs to leave out of the report (comma-separated)")
+	lintCmd.Flags().StringVar(&lintVersification, "versification", "",
+		"Check chapters and verses against a versification: english, original, septuagint, vulgate, or a .vrs file")
+	lintCmd.Flags().StringVar(&customVersification, "custom-versification", "",
+		"Paratext custom.vrs file that amends the --versification scheme")
+
+	rootCmd.AddCommand(lintCmd)
+}
+
+// ruleList lists the lint rules with their descriptions for the help text
+func ruleList() string {
+	var result strings.Builder
+	for _, rule := range lint.Rules {
+		fmt.Fprintf(&result, "  %-18s %s\n", rule.ID, rule.Description)
+	}
+	return result.String()
+}
+
+// runLint checks the input files and writes the findings
+func runLint(cmd *cobra.Command, args []string) error {
+	if quiet && verbose {
+		return fmt.Errorf("cannot use both --quiet and --verbose flags")
+	}
+	if !contains([]string{"txt", "tsv", "json"}, lintFormat) {
+		return fmt.Errorf("invalid output format: %s (valid: txt, tsv, json)", lintFormat)
+	}
+	ignored := make(map[string]bool)
+	for _, id := range ignoreRules {
+		if _, ok := lint.LookupRule(id); !ok {
+			return fmt.Errorf("unknown rule: %s", id)
+		}
+		ignored[id] = true
+	}
+	if customVersification != "" && lintVersification == "" {
+		return fmt.Errorf("--custom-versification requires --versification")
+	}
+	var options lint.Options
+	if lintVersification != "" {
+		scheme, err := loadVersification(lintVersification, customVersification)
+		if err != nil {
+			return err
+		}
+		options.Versification = scheme
+	}
+
+	var files []string
+	for _, inputPath := range args {
+		inputFiles, err := inputFiles(inputPath)
+		if err != nil {
+			return err
+		}
+		files = append(files, inputFiles...)
+	}
+
+	var findings []lint.Finding
+	for _, file := range files {
+		logInfo("Checking file: %s", file)
+		fileFindings, err := lint.CheckFile(file, options)
+		if err != nil {
+			return err
+		}
+		for _, finding := range fileFindings {
+			if !ignored[finding.Rule] {
+				findings = append(findings, finding)
+			}
+		}
+	}
+
+	if err := streamOutput(func(w io.Writer) error {
+		return writeFindings(w, findings)
+	}); err != nil {
+		return err
+	}
+
+	if len(findings) > 0 {
+		// The problems are the report; usage help would only hide them
+		cmd.SilenceUsage = true
+		noun := "problems"
+		if len(findings) == 1 {
+			noun = "problem"
+		}
+		return fmt.Errorf("found %d %s in %d files", len(findings), noun, len(files))
+	}
+	logInfo("No problems found in %d files", len(files))
+	return nil
+}
+
+// loadVersification returns the built-in scheme with the given name or loads a .vrs file,
+// amended by a custom.vrs file if one is given
+func loadVersification(name, customPath string) (*versification.Scheme, error) {
+	scheme, ok := versification.Lookup(name)
+	if !ok {
+		if !strings.HasSuffix(strings.ToLower(name), ".vrs") {
+			return nil, fmt.Errorf("unknown versification: %s (valid: english, original, septuagint, vulgate, or a .vrs file)", name)
+		}
+		var err error
+		if scheme, err = versification.LoadFile(name); err != nil {
+			return nil, err
+		}
+	}
+	if customPath == "" {
+		return scheme, nil
+	}
+	return customizeVersification(scheme, customPath)
+}
+
+// customizeVersification amends a scheme with a Paratext custom.vrs file
+func customizeVersification(scheme *versification.Scheme, customPath string) (*versification.Scheme, error) {
+	file, err := os.Open(customPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open custom versification: %w", err)
+	}
+	defer file.Close()
+
+	custom, err := scheme.Customize(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", customPath, err)
+	}
+	return custom, nil
+}
+
+// writeFindings writes lint findings in the --format of the lint command
+func writeFindings(w io.Writer, findings []lint.Finding) error {
+	switch lintFormat {
+	case "json":
+		return writeJSONList(w, findings)
+
+	case "tsv":
+		if _, err := io.WriteString(w, "file\tline\trule\tmessage\n"); err != nil {
+			return err
+		}
+		for _, f := range findings {
+			if _, err := fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", f.File, f.Line, f.Rule, f.Message); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, f := range findings {
+		if _, err := fmt.Fprintln(w, f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/arenzana/usfmp/internal/formatter"
+	"github.com/arenzana/usfmp/pkg/parallel"
+	"github.com/arenzana/usfmp/pkg/versification"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Parallel flags
+	parallelFormat       string
+	parallelScheme       string
+	sourceVersifications map[string]string
+)
+
+// parallelCmd writes several translations side by side, aligned verse by verse
+var parallelCmd = &cobra.Command{
+	Use:   "parallel input-file-or-directory...",
+	Short: "Write several translations side by side, aligned verse by verse",
+	Long: `parallel aligns the verses of several translations, one per input, and writes one row per
+verse of the --versification (english by default) with the text of every translation: as TSV or
+CSV with one column per translation, as a side-by-side HTML table, or as JSON with one record
+per verse. Translations are named after their input file or directory.
+
+Translations numbered differently are mapped to the versification: give their scheme with
+--source-versification NAME=SCHEME (e.g., lxx=septuagint). A Paratext custom.vrs file in an
+input directory amends the scheme of that translation. With --versification none, the
+translations are aligned by their own chapter and verse numbers.`,
+	Example: `  usfmp parallel samples/bsb_usfm samples/eng-kjv_usfm -o parallel.tsv
+  usfmp parallel -f html --title "BSB and KJV" samples/bsb_usfm samples/eng-kjv_usfm -o parallel.html
+  usfmp parallel -f json --source-versification vulgate_usfm=vulgate samples/bsb_usfm vulgate_usfm/`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runParallel,
+}
+
+func init() {
+	addInputFlags(parallelCmd.Flags())
+
+	parallelCmd.Flags().StringVarP(&parallelFormat, "format", "f", "tsv",
+		"Output format: tsv, csv, html, json")
+	parallelCmd.Flags().StringVarP(&outputFile, "output", "o", "",
+		"Output file (default: stdout)")
+	parallelCmd.Flags().StringVar(&parallelScheme, "versification", "english",
+		"Versification of the rows: english, original, septuagint, vulgate, a .vrs file, or none")
+	parallelCmd.Flags().StringToStringVar(&sourceVersifications, "source-versification", nil,
+		"Versification of a translation numbered differently, as NAME=SCHEME (repeatable)")
+	parallelCmd.Flags().StringVar(&title, "title", "",
+		"Page title (html)")
+	parallelCmd.Flags().StringVar(&language, "lang", "",
+		"Language code of the text, e.g. en (html)")
+	parallelCmd.Flags().StringVar(&cssMode, "css", "inline",
+		"HTML styling: inline (embedded stylesheet) or classes (class names only)")
+
+	rootCmd.AddCommand(parallelCmd)
+}
+
+// runParallel parses the inputs as translations and writes them aligned
+func runParallel(cmd *cobra.Command, args []string) error {
+	if quiet && verbose {
+		return fmt.Errorf("cannot use both --quiet and --verbose flags")
+	}
+	if !contains([]string{"tsv", "csv", "html", "json"}, parallelFormat) {
+		return fmt.Errorf("invalid output format: %s (valid: tsv, csv, html, json)", parallelFormat)
+	}
+	if !contains([]string{"inline", "classes"}, cssMode) {
+		return fmt.Errorf("invalid --css value: %s (valid: inline, classes)", cssMode)
+	}
+
+	var scheme *versification.Scheme
+	if parallelScheme != "none" {
+		var err error
+		if scheme, err = loadVersification(parallelScheme, ""); err != nil {
+			return err
+		}
+	} else if len(sourceVersifications) > 0 {
+		return fmt.Errorf("--source-versification requires a --versification other than none")
+	}
+
+	bibles, err := parseInputs(args)
+	if err != nil {
+		return err
+	}
+
+	translations := make([]parallel.Translation, len(bibles))
+	names := make([]string, len(bibles))
+	used := make(map[string]bool)
+	for i, bible := range bibles {
+		own, err := translationVersification(scheme, bible.Name, args[i])
+		if err != nil {
+			return err
+		}
+		translations[i] = parallel.Translation{Name: bible.Name, Documents: bible.Documents, Versification: own}
+		names[i] = bible.Name
+		used[bible.Name] = true
+	}
+	for name := range sourceVersifications {
+		if !used[name] {
+			return fmt.Errorf("--source-versification names no input: %s (inputs: %s)", name, strings.Join(names, ", "))
+		}
+	}
+
+	rows := parallel.Align(translations, scheme)
+	logInfo("Aligned %d translations in %d verses", len(translations), len(rows))
+
+	return streamOutput(func(w io.Writer) error {
+		switch parallelFormat {
+		case "csv":
+			return formatter.WriteParallelCSV(w, names, rows)
+		case "html":
+			return formatter.WriteParallelHTML(w, names, rows, formatter.HTMLOptions{
+				Title:     title,
+				Language:  language,
+				ClassOnly: cssMode == "classes",
+			})
+		case "json":
+			return writeJSONList(w, rows)
+		}
+		return formatter.WriteParallelTSV(w, names, rows)
+	})
+}
+
+// translationVersification returns the scheme a translation is numbered in: its
+// --source-versification or the scheme of the rows, amended by a custom.vrs file in its
+// input directory, or nil if it is numbered like the rows
+func translationVersification(scheme *versification.Scheme, name, inputPath string) (*versification.Scheme, error) {
+	if scheme == nil {
+		return nil, nil
+	}
+
+	custom := filepath.Join(inputPath, "custom.vrs")
+	if info, err := os.Stat(custom); err != nil || info.IsDir() {
+		custom = ""
+	}
+	source, ok := sourceVersifications[name]
+	if !ok && custom == "" {
+		return nil, nil
+	}
+
+	if custom != "" {
+		logInfo("Using custom versification: %s", custom)
+	}
+	if !ok {
+		return customizeVersification(scheme, custom)
+	}
+	return loadVersification(source, custom)
+}
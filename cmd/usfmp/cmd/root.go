@@ -1,14 +1,20 @@
 package cmd
 
 import (
+	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/arenzana/usfmp/internal/formatter"
+	"github.com/arenzana/usfmp/pkg/format"
 	"github.com/arenzana/usfmp/pkg/usfm"
+	"github.com/arenzana/usfmp/pkg/versification"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 var (
@@ -18,6 +24,52 @@ var (
 	verbose      bool
 	quiet        bool
 	strict       bool
+	parseRefs    bool
+	title        string
+	footnotes    bool
+	language     string
+	identifier   string
+	split        bool
+	settings     map[string]string
+
+	// HTML flags
+	cssMode string
+
+	// JSON Lines flags
+	recordType string
+
+	// CSV and TSV flags
+	columns  string
+	noHeader bool
+	tables   bool
+
+	// Parallel corpus flags
+	corpus bool
+
+	// Template flags
+	templateFile string
+	templateHTML bool
+
+	// SSML flags
+	readAloud      string
+	sectionPause   time.Duration
+	paragraphPause time.Duration
+
+	// PDF layout flags
+	pageSize     string
+	margins      string
+	fontSize     float64
+	fontFile     string
+	boldFontFile string
+
+	// Flag sets with the output format flags, whose usage lists the registered formats
+	outputFlagSets []*pflag.FlagSet
+
+	// Format-specific flags, passed to the formatter as settings of the same name
+	settingFlags = []string{
+		"css", "record", "columns", "no-header", "identifier", "template", "template-html",
+		"read", "section-pause", "paragraph-pause", "page-size", "margins", "font-size", "font", "bold-font",
+	}
 
 	// Version information
 	buildVersion = "dev"
@@ -28,13 +80,14 @@ var (
 
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
-	Use:   "usfmp [input-file-or-directory]",
+	Use:   "usfmp [input-file-or-directory...]",
 	Short: "A USFM (Unified Standard Format Marker) parser for biblical texts",
 	Long: `usfmp is a command-line tool for parsing USFM (Unified Standard Format Marker) files.
 It can process single files or entire directories of USFM files and output them in various formats.
+Several inputs are combined into one output, or aligned verse by verse with --corpus.
 
 USFM is a markup format used for biblical texts. More information: https://docs.usfm.bible/usfm/3.1/index.html`,
-	Args: cobra.ExactArgs(1),
+	Args: cobra.MinimumNArgs(1),
 	RunE: run,
 }
 
@@ -51,70 +104,216 @@ func SetVersionInfo(version, commit, date, builtBy string) {
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
+// Output formats registered with format.Register before Execute is called are accepted
+// for --format and listed in the help text.
 func Execute() error {
+	for _, flags := range outputFlagSets {
+		flags.Lookup("format").Usage = formatUsage()
+	}
+	rootCmd.Long = strings.TrimRight(rootCmd.Long, "\n") + "\n\nOutput formats:\n" + formatList()
 	return rootCmd.Execute()
 }
 
-func init() {
-	// Output format flag
-	rootCmd.Flags().StringVarP(&outputFormat, "format", "f", "json",
-		"Output format: json, txt, tsv, pdf")
+// formatList lists the registered formats with their descriptions for the help text
+func formatList() string {
+	var result strings.Builder
+	for _, entry := range format.Entries() {
+		fmt.Fprintf(&result, "  %-10s %s\n", entry.Name, entry.Description)
+	}
+	return result.String()
+}
 
-	// Output file flag
-	rootCmd.Flags().StringVarP(&outputFile, "output", "o", "",
-		"Output file (default: stdout)")
+// formatUsage describes the --format flag with the names of the registered formats
+func formatUsage() string {
+	return "Output format: " + strings.Join(format.Names(), ", ")
+}
 
+func init() {
+	addInputFlags(rootCmd.Flags())
+	addOutputFlags(rootCmd.Flags())
+}
+
+// addInputFlags adds the verbosity and parsing flags shared by the commands that read USFM input
+func addInputFlags(flags *pflag.FlagSet) {
 	// Verbosity flags
-	rootCmd.Flags().BoolVarP(&verbose, "verbose", "v", false,
+	flags.BoolVarP(&verbose, "verbose", "v", false,
 		"Verbose output")
-	rootCmd.Flags().BoolVarP(&quiet, "quiet", "q", false,
+	flags.BoolVarP(&quiet, "quiet", "q", false,
 		"Quiet mode - minimal output")
 
 	// Parsing options
-	rootCmd.Flags().BoolVar(&strict, "strict", false,
+	flags.BoolVar(&strict, "strict", false,
 		"Strict mode - fail on unknown markers")
+	flags.BoolVar(&parseRefs, "parse-references", false,
+		"Parse \\r cross-references and \\fr footnote locations into structured references (json, jsonl)")
+}
+
+// addOutputFlags adds the output format flags shared by the commands that write formatted documents
+func addOutputFlags(flags *pflag.FlagSet) {
+	outputFlagSets = append(outputFlagSets, flags)
+
+	// Output format flag
+	flags.StringVarP(&outputFormat, "format", "f", "json", formatUsage())
+	flags.StringToStringVar(&settings, "set", nil,
+		"Format-specific setting as name=value, for formats registered by other programs (repeatable)")
+
+	// Output file flag
+	flags.StringVarP(&outputFile, "output", "o", "",
+		"Output file, or output directory with --split and for ssml (default: stdout)")
+	flags.BoolVar(&split, "split", false,
+		"Write one file per book (html) or chapter (md) into the --output directory")
+
+	// Output content options
+	flags.StringVar(&title, "title", "",
+		"Bible title for formats that carry translation metadata (zefania, pdf, html, epub, latex, docx)")
+	flags.BoolVar(&footnotes, "footnotes", true,
+		"Include footnotes in formats where they are optional (zefania, html, epub, md, latex, docx)")
+	flags.StringVar(&language, "lang", "",
+		"Language code of the text, e.g. en (html, epub, ssml)")
+	flags.StringVar(&identifier, "identifier", "",
+		"Unique publication identifier such as an ISBN or URN (epub; default: derived from title and books)")
+
+	// HTML options
+	flags.StringVar(&cssMode, "css", "inline",
+		"HTML styling: inline (embedded stylesheet) or classes (class names only)")
+
+	// JSON Lines options
+	flags.StringVar(&recordType, "record", "verse",
+		"JSON Lines record type: verse, footnote, or section (jsonl)")
+
+	// CSV and TSV options
+	flags.StringVar(&columns, "columns", "",
+		"Comma-separated columns to write, in order (csv, tsv): "+strings.Join(formatter.ColumnNames(), ", "))
+	flags.BoolVar(&noHeader, "no-header", false,
+		"Leave out the header row (csv, tsv)")
+	flags.BoolVar(&tables, "tables", false,
+		"Write normalized books, chapters, sections, verses, footnotes, and cross-references tables into the --output directory (csv)")
+
+	// Parallel corpus options
+	flags.BoolVar(&corpus, "corpus", false,
+		"Write vref.txt and one verse-aligned text file per input into the --output directory (vpl)")
+
+	// Template options
+	flags.StringVar(&templateFile, "template", "",
+		"Go text/template file to execute against the parsed documents (template)")
+	flags.BoolVar(&templateHTML, "template-html", false,
+		"Execute the --template file with html/template, escaping text for HTML (template)")
+
+	// SSML options
+	defaultSSML := formatter.DefaultSSMLOptions()
+	flags.StringVar(&readAloud, "read", "",
+		"Comma-separated extras to read aloud (ssml): headings, verse-numbers, footnotes")
+	flags.DurationVar(&sectionPause, "section-pause", defaultSSML.SectionPause,
+		"Pause between sections (ssml)")
+	flags.DurationVar(&paragraphPause, "paragraph-pause", defaultSSML.ParagraphPause,
+		"Pause between paragraphs and poetry lines (ssml)")
+
+	// PDF layout options
+	flags.StringVar(&pageSize, "page-size", "a4",
+		"PDF page size: a4, a5, letter, legal, or WIDTHxHEIGHT (e.g. 6inx9in)")
+	flags.StringVar(&margins, "margins", "0.75in",
+		"PDF page margins: one, two (vertical,horizontal), or four (top,right,bottom,left) lengths")
+	flags.Float64Var(&fontSize, "font-size", 11,
+		"PDF body text size in points")
+	flags.StringVar(&fontFile, "font", "",
+		"TrueType font file to embed in PDF output (needed for non-Latin scripts)")
+	flags.StringVar(&boldFontFile, "bold-font", "",
+		"TrueType font file for PDF headings (default: same as --font)")
 }
 
 // run is the main command execution function
 func run(cmd *cobra.Command, args []string) error {
-	inputPath := args[0]
-
-	// Validate flags
-	if err := validateFlags(); err != nil {
+	// Validate flags and create the formatter, which checks the format-specific flags
+	entry, output, err := newOutputFormatter(cmd.Flags())
+	if err != nil {
 		return err
 	}
 
-	// Configure parser options
-	parseOptions := usfm.ParseOptions{
-		StrictMode:        strict,
-		IncludeFootnotes:  true,
-		IncludeReferences: true,
+	// The corpus output keeps the inputs apart as translations
+	if corpus {
+		bibles, err := parseInputs(args)
+		if err != nil {
+			return err
+		}
+		return outputCorpus(bibles)
 	}
 
-	parser := usfm.NewParser(parseOptions)
-
-	// Check if input is file or directory
-	info, err := os.Stat(inputPath)
+	// Format and output results
+	documents, err := parseDocuments(args)
 	if err != nil {
-		return fmt.Errorf("cannot access input path: %w", err)
+		return err
 	}
+	return outputResults(documents, entry, output)
+}
+
+// parseInputs parses each input path as a translation named after the path
+// and reports books that appear more than once in the same input
+func parseInputs(inputPaths []string) ([]*usfm.Bible, error) {
+	parser := newParser()
 
-	var files []string
-	if info.IsDir() {
-		// Process directory
-		files, err = findUSFMFiles(inputPath)
+	var bibles []*usfm.Bible
+	for _, inputPath := range inputPaths {
+		documents, err := parseInput(parser, inputPath)
 		if err != nil {
-			return fmt.Errorf("error finding USFM files: %w", err)
+			return nil, err
 		}
+		bible, err := usfm.NewBible(translationName(inputPath), documents)
+		if err != nil {
+			return nil, fmt.Errorf("invalid input %s: %w", inputPath, err)
+		}
+		if bible.Title != "" {
+			logInfo("Loaded %s (%s) with %d books", bible.Name, bible.Title, len(bible.BookCodes()))
+		}
+		bibles = append(bibles, bible)
+	}
+	return bibles, nil
+}
+
+// parseDocuments parses the inputs into documents in the order given, for the commands that
+// format them. A book that appears more than once in the same input is written as often as
+// it appears, with a warning.
+func parseDocuments(inputPaths []string) ([]*usfm.Document, error) {
+	parser := newParser()
 
-		if len(files) == 0 {
-			return fmt.Errorf("no USFM files found in directory: %s", inputPath)
+	var documents []*usfm.Document
+	for _, inputPath := range inputPaths {
+		input, err := parseInput(parser, inputPath)
+		if err != nil {
+			return nil, err
 		}
 
-		logInfo("Found %d USFM files", len(files))
-	} else {
-		// Single file
-		files = []string{inputPath}
+		books := make(map[string]*usfm.Document)
+		for _, doc := range input {
+			code := doc.BookCode()
+			if code == "" {
+				continue
+			}
+			if existing, ok := books[code]; ok {
+				logWarning("Duplicate book %s in %s and %s", code, existing.SourceFile, doc.SourceFile)
+				continue
+			}
+			books[code] = doc
+		}
+		documents = append(documents, input...)
+	}
+	return documents, nil
+}
+
+// newParser returns a parser configured by the parsing flags
+func newParser() *usfm.Parser {
+	return usfm.NewParser(usfm.ParseOptions{
+		StrictMode:        strict,
+		IncludeFootnotes:  true,
+		IncludeReferences: true,
+		ParseReferences:   parseRefs,
+	})
+}
+
+// parseInput parses a USFM file, or all USFM files in a directory
+func parseInput(parser *usfm.Parser, inputPath string) ([]*usfm.Document, error) {
+	files, err := inputFiles(inputPath)
+	if err != nil {
+		return nil, err
 	}
 
 	// Parse files
@@ -124,7 +323,7 @@ func run(cmd *cobra.Command, args []string) error {
 
 		f, err := os.Open(file)
 		if err != nil {
-			return fmt.Errorf("error opening file %s: %w", file, err)
+			return nil, fmt.Errorf("error opening file %s: %w", file, err)
 		}
 
 		doc, err := parser.Parse(f, file)
@@ -133,95 +332,241 @@ func run(cmd *cobra.Command, args []string) error {
 		}
 
 		if err != nil {
-			return fmt.Errorf("error parsing file %s: %w", file, err)
+			return nil, fmt.Errorf("error parsing file %s: %w", file, err)
 		}
 
 		documents = append(documents, doc)
 		logInfo("Successfully parsed %s - %s", doc.ID, doc.MainTitle)
 	}
 
-	// Format and output results
-	return outputResults(documents)
+	return documents, nil
+}
+
+// inputFiles returns an input file, or the USFM files in an input directory
+func inputFiles(inputPath string) ([]string, error) {
+	// Check if input is file or directory
+	info, err := os.Stat(inputPath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot access input path: %w", err)
+	}
+	if !info.IsDir() {
+		return []string{inputPath}, nil
+	}
+
+	files, err := usfm.FindFiles(inputPath)
+	if err != nil {
+		return nil, fmt.Errorf("error finding USFM files: %w", err)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no USFM files found in directory: %s", inputPath)
+	}
+
+	logInfo("Found %d USFM files", len(files))
+	return files, nil
+}
+
+// translationName names the translation read from an input path after the file or directory
+// (e.g., "bsb_usfm" for samples/bsb_usfm/)
+func translationName(inputPath string) string {
+	name := filepath.Base(filepath.Clean(inputPath))
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
+
+// newOutputFormatter checks the output flags and creates the formatter for --format
+func newOutputFormatter(flags *pflag.FlagSet) (format.Entry, format.Formatter, error) {
+	entry, err := validateFlags()
+	if err != nil {
+		return format.Entry{}, nil, err
+	}
+
+	output, err := entry.New(formatOptions(flags))
+	if err != nil {
+		return format.Entry{}, nil, fmt.Errorf("invalid options for format %s: %w", outputFormat, err)
+	}
+	return entry, output, nil
 }
 
-// validateFlags checks that flag combinations are valid
-func validateFlags() error {
+// validateFlags checks that flag combinations are valid and returns the entry of the output format
+func validateFlags() (format.Entry, error) {
 	if quiet && verbose {
-		return fmt.Errorf("cannot use both --quiet and --verbose flags")
+		return format.Entry{}, fmt.Errorf("cannot use both --quiet and --verbose flags")
+	}
+
+	entry, ok := format.Lookup(outputFormat)
+	if !ok {
+		return format.Entry{}, fmt.Errorf("invalid output format: %s (valid: %s)",
+			outputFormat, strings.Join(format.Names(), ", "))
 	}
 
-	validFormats := []string{"json", "txt", "tsv", "pdf"}
-	for _, format := range validFormats {
-		if outputFormat == format {
-			return nil
+	for name := range settings {
+		if contains(settingFlags, name) {
+			return format.Entry{}, fmt.Errorf("--set %s: use the --%s flag instead", name, name)
 		}
 	}
 
-	return fmt.Errorf("invalid output format: %s (valid: %s)",
-		outputFormat, strings.Join(validFormats, ", "))
-}
+	if split {
+		splitFormats := []string{"html", "md"}
+		if !contains(splitFormats, outputFormat) {
+			return format.Entry{}, fmt.Errorf("--split is not supported for format %s (supported: %s)",
+				outputFormat, strings.Join(splitFormats, ", "))
+		}
+		if outputFile == "" {
+			return format.Entry{}, fmt.Errorf("--split requires an --output directory")
+		}
+	}
 
-// findUSFMFiles recursively finds USFM files in a directory
-func findUSFMFiles(dir string) ([]string, error) {
-	var files []string
+	if tables {
+		if outputFormat != "csv" {
+			return format.Entry{}, fmt.Errorf("--tables is only supported for format csv")
+		}
+		if split {
+			return format.Entry{}, fmt.Errorf("cannot use both --split and --tables flags")
+		}
+		if outputFile == "" {
+			return format.Entry{}, fmt.Errorf("--tables requires an --output directory")
+		}
+	}
 
-	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
+	if corpus {
+		if outputFormat != "vpl" {
+			return format.Entry{}, fmt.Errorf("--corpus is only supported for format vpl")
+		}
+		if split || tables {
+			return format.Entry{}, fmt.Errorf("cannot use --corpus with --split or --tables")
+		}
+		if outputFile == "" {
+			return format.Entry{}, fmt.Errorf("--corpus requires an --output directory")
 		}
+	}
+
+	if entry.Directory && outputFile == "" {
+		return format.Entry{}, fmt.Errorf("format %s writes several files and requires an --output directory", outputFormat)
+	}
+
+	return entry, nil
+}
 
-		if !info.IsDir() && isUSFMFile(path) {
-			files = append(files, path)
+// contains reports whether a string slice contains a value
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
 		}
+	}
+	return false
+}
 
-		return nil
-	})
+// outputResults formats and outputs the parsed documents with the formatter of a format entry
+func outputResults(documents []*usfm.Document, entry format.Entry, output format.Formatter) error {
+	if split || tables || entry.Directory {
+		fileFormatter, ok := output.(format.FileFormatter)
+		if !ok {
+			return fmt.Errorf("format %s cannot be written as several files", outputFormat)
+		}
+		files, err := fileFormatter.FormatFiles(documents)
+		if err != nil {
+			return fmt.Errorf("error formatting output: %w", err)
+		}
+		return writeFiles(files)
+	}
 
-	return files, err
+	return streamOutput(func(w io.Writer) error {
+		return output.Format(w, documents)
+	})
 }
 
-// isUSFMFile checks if a file is likely a USFM file based on extension
-func isUSFMFile(filename string) bool {
-	ext := strings.ToLower(filepath.Ext(filename))
-	return ext == ".sfm" || ext == ".usfm"
+// formatOptions builds the formatter options from the command line flags.
+// The format-specific flags are passed to the formatter as settings of the same name
+// (e.g., "page-size"), together with the settings given with --set.
+func formatOptions(flags *pflag.FlagSet) format.Options {
+	options := format.Options{
+		Title:            title,
+		Language:         language,
+		IncludeFootnotes: footnotes,
+		Settings:         make(map[string]string),
+	}
+
+	for _, name := range settingFlags {
+		if flag := flags.Lookup(name); flag != nil {
+			options.Settings[name] = flag.Value.String()
+		}
+	}
+	for name, value := range settings {
+		options.Settings[name] = value
+	}
+
+	return options
 }
 
-// outputResults formats and outputs the parsed documents
-func outputResults(documents []*usfm.Document) error {
-	var output string
-	var err error
+// streamOutput runs a streaming formatter that writes directly to the output file or stdout
+func streamOutput(write func(w io.Writer) error) error {
+	if outputFile == "" {
+		buffered := bufio.NewWriter(os.Stdout)
+		if err := write(buffered); err != nil {
+			return fmt.Errorf("error writing output: %w", err)
+		}
+		if err := buffered.Flush(); err != nil {
+			return fmt.Errorf("error writing output: %w", err)
+		}
+		return nil
+	}
 
-	switch outputFormat {
-	case "json":
-		output, err = formatter.FormatJSON(documents)
-	case "txt":
-		output, err = formatter.FormatText(documents)
-	case "tsv":
-		output, err = formatter.FormatTSV(documents)
-	case "pdf":
-		return fmt.Errorf("PDF output not yet implemented")
-	default:
-		return fmt.Errorf("unsupported output format: %s", outputFormat)
+	file, err := os.Create(outputFile)
+	if err != nil {
+		return fmt.Errorf("error creating output file: %w", err)
+	}
+	buffered := bufio.NewWriter(file)
+	err = write(buffered)
+	if err == nil {
+		err = buffered.Flush()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		// Don't leave a truncated output file behind
+		os.Remove(outputFile)
+		return fmt.Errorf("error writing output: %w", err)
 	}
 
+	logInfo("Output written to: %s", outputFile)
+	return nil
+}
+
+// outputCorpus writes the parsed translations as a verse-aligned parallel corpus into the output directory
+func outputCorpus(bibles []*usfm.Bible) error {
+	corpus, err := formatter.FormatCorpus(bibles, versification.English)
 	if err != nil {
 		return fmt.Errorf("error formatting output: %w", err)
 	}
+	files := make([]format.File, len(corpus))
+	for i, file := range corpus {
+		files[i] = format.File(file)
+	}
+	return writeFiles(files)
+}
 
-	// Write output
-	if outputFile != "" {
-		err = os.WriteFile(outputFile, []byte(output), 0644)
-		if err != nil {
+// writeFiles writes the files of a multi-file output into the output directory
+func writeFiles(files []format.File) error {
+	for _, file := range files {
+		path := filepath.Join(outputFile, filepath.FromSlash(file.Name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("error creating output directory: %w", err)
+		}
+		if err := os.WriteFile(path, file.Data, 0644); err != nil {
 			return fmt.Errorf("error writing output file: %w", err)
 		}
-		logInfo("Output written to: %s", outputFile)
-	} else {
-		fmt.Print(output)
 	}
+	logInfo("Wrote %d files to: %s", len(files), outputFile)
 
 	return nil
 }
 
+// logWarning prints warnings, which are shown in quiet mode too
+func logWarning(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "[WARN] "+format+"\n", args...)
+}
+
 // logInfo prints informational messages unless in quiet mode
 func logInfo(format string, args ...interface{}) {
 	if !quiet {
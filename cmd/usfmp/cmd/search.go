@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+
+	"github.com/arenzana/usfmp/pkg/ref"
+	"github.com/arenzana/usfmp/pkg/search"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Search flags
+	searchIn     string
+	searchIndex  string
+	searchLimit  int
+	snippetWidth int
+	searchColor  string
+)
+
+// searchCmd finds verses by the words of their text
+var searchCmd = &cobra.Command{
+	Use:   "search QUERY [input-file-or-directory...]",
+	Short: "Find verses by their words, such as \"living water\"",
+	Long: `search finds the verses whose text matches a query and prints their references with
+the matching words highlighted. Verse text is searched after markup and footnotes are removed,
+without regard to case or diacritics.
+
+Words separated by spaces must all appear in a verse. Quoted words ("living water") must appear
+together in order, and a word ending in "*" matches any word that starts with it. OR, AND, and NOT
+(in capitals) combine words and phrases, "-" before a word or phrase excludes it, and parentheses
+group them.
+
+With --index FILE, the index built from the inputs is saved to FILE, and later searches can read
+FILE instead of parsing the inputs again.`,
+	Example: `  usfmp search "living water" samples/bsb_usfm
+  usfmp search '"living water" OR "springs of water"' samples/bsb_usfm
+  usfmp search --in 'Gen-Deut' 'shepherd* -sheep' samples/bsb_usfm
+  usfmp search --index bsb.idx love samples/bsb_usfm
+  usfmp search --index bsb.idx '(lamb OR sheep) AND NOT goat'`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runSearch,
+}
+
+func init() {
+	addInputFlags(searchCmd.Flags())
+
+	searchCmd.Flags().StringVar(&searchIn, "in", "",
+		"Only search these books or passages (e.g., \"John\", \"Gen-Deut\", \"Ps 1-50; Prov\")")
+	searchCmd.Flags().StringVar(&searchIndex, "index", "",
+		"Index file: saved after indexing the inputs, or searched when no inputs are given")
+	searchCmd.Flags().IntVarP(&searchLimit, "limit", "n", 0,
+		"Maximum number of verses to print (0 for all)")
+	searchCmd.Flags().IntVar(&snippetWidth, "width", 120,
+		"Maximum snippet length in bytes (0 for the whole verse)")
+	searchCmd.Flags().StringVar(&searchColor, "color", "auto",
+		"Highlight matches with terminal colors: auto, always, never")
+
+	rootCmd.AddCommand(searchCmd)
+}
+
+// runSearch builds or loads the index and prints the verses that match the query
+func runSearch(cmd *cobra.Command, args []string) error {
+	if quiet && verbose {
+		return fmt.Errorf("cannot use both --quiet and --verbose flags")
+	}
+
+	before, after, err := highlightMarkers(searchColor)
+	if err != nil {
+		return err
+	}
+
+	var options search.Options
+	if searchIn != "" {
+		if options.Ranges, err = ref.Parse(searchIn); err != nil {
+			return fmt.Errorf("invalid --in: %w", err)
+		}
+	}
+
+	index, err := searchIndexFor(args[1:])
+	if err != nil {
+		return err
+	}
+
+	results, err := index.Search(args[0], options)
+	if err != nil {
+		return fmt.Errorf("invalid query: %w", err)
+	}
+	logInfo("Found %d verses", len(results))
+	if searchLimit > 0 && len(results) > searchLimit {
+		logInfo("Showing the first %d", searchLimit)
+		results = results[:searchLimit]
+	}
+
+	// Name the translation of each verse when several were searched
+	translations := len(index.Translations()) > 1
+
+	w := bufio.NewWriter(cmd.OutOrStdout())
+	for _, result := range results {
+		reference := result.Range.String()
+		if translations {
+			reference += " (" + result.Translation + ")"
+		}
+		fmt.Fprintf(w, "%s\t%s\n", reference, result.Snippet(snippetWidth, before, after))
+	}
+	return w.Flush()
+}
+
+// searchIndexFor indexes the inputs and saves the index to --index, or loads the
+// index from --index when there are no inputs
+func searchIndexFor(inputPaths []string) (*search.Index, error) {
+	if len(inputPaths) == 0 {
+		if searchIndex == "" {
+			return nil, fmt.Errorf("no input files: give USFM files or directories, or an --index file")
+		}
+
+		f, err := os.Open(searchIndex)
+		if err != nil {
+			return nil, fmt.Errorf("cannot open index: %w", err)
+		}
+		defer f.Close()
+
+		index, err := search.Load(bufio.NewReader(f))
+		if err != nil {
+			return nil, err
+		}
+		logInfo("Loaded index of %d verses from %s", index.Len(), searchIndex)
+		return index, nil
+	}
+
+	bibles, err := parseInputs(inputPaths)
+	if err != nil {
+		return nil, err
+	}
+	index := search.Build(bibles...)
+	logInfo("Indexed %d verses", index.Len())
+
+	if searchIndex != "" {
+		if err := saveIndex(index, searchIndex); err != nil {
+			return nil, err
+		}
+		logInfo("Index written to: %s", searchIndex)
+	}
+	return index, nil
+}
+
+// saveIndex writes the index to a file
+func saveIndex(index *search.Index, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("error creating index file: %w", err)
+	}
+
+	w := bufio.NewWriter(f)
+	if err := index.Save(w); err != nil {
+		f.Close()
+		return err
+	}
+	err = w.Flush()
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("error writing index file: %w", err)
+	}
+	return nil
+}
+
+// highlightMarkers returns the text written around matched words: bold yellow on a terminal,
+// or "**" when the output is redirected
+func highlightMarkers(color string) (string, string, error) {
+	switch color {
+	case "always":
+		return "\033[1;33m", "\033[0m", nil
+	case "never":
+		return "**", "**", nil
+	case "auto":
+		if info, err := os.Stdout.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+			return "\033[1;33m", "\033[0m", nil
+		}
+		return "**", "**", nil
+	}
+	return "", "", fmt.Errorf("invalid --color: %s (valid: auto, always, never)", color)
+}
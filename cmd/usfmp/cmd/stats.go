@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"unicode/utf8"
+
+	"github.com/arenzana/usfmp/pkg/ref"
+	"github.com/arenzana/usfmp/pkg/stats"
+	"github.com/arenzana/usfmp/pkg/usfm"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Stats flags
+	statsFormat   string
+	statsChapters bool
+)
+
+// statsCmd reports statistics of the parsed documents
+var statsCmd = &cobra.Command{
+	Use:   "stats input-file-or-directory...",
+	Short: "Count verses, words, footnotes, and more per book and chapter",
+	Long: `stats parses the input and reports, per book and in total: chapters, verses, words, characters,
+different words, average words per verse, footnotes, cross-references, section headings, and the
+longest and shortest verses. With --chapters, every chapter is reported as well.
+
+Words are counted in verse text without markup or footnotes; different words are counted
+regardless of case and diacritics.`,
+	Example: `  usfmp stats samples/bsb_usfm
+  usfmp stats --chapters samples/bsb_usfm/19PSABSB.SFM
+  usfmp stats -f tsv -o stats.tsv samples/bsb_usfm
+  usfmp stats -f json samples/eng-kjv_usfm | jq '.total'`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runStats,
+}
+
+func init() {
+	addInputFlags(statsCmd.Flags())
+
+	statsCmd.Flags().StringVarP(&statsFormat, "format", "f", "txt",
+		"Output format: txt, tsv, json")
+	statsCmd.Flags().StringVarP(&outputFile, "output", "o", "",
+		"Output file (default: stdout)")
+	statsCmd.Flags().BoolVar(&statsChapters, "chapters", false,
+		"Report every chapter as well as every book (txt, tsv; json always includes chapters)")
+
+	rootCmd.AddCommand(statsCmd)
+}
+
+// runStats parses the inputs and writes their statistics
+func runStats(cmd *cobra.Command, args []string) error {
+	if quiet && verbose {
+		return fmt.Errorf("cannot use both --quiet and --verbose flags")
+	}
+	if !contains([]string{"txt", "tsv", "json"}, statsFormat) {
+		return fmt.Errorf("invalid output format: %s (valid: txt, tsv, json)", statsFormat)
+	}
+
+	bibles, err := parseInputs(args)
+	if err != nil {
+		return err
+	}
+	var documents []*usfm.Document
+	for _, bible := range bibles {
+		documents = append(documents, bible.Documents...)
+	}
+
+	report := stats.Compute(documents)
+	return streamOutput(func(w io.Writer) error {
+		switch statsFormat {
+		case "json":
+			encoder := json.NewEncoder(w)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(report)
+		case "tsv":
+			return writeStatsTable(w, report, false)
+		}
+
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+		if err := writeStatsTable(tw, report, true); err != nil {
+			return err
+		}
+		return tw.Flush()
+	})
+}
+
+// statsColumns are the columns of the stats table
+var statsColumns = []string{
+	"name", "book", "chapter", "chapters", "verses", "words", "characters", "unique_words",
+	"average_verse_words", "footnotes", "cross_references", "sections",
+	"longest_verse", "longest_words", "shortest_verse", "shortest_words",
+}
+
+// writeStatsTable writes one row per book (and per chapter with --chapters) and a total row.
+// The text layout leaves out the book and chapter columns, which repeat the name, pads the
+// names to keep them left-aligned, and ends each cell with a tab for the tabwriter to align.
+func writeStatsTable(w io.Writer, report *stats.Report, text bool) error {
+	columns, end := statsColumns, "\n"
+	nameWidth := 0
+	if text {
+		columns, end = append([]string{"name"}, statsColumns[3:]...), "\t\n"
+		for _, book := range report.Books {
+			nameWidth = max(nameWidth, utf8.RuneCountInString(book.Name)+4) // room for " 150"
+		}
+		columns[0] = fmt.Sprintf("%-*s", nameWidth, columns[0])
+	}
+	if _, err := io.WriteString(w, strings.Join(columns, "\t")+end); err != nil {
+		return err
+	}
+
+	writeRow := func(s stats.Stats) error {
+		fields := []string{
+			s.Name, s.Book, optionalCount(s.Chapter), optionalCount(s.Chapters),
+			strconv.Itoa(s.Verses), strconv.Itoa(s.Words), strconv.Itoa(s.Characters), strconv.Itoa(s.UniqueWords),
+			strconv.FormatFloat(s.AverageVerseWords, 'f', 1, 64),
+			strconv.Itoa(s.Footnotes), strconv.Itoa(s.CrossReferences), strconv.Itoa(s.Sections),
+		}
+		fields = append(fields, verseLengthFields(s.Longest)...)
+		fields = append(fields, verseLengthFields(s.Shortest)...)
+		if text {
+			// Pad the name so that it stays left-aligned in the right-aligned table
+			fields = append([]string{fmt.Sprintf("%-*s", nameWidth, s.Name)}, fields[3:]...)
+		}
+		_, err := io.WriteString(w, strings.Join(fields, "\t")+end)
+		return err
+	}
+
+	for _, book := range report.Books {
+		if err := writeRow(book.Stats); err != nil {
+			return err
+		}
+		if statsChapters {
+			for _, chapter := range book.ChapterStats {
+				if err := writeRow(chapter); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return writeRow(report.Total)
+}
+
+// verseLengthFields returns the reference and word count of a verse, or empty fields
+func verseLengthFields(length *stats.VerseLength) []string {
+	if length == nil {
+		return []string{"", ""}
+	}
+	return []string{ref.Format([]ref.Range{length.Reference}, ref.USFM), strconv.Itoa(length.Words)}
+}
+
+// optionalCount formats a count that only some rows have, leaving the field empty for zero
+func optionalCount(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
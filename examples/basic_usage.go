@@ -193,7 +193,7 @@ func demonstrateFormats(doc *usfm.Document) {
 
 	// TSV format (first 3 lines)
 	fmt.Println("TSV Format (first 3 lines):")
-	tsvOutput, err := formatter.FormatTSV(documents)
+	tsvOutput, err := formatter.FormatTSV(documents, formatter.TableOptions{})
 	if err != nil {
 		fmt.Printf("Error formatting TSV: %v\n", err)
 	} else {
@@ -0,0 +1,131 @@
+// Package canon holds the canonical book registry shared by the usfm and ref packages.
+// It is exposed publicly as usfm.Book, usfm.Books, and usfm.LookupBook.
+package canon
+
+import "strings"
+
+// Book describes a canonical book of the Bible as identified by its USFM code.
+// Books are listed in canonical order, starting with the Old Testament,
+// followed by the New Testament and the deuterocanonical books.
+type Book struct {
+	Number    int    `json:"number"`    // Canonical book number (1-66 for the Protestant canon, 67+ for deuterocanonical books)
+	Code      string `json:"code"`      // Three-character USFM book code (e.g., "GEN")
+	Name      string `json:"name"`      // English book name (e.g., "Genesis")
+	OSIS      string `json:"osis"`      // OSIS book identifier (e.g., "Gen")
+	Testament string `json:"testament"` // "OT", "NT", or "DC" for deuterocanonical books
+}
+
+// books holds the canonical book registry in canonical order.
+var books = []Book{
+	{1, "GEN", "Genesis", "Gen", "OT"},
+	{2, "EXO", "Exodus", "Exod", "OT"},
+	{3, "LEV", "Leviticus", "Lev", "OT"},
+	{4, "NUM", "Numbers", "Num", "OT"},
+	{5, "DEU", "Deuteronomy", "Deut", "OT"},
+	{6, "JOS", "Joshua", "Josh", "OT"},
+	{7, "JDG", "Judges", "Judg", "OT"},
+	{8, "RUT", "Ruth", "Ruth", "OT"},
+	{9, "1SA", "1 Samuel", "1Sam", "OT"},
+	{10, "2SA", "2 Samuel", "2Sam", "OT"},
+	{11, "1KI", "1 Kings", "1Kgs", "OT"},
+	{12, "2KI", "2 Kings", "2Kgs", "OT"},
+	{13, "1CH", "1 Chronicles", "1Chr", "OT"},
+	{14, "2CH", "2 Chronicles", "2Chr", "OT"},
+	{15, "EZR", "Ezra", "Ezra", "OT"},
+	{16, "NEH", "Nehemiah", "Neh", "OT"},
+	{17, "EST", "Esther", "Esth", "OT"},
+	{18, "JOB", "Job", "Job", "OT"},
+	{19, "PSA", "Psalms", "Ps", "OT"},
+	{20, "PRO", "Proverbs", "Prov", "OT"},
+	{21, "ECC", "Ecclesiastes", "Eccl", "OT"},
+	{22, "SNG", "Song of Songs", "Song", "OT"},
+	{23, "ISA", "Isaiah", "Isa", "OT"},
+	{24, "JER", "Jeremiah", "Jer", "OT"},
+	{25, "LAM", "Lamentations", "Lam", "OT"},
+	{26, "EZK", "Ezekiel", "Ezek", "OT"},
+	{27, "DAN", "Daniel", "Dan", "OT"},
+	{28, "HOS", "Hosea", "Hos", "OT"},
+	{29, "JOL", "Joel", "Joel", "OT"},
+	{30, "AMO", "Amos", "Amos", "OT"},
+	{31, "OBA", "Obadiah", "Obad", "OT"},
+	{32, "JON", "Jonah", "Jonah", "OT"},
+	{33, "MIC", "Micah", "Mic", "OT"},
+	{34, "NAM", "Nahum", "Nah", "OT"},
+	{35, "HAB", "Habakkuk", "Hab", "OT"},
+	{36, "ZEP", "Zephaniah", "Zeph", "OT"},
+	{37, "HAG", "Haggai", "Hag", "OT"},
+	{38, "ZEC", "Zechariah", "Zech", "OT"},
+	{39, "MAL", "Malachi", "Mal", "OT"},
+	{40, "MAT", "Matthew", "Matt", "NT"},
+	{41, "MRK", "Mark", "Mark", "NT"},
+	{42, "LUK", "Luke", "Luke", "NT"},
+	{43, "JHN", "John", "John", "NT"},
+	{44, "ACT", "Acts", "Acts", "NT"},
+	{45, "ROM", "Romans", "Rom", "NT"},
+	{46, "1CO", "1 Corinthians", "1Cor", "NT"},
+	{47, "2CO", "2 Corinthians", "2Cor", "NT"},
+	{48, "GAL", "Galatians", "Gal", "NT"},
+	{49, "EPH", "Ephesians", "Eph", "NT"},
+	{50, "PHP", "Philippians", "Phil", "NT"},
+	{51, "COL", "Colossians", "Col", "NT"},
+	{52, "1TH", "1 Thessalonians", "1Thess", "NT"},
+	{53, "2TH", "2 Thessalonians", "2Thess", "NT"},
+	{54, "1TI", "1 Timothy", "1Tim", "NT"},
+	{55, "2TI", "2 Timothy", "2Tim", "NT"},
+	{56, "TIT", "Titus", "Titus", "NT"},
+	{57, "PHM", "Philemon", "Phlm", "NT"},
+	{58, "HEB", "Hebrews", "Heb", "NT"},
+	{59, "JAS", "James", "Jas", "NT"},
+	{60, "1PE", "1 Peter", "1Pet", "NT"},
+	{61, "2PE", "2 Peter", "2Pet", "NT"},
+	{62, "1JN", "1 John", "1John", "NT"},
+	{63, "2JN", "2 John", "2John", "NT"},
+	{64, "3JN", "3 John", "3John", "NT"},
+	{65, "JUD", "Jude", "Jude", "NT"},
+	{66, "REV", "Revelation", "Rev", "NT"},
+	{67, "TOB", "Tobit", "Tob", "DC"},
+	{68, "JDT", "Judith", "Jdt", "DC"},
+	{69, "ESG", "Esther (Greek)", "EsthGr", "DC"},
+	{70, "WIS", "Wisdom of Solomon", "Wis", "DC"},
+	{71, "SIR", "Sirach", "Sir", "DC"},
+	{72, "BAR", "Baruch", "Bar", "DC"},
+	{73, "LJE", "Letter of Jeremiah", "EpJer", "DC"},
+	{74, "S3Y", "Song of the Three Young Men", "PrAzar", "DC"},
+	{75, "SUS", "Susanna", "Sus", "DC"},
+	{76, "BEL", "Bel and the Dragon", "Bel", "DC"},
+	{77, "1MA", "1 Maccabees", "1Macc", "DC"},
+	{78, "2MA", "2 Maccabees", "2Macc", "DC"},
+	{79, "3MA", "3 Maccabees", "3Macc", "DC"},
+	{80, "4MA", "4 Maccabees", "4Macc", "DC"},
+	{81, "1ES", "1 Esdras", "1Esd", "DC"},
+	{82, "2ES", "2 Esdras", "2Esd", "DC"},
+	{83, "MAN", "Prayer of Manasseh", "PrMan", "DC"},
+	{84, "PS2", "Psalm 151", "AddPs", "DC"},
+}
+
+// bookIndex maps upper-case USFM book codes to their position in books.
+var bookIndex = func() map[string]int {
+	index := make(map[string]int, len(books))
+	for i, book := range books {
+		index[book.Code] = i
+	}
+	return index
+}()
+
+// Books returns the canonical book registry in canonical order.
+// The returned slice is a copy and may be modified by the caller.
+func Books() []Book {
+	result := make([]Book, len(books))
+	copy(result, books)
+	return result
+}
+
+// Lookup returns the registry entry for a USFM book code such as "GEN" or "1KI".
+// The lookup is case-insensitive. The second return value reports whether the code is known.
+func Lookup(code string) (Book, bool) {
+	i, ok := bookIndex[strings.ToUpper(strings.TrimSpace(code))]
+	if !ok {
+		return Book{}, false
+	}
+	return books[i], true
+}
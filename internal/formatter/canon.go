@@ -0,0 +1,59 @@
+package formatter
+
+import (
+	"sort"
+
+	"github.com/arenzana/usfmp/pkg/usfm"
+)
+
+// canonicalBook pairs a parsed document with its entry in the canonical book registry.
+type canonicalBook struct {
+	Book     usfm.Book
+	Document *usfm.Document
+}
+
+// canonicalBooks returns the documents that belong to a known canonical book,
+// sorted in canonical order. Documents without a recognized book code
+// (front matter, glossaries, etc.) are left out because formats keyed
+// by book number cannot represent them, and so are later copies of a book.
+func canonicalBooks(documents []*usfm.Document) []canonicalBook {
+	var result []canonicalBook
+	for _, doc := range firstBooks(documents) {
+		if book, ok := usfm.LookupBook(doc.BookCode()); ok {
+			result = append(result, canonicalBook{Book: book, Document: doc})
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Book.Number < result[j].Book.Number
+	})
+
+	return result
+}
+
+// bookTitle returns the display title of a document: its main title,
+// then its running header, then its book code
+func bookTitle(doc *usfm.Document) string {
+	if doc.MainTitle != "" {
+		return doc.MainTitle
+	}
+	if doc.Header != "" {
+		return doc.Header
+	}
+	return doc.BookCode()
+}
+
+// firstBooks returns the documents without the later copies of a book that occurs more than once
+func firstBooks(documents []*usfm.Document) []*usfm.Document {
+	var result []*usfm.Document
+	seen := make(map[string]bool)
+	for _, doc := range documents {
+		code := doc.BookCode()
+		if code != "" && seen[code] {
+			continue
+		}
+		seen[code] = true
+		result = append(result, doc)
+	}
+	return result
+}
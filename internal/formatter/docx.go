@@ -0,0 +1,329 @@
+package formatter
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/arenzana/usfmp/pkg/usfm"
+)
+
+// DOCXOptions configures the DOCX output.
+type DOCXOptions struct {
+	Title            string // Document title written as a title paragraph and to the document properties
+	IncludeFootnotes bool   // Whether to write footnotes as Word footnotes
+}
+
+// docxNamespace is the WordprocessingML main namespace
+const docxNamespace = "http://schemas.openxmlformats.org/wordprocessingml/2006/main"
+
+// docxContentTypes declares the content type of every part in the package
+const docxContentTypes = xmlHeader + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+  <Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+  <Default Extension="xml" ContentType="application/xml"/>
+  <Override PartName="/word/document.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"/>
+  <Override PartName="/word/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.styles+xml"/>
+  <Override PartName="/word/settings.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.settings+xml"/>
+  <Override PartName="/word/footnotes.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.footnotes+xml"/>
+  <Override PartName="/docProps/core.xml" ContentType="application/vnd.openxmlformats-package.core-properties+xml"/>
+</Types>
+`
+
+// docxPackageRelationships points at the main document and the document properties
+const docxPackageRelationships = xmlHeader + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+  <Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="word/document.xml"/>
+  <Relationship Id="rId2" Type="http://schemas.openxmlformats.org/package/2006/relationships/metadata/core-properties" Target="docProps/core.xml"/>
+</Relationships>
+`
+
+// docxDocumentRelationships points the main document at its styles, settings, and footnotes
+const docxDocumentRelationships = xmlHeader + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+  <Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>
+  <Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/settings" Target="settings.xml"/>
+  <Relationship Id="rId3" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/footnotes" Target="footnotes.xml"/>
+</Relationships>
+`
+
+// docxSettings refers to the footnote separators that Word expects in footnotes.xml
+const docxSettings = xmlHeader + `<w:settings xmlns:w="` + docxNamespace + `">
+  <w:footnotePr>
+    <w:footnote w:id="-1"/>
+    <w:footnote w:id="0"/>
+  </w:footnotePr>
+</w:settings>
+`
+
+// docxStyles defines the paragraph and character styles used by the DOCX output.
+// Reviewers can restyle the document by modifying these styles in Word.
+const docxStyles = xmlHeader + `<w:styles xmlns:w="` + docxNamespace + `">
+  <w:docDefaults>
+    <w:rPrDefault><w:rPr><w:sz w:val="22"/><w:szCs w:val="22"/><w:lang w:val="en-US"/></w:rPr></w:rPrDefault>
+    <w:pPrDefault><w:pPr><w:spacing w:after="0" w:line="276" w:lineRule="auto"/></w:pPr></w:pPrDefault>
+  </w:docDefaults>
+  <w:style w:type="paragraph" w:default="1" w:styleId="Normal"><w:name w:val="Normal"/><w:qFormat/></w:style>
+  <w:style w:type="paragraph" w:styleId="Title"><w:name w:val="Title"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/><w:qFormat/>
+    <w:pPr><w:spacing w:before="2400" w:after="480"/><w:jc w:val="center"/></w:pPr><w:rPr><w:b/><w:sz w:val="56"/></w:rPr></w:style>
+  <w:style w:type="paragraph" w:styleId="Heading1"><w:name w:val="heading 1"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/><w:qFormat/>
+    <w:pPr><w:keepNext/><w:pageBreakBefore/><w:spacing w:before="240" w:after="360"/><w:jc w:val="center"/><w:outlineLvl w:val="0"/></w:pPr><w:rPr><w:b/><w:sz w:val="40"/></w:rPr></w:style>
+  <w:style w:type="paragraph" w:styleId="Heading2"><w:name w:val="heading 2"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/><w:qFormat/>
+    <w:pPr><w:keepNext/><w:spacing w:before="360" w:after="120"/><w:outlineLvl w:val="1"/></w:pPr><w:rPr><w:b/><w:sz w:val="30"/></w:rPr></w:style>
+  <w:style w:type="paragraph" w:styleId="Heading3"><w:name w:val="heading 3"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/><w:qFormat/>
+    <w:pPr><w:keepNext/><w:spacing w:before="240" w:after="60"/><w:outlineLvl w:val="2"/></w:pPr><w:rPr><w:b/><w:sz w:val="24"/></w:rPr></w:style>
+  <w:style w:type="paragraph" w:styleId="Heading4"><w:name w:val="heading 4"/><w:basedOn w:val="Heading3"/><w:next w:val="Normal"/><w:qFormat/>
+    <w:pPr><w:outlineLvl w:val="3"/></w:pPr><w:rPr><w:sz w:val="22"/></w:rPr></w:style>
+  <w:style w:type="paragraph" w:styleId="Heading5"><w:name w:val="heading 5"/><w:basedOn w:val="Heading4"/><w:next w:val="Normal"/><w:qFormat/>
+    <w:pPr><w:outlineLvl w:val="4"/></w:pPr><w:rPr><w:b w:val="0"/><w:i/></w:rPr></w:style>
+  <w:style w:type="paragraph" w:styleId="CrossReference"><w:name w:val="Cross Reference"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/>
+    <w:pPr><w:keepNext/><w:spacing w:after="120"/></w:pPr><w:rPr><w:i/><w:sz w:val="20"/></w:rPr></w:style>
+  <w:style w:type="paragraph" w:styleId="Paragraph"><w:name w:val="Paragraph"/><w:basedOn w:val="Normal"/>
+    <w:pPr><w:ind w:firstLine="360"/></w:pPr></w:style>
+  <w:style w:type="paragraph" w:styleId="ParagraphIndented1"><w:name w:val="Paragraph Indented 1"/><w:basedOn w:val="Paragraph"/>
+    <w:pPr><w:ind w:left="360"/></w:pPr></w:style>
+  <w:style w:type="paragraph" w:styleId="ParagraphIndented2"><w:name w:val="Paragraph Indented 2"/><w:basedOn w:val="Paragraph"/>
+    <w:pPr><w:ind w:left="720"/></w:pPr></w:style>
+  <w:style w:type="paragraph" w:styleId="ParagraphIndented3"><w:name w:val="Paragraph Indented 3"/><w:basedOn w:val="Paragraph"/>
+    <w:pPr><w:ind w:left="1080"/></w:pPr></w:style>
+  <w:style w:type="paragraph" w:styleId="Poetry1"><w:name w:val="Poetry 1"/><w:basedOn w:val="Normal"/>
+    <w:pPr><w:ind w:left="720" w:hanging="360"/></w:pPr></w:style>
+  <w:style w:type="paragraph" w:styleId="Poetry2"><w:name w:val="Poetry 2"/><w:basedOn w:val="Normal"/>
+    <w:pPr><w:ind w:left="1080" w:hanging="360"/></w:pPr></w:style>
+  <w:style w:type="paragraph" w:styleId="Poetry3"><w:name w:val="Poetry 3"/><w:basedOn w:val="Normal"/>
+    <w:pPr><w:ind w:left="1440" w:hanging="360"/></w:pPr></w:style>
+  <w:style w:type="paragraph" w:styleId="Poetry4"><w:name w:val="Poetry 4"/><w:basedOn w:val="Normal"/>
+    <w:pPr><w:ind w:left="1800" w:hanging="360"/></w:pPr></w:style>
+  <w:style w:type="paragraph" w:styleId="FootnoteText"><w:name w:val="footnote text"/><w:basedOn w:val="Normal"/>
+    <w:rPr><w:sz w:val="18"/></w:rPr></w:style>
+  <w:style w:type="character" w:default="1" w:styleId="DefaultParagraphFont"><w:name w:val="Default Paragraph Font"/></w:style>
+  <w:style w:type="character" w:styleId="FootnoteReference"><w:name w:val="footnote reference"/>
+    <w:rPr><w:vertAlign w:val="superscript"/></w:rPr></w:style>
+  <w:style w:type="character" w:styleId="VerseNumber"><w:name w:val="Verse Number"/>
+    <w:rPr><w:color w:val="666666"/><w:vertAlign w:val="superscript"/></w:rPr></w:style>
+  <w:style w:type="character" w:styleId="WordsOfJesus"><w:name w:val="Words of Jesus"/>
+    <w:rPr><w:color w:val="C00000"/></w:rPr></w:style>
+</w:styles>
+`
+
+// FormatDOCX formats USFM documents as an Office Open XML (Word) document.
+//
+// The DOCX output uses:
+//   - "Heading 1" for books (starting on a new page), "Heading 2" for chapters,
+//     and "Heading 3" to "Heading 5" for \s1 to \s3 section headings
+//   - A "Cross Reference" paragraph style for \r references
+//   - "Paragraph", "Paragraph Indented N", and "Poetry N" paragraph styles for \p, \pi, and \q lines
+//   - A superscript "Verse Number" character style
+//   - Word footnotes for verse footnotes, so reviewers can comment on them like any other note
+//   - A red "Words of Jesus" character style for \wj, small caps for \nd, and italics for \add
+//
+// Heading styles are recognized by Word's navigation pane, so reviewers can jump to any chapter.
+func FormatDOCX(documents []*usfm.Document, options DOCXOptions) ([]byte, error) {
+	var result bytes.Buffer
+	if err := WriteDOCX(&result, documents, options); err != nil {
+		return nil, err
+	}
+	return result.Bytes(), nil
+}
+
+// WriteDOCX writes USFM documents as a Word document to w, as described for FormatDOCX.
+// The main document part is written into the archive one chapter at a time; footnotes
+// are collected and written after it.
+func WriteDOCX(w io.Writer, documents []*usfm.Document, options DOCXOptions) error {
+	title := options.Title
+	if title == "" && len(documents) > 0 {
+		title = bookTitle(documents[0])
+	}
+
+	archive := newZipWriter(w, time.Now())
+	if err := archive.add(
+		zipFile{"[Content_Types].xml", docxContentTypes, zip.Deflate},
+		zipFile{"_rels/.rels", docxPackageRelationships, zip.Deflate},
+		zipFile{"docProps/core.xml", docxCoreProperties(title), zip.Deflate},
+		zipFile{"word/_rels/document.xml.rels", docxDocumentRelationships, zip.Deflate},
+	); err != nil {
+		return fmt.Errorf("failed to write DOCX archive: %w", err)
+	}
+	document, err := archive.create("word/document.xml", zip.Deflate)
+	if err != nil {
+		return fmt.Errorf("failed to write DOCX archive: %w", err)
+	}
+
+	var body, notes strings.Builder
+	noteID := 0
+
+	body.WriteString(docxDocumentStart)
+	if options.Title != "" {
+		writeDOCXParagraph(&body, "Title", docxRun("", escapeXML(options.Title)))
+	}
+
+	for _, doc := range documents {
+		writeDOCXParagraph(&body, "Heading1", docxRun("", escapeXML(bookTitle(doc))))
+
+		for _, chapter := range doc.Chapters {
+			writeDOCXParagraph(&body, "Heading2", docxRun("", fmt.Sprintf("Chapter %d", chapter.Number)))
+
+			for _, section := range chapter.Sections {
+				if section.Title != "" {
+					level := section.Level
+					if level < 1 || level > 3 {
+						level = 1
+					}
+					writeDOCXParagraph(&body, fmt.Sprintf("Heading%d", level+2), docxInline(section.Title))
+				}
+				if section.Reference != "" {
+					writeDOCXParagraph(&body, "CrossReference", docxRun("", escapeXML(section.Reference)))
+				}
+
+				// Verses flow into paragraphs; a line break starts a new paragraph or poetry line
+				style := ""
+				var runs strings.Builder
+				for _, verse := range section.Verses {
+					lines := verse.Lines
+					if len(lines) == 0 {
+						lines = []usfm.Line{{Text: verse.Text}}
+					}
+
+					for i, line := range lines {
+						if line.Break || style == "" {
+							if style != "" {
+								writeDOCXParagraph(&body, style, runs.String())
+								runs.Reset()
+							}
+							style = docxParagraphStyle(line)
+						} else {
+							runs.WriteString(docxRun("", " "))
+						}
+
+						if i == 0 {
+							runs.WriteString(docxRun(`<w:rStyle w:val="VerseNumber"/>`, fmt.Sprintf("%d", verse.Number)))
+							runs.WriteString(docxRun("", " "))
+						}
+						runs.WriteString(docxInline(strings.TrimSpace(line.Text)))
+					}
+
+					if options.IncludeFootnotes {
+						for _, footnote := range verse.Footnotes {
+							noteID++
+							fmt.Fprintf(&runs, `<w:r><w:rPr><w:rStyle w:val="FootnoteReference"/></w:rPr><w:footnoteReference w:id="%d"/></w:r>`, noteID)
+							writeDOCXFootnote(&notes, noteID, footnote)
+						}
+					}
+				}
+				if style != "" {
+					writeDOCXParagraph(&body, style, runs.String())
+				}
+			}
+
+			if err := flushOutput(document, &body); err != nil {
+				return fmt.Errorf("failed to write DOCX archive: %w", err)
+			}
+		}
+	}
+
+	body.WriteString(docxDocumentEnd)
+	if err := flushOutput(document, &body); err != nil {
+		return fmt.Errorf("failed to write DOCX archive: %w", err)
+	}
+
+	if err := archive.add(
+		zipFile{"word/styles.xml", docxStyles, zip.Deflate},
+		zipFile{"word/settings.xml", docxSettings, zip.Deflate},
+		zipFile{"word/footnotes.xml", docxFootnotes(notes.String()), zip.Deflate},
+	); err != nil {
+		return fmt.Errorf("failed to write DOCX archive: %w", err)
+	}
+	if err := archive.close(); err != nil {
+		return fmt.Errorf("failed to write DOCX archive: %w", err)
+	}
+	return nil
+}
+
+// docxParagraphStyle returns the paragraph style for a paragraph or poetry marker
+func docxParagraphStyle(line usfm.Line) string {
+	switch {
+	case line.IsPoetry():
+		return fmt.Sprintf("Poetry%d", min(max(line.Indent(), 1), 4))
+	case strings.HasPrefix(line.Marker, "pi"):
+		return fmt.Sprintf("ParagraphIndented%d", min(max(line.Indent(), 1), 3))
+	case line.Marker == "p" || line.Marker == "":
+		return "Paragraph"
+	default:
+		return "Normal"
+	}
+}
+
+// writeDOCXParagraph writes a paragraph with the given style and runs
+func writeDOCXParagraph(result *strings.Builder, style, runs string) {
+	fmt.Fprintf(result, "<w:p><w:pPr><w:pStyle w:val=\"%s\"/></w:pPr>%s</w:p>\n", style, runs)
+}
+
+// writeDOCXFootnote writes a footnote with its reference mark, reference, and text
+func writeDOCXFootnote(result *strings.Builder, id int, footnote usfm.Footnote) {
+	fmt.Fprintf(result, "<w:footnote w:id=\"%d\"><w:p><w:pPr><w:pStyle w:val=\"FootnoteText\"/></w:pPr>", id)
+	result.WriteString(`<w:r><w:rPr><w:rStyle w:val="FootnoteReference"/></w:rPr><w:footnoteRef/></w:r>`)
+	result.WriteString(docxRun("", " "))
+	if footnote.Reference != "" {
+		result.WriteString(docxRun("<w:b/>", escapeXML(footnote.Reference)))
+		result.WriteString(docxRun("", " "))
+	}
+	result.WriteString(docxInline(footnote.Text))
+	result.WriteString("</w:p></w:footnote>\n")
+}
+
+// docxRun returns a text run with the given run properties and escaped text
+func docxRun(properties, text string) string {
+	if properties != "" {
+		properties = "<w:rPr>" + properties + "</w:rPr>"
+	}
+	return fmt.Sprintf(`<w:r>%s<w:t xml:space="preserve">%s</w:t></w:r>`, properties, text)
+}
+
+// docxInline converts text with USFM character markup into text runs.
+// Each span becomes a run whose properties combine all of its character markers.
+func docxInline(text string) string {
+	var result strings.Builder
+
+	for _, span := range usfm.ParseInline(text) {
+		var properties strings.Builder
+		if span.Has("wj") {
+			properties.WriteString(`<w:rStyle w:val="WordsOfJesus"/>`)
+		}
+		if span.Has("bd") {
+			properties.WriteString("<w:b/>")
+		}
+		if span.Has("add") || span.Has("it") || span.Has("em") || span.Has("tl") {
+			properties.WriteString("<w:i/>")
+		}
+		if span.Has("nd") || span.Has("sc") {
+			properties.WriteString("<w:smallCaps/>")
+		}
+		result.WriteString(docxRun(properties.String(), escapeXML(span.Text)))
+	}
+
+	return result.String()
+}
+
+// docxDocumentStart opens the main document part, before the body paragraphs
+const docxDocumentStart = xmlHeader + `<w:document xmlns:w="` + docxNamespace + `">` + "\n<w:body>\n"
+
+// docxDocumentEnd closes the main document part with the page setup, after the body paragraphs
+const docxDocumentEnd = `<w:sectPr><w:pgSz w:w="11906" w:h="16838"/><w:pgMar w:top="1440" w:right="1440" w:bottom="1440" w:left="1440" w:header="720" w:footer="720" w:gutter="0"/></w:sectPr>` +
+	"\n</w:body>\n</w:document>\n"
+
+// docxFootnotes wraps the footnotes in the footnotes part, after the separators Word requires
+func docxFootnotes(notes string) string {
+	return xmlHeader + `<w:footnotes xmlns:w="` + docxNamespace + `">` + "\n" +
+		`<w:footnote w:type="separator" w:id="-1"><w:p><w:r><w:separator/></w:r></w:p></w:footnote>` + "\n" +
+		`<w:footnote w:type="continuationSeparator" w:id="0"><w:p><w:r><w:continuationSeparator/></w:r></w:p></w:footnote>` + "\n" +
+		notes + "</w:footnotes>\n"
+}
+
+// docxCoreProperties builds the document properties part with the document title
+func docxCoreProperties(title string) string {
+	return xmlHeader + `<cp:coreProperties xmlns:cp="http://schemas.openxmlformats.org/package/2006/metadata/core-properties"` +
+		` xmlns:dc="http://purl.org/dc/elements/1.1/">` + "\n" +
+		"  <dc:title>" + escapeXML(title) + "</dc:title>\n" +
+		"  <dc:creator>usfmp</dc:creator>\n" +
+		"</cp:coreProperties>\n"
+}
@@ -0,0 +1,76 @@
+package formatter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/arenzana/usfmp/pkg/usfm"
+)
+
+// TestFormatDOCX tests DOCX packaging, paragraph styles, and footnotes
+func TestFormatDOCX(t *testing.T) {
+	doc := createTestDocument()
+	doc.Chapters[0].Sections[0].Verses[1].Lines = []usfm.Line{
+		{Marker: "q1", Text: `Now the \nd Lord\nd* said,`, Break: true},
+		{Marker: "q2", Text: `\wj I \add am\add* <here> & there\wj*`, Break: true},
+	}
+
+	result, err := FormatDOCX([]*usfm.Document{doc}, DOCXOptions{Title: "Test Bible", IncludeFootnotes: true})
+	if err != nil {
+		t.Fatalf("FormatDOCX failed: %v", err)
+	}
+
+	_, contents := readZip(t, result)
+	expected := map[string][]string{
+		"[Content_Types].xml":          {`PartName="/word/document.xml"`, `PartName="/word/footnotes.xml"`},
+		"_rels/.rels":                  {`Target="word/document.xml"`},
+		"word/_rels/document.xml.rels": {`Target="styles.xml"`, `Target="footnotes.xml"`},
+		"docProps/core.xml":            {"<dc:title>Test Bible</dc:title>"},
+		"word/styles.xml":              {`w:styleId="Heading1"`, `w:styleId="Poetry2"`, `w:styleId="VerseNumber"`, `w:styleId="WordsOfJesus"`},
+		"word/document.xml": {
+			`<w:p><w:pPr><w:pStyle w:val="Title"/></w:pPr><w:r><w:t xml:space="preserve">Test Bible</w:t></w:r></w:p>`,
+			`<w:p><w:pPr><w:pStyle w:val="Heading1"/></w:pPr><w:r><w:t xml:space="preserve">Genesis</w:t></w:r></w:p>`,
+			`<w:p><w:pPr><w:pStyle w:val="Heading2"/></w:pPr><w:r><w:t xml:space="preserve">Chapter 1</w:t></w:r></w:p>`,
+			`<w:p><w:pPr><w:pStyle w:val="Heading3"/></w:pPr><w:r><w:t xml:space="preserve">The Creation</w:t></w:r></w:p>`,
+			`<w:pStyle w:val="CrossReference"/>`,
+			`<w:p><w:pPr><w:pStyle w:val="Paragraph"/></w:pPr><w:r><w:rPr><w:rStyle w:val="VerseNumber"/></w:rPr><w:t xml:space="preserve">1</w:t></w:r>`,
+			`<w:r><w:rPr><w:rStyle w:val="FootnoteReference"/></w:rPr><w:footnoteReference w:id="1"/></w:r></w:p>`,
+			`<w:p><w:pPr><w:pStyle w:val="Poetry1"/></w:pPr>`,
+			`<w:r><w:rPr><w:smallCaps/></w:rPr><w:t xml:space="preserve">Lord</w:t></w:r>`,
+			`<w:p><w:pPr><w:pStyle w:val="Poetry2"/></w:pPr>`,
+			`<w:r><w:rPr><w:rStyle w:val="WordsOfJesus"/><w:i/></w:rPr><w:t xml:space="preserve">am</w:t></w:r>`,
+			`<w:t xml:space="preserve"> &lt;here&gt; &amp; there</w:t>`,
+		},
+		"word/footnotes.xml": {
+			`<w:footnote w:type="separator" w:id="-1">`,
+			`<w:footnote w:id="1">`,
+			`<w:t xml:space="preserve">1:1</w:t>`,
+			`<w:t xml:space="preserve">Hebrew: Elohim</w:t>`,
+		},
+	}
+	for name, values := range expected {
+		content, ok := contents[name]
+		if !ok {
+			t.Errorf("DOCX should contain %s", name)
+			continue
+		}
+		for _, exp := range values {
+			if !strings.Contains(content, exp) {
+				t.Errorf("%s should contain '%s'", name, exp)
+			}
+		}
+	}
+
+	// Footnotes are optional
+	result, err = FormatDOCX([]*usfm.Document{doc}, DOCXOptions{})
+	if err != nil {
+		t.Fatalf("FormatDOCX failed: %v", err)
+	}
+	_, contents = readZip(t, result)
+	if strings.Contains(contents["word/document.xml"], "footnoteReference") {
+		t.Error("Footnotes should not be written when disabled")
+	}
+	if strings.Contains(contents["word/document.xml"], `w:val="Title"`) {
+		t.Error("Title paragraph should not be written without a title")
+	}
+}
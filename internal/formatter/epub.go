@@ -0,0 +1,213 @@
+package formatter
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha1"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/arenzana/usfmp/pkg/usfm"
+)
+
+// EPUBOptions configures the EPUB output.
+type EPUBOptions struct {
+	Title            string    // Publication title; defaults to the book title for a single book, or "Bible"
+	Language         string    // Language code of the text (e.g., "en"); defaults to "und" (undetermined)
+	Identifier       string    // Unique publication identifier; defaults to a UUID derived from the title and books
+	IncludeFootnotes bool      // Whether to write footnotes as popup notes
+	Modified         time.Time // Last modification time written to the metadata; defaults to the current time
+}
+
+// epubContainer points reading systems at the package document
+const epubContainer = xmlHeader + `<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+  <rootfiles>
+    <rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/>
+  </rootfiles>
+</container>
+`
+
+// epubStyle adds the e-book specific rules to the HTML stylesheet
+const epubStyle = `body { max-width: none; }
+aside.footnote { font-size: 0.85em; }
+`
+
+// FormatEPUB formats USFM documents as an EPUB 3 e-book.
+// The e-book is a zip archive with one XHTML content document per book,
+// laid out like the HTML output (see FormatHTML), and a navigation document that lists
+// each book by its \toc1 name with links to its chapters.
+//
+// Footnotes are marked up as EPUB footnotes (epub:type="noteref" and "footnote"),
+// which reading systems such as Apple Books and Kobo show as popups.
+func FormatEPUB(documents []*usfm.Document, options EPUBOptions) ([]byte, error) {
+	var result bytes.Buffer
+	if err := WriteEPUB(&result, documents, options); err != nil {
+		return nil, err
+	}
+	return result.Bytes(), nil
+}
+
+// WriteEPUB writes USFM documents as an EPUB 3 e-book to w, as described for FormatEPUB.
+// The content document of each book is built and written into the archive one at a time.
+func WriteEPUB(w io.Writer, documents []*usfm.Document, options EPUBOptions) error {
+	if len(documents) == 0 {
+		return fmt.Errorf("no documents to write")
+	}
+
+	title := options.Title
+	if title == "" {
+		title = "Bible"
+		if len(documents) == 1 {
+			title = bookTitle(documents[0])
+		}
+	}
+	language := options.Language
+	if language == "" {
+		language = "und"
+	}
+	identifier := options.Identifier
+	if identifier == "" {
+		identifier = epubIdentifier(title, documents)
+	}
+	modified := options.Modified
+	if modified.IsZero() {
+		modified = time.Now()
+	}
+
+	names := bookFileNames(documents, "xhtml")
+	ids := htmlBookIDs(documents)
+	htmlOptions := HTMLOptions{Language: language, IncludeFootnotes: options.IncludeFootnotes}
+
+	// The mimetype file must come first and be stored uncompressed
+	archive := newZipWriter(w, modified)
+	if err := archive.add(
+		zipFile{"mimetype", "application/epub+zip", zip.Store},
+		zipFile{"META-INF/container.xml", epubContainer, zip.Deflate},
+		zipFile{"OEBPS/content.opf", epubPackage(title, language, identifier, modified, names), zip.Deflate},
+		zipFile{"OEBPS/nav.xhtml", epubNavigation(title, language, documents, names, ids), zip.Deflate},
+		zipFile{"OEBPS/style.css", htmlStyle + epubStyle, zip.Deflate},
+	); err != nil {
+		return fmt.Errorf("failed to write EPUB archive: %w", err)
+	}
+
+	for i, doc := range documents {
+		var content strings.Builder
+		writeEPUBHeader(&content, bookTitle(doc), language)
+		writeHTMLBook(&content, doc, ids[i], htmlOptions, true)
+		content.WriteString("</body>\n</html>\n")
+		if err := archive.add(zipFile{"OEBPS/" + names[i], content.String(), zip.Deflate}); err != nil {
+			return fmt.Errorf("failed to write EPUB archive: %w", err)
+		}
+	}
+
+	if err := archive.close(); err != nil {
+		return fmt.Errorf("failed to write EPUB archive: %w", err)
+	}
+	return nil
+}
+
+// epubIdentifier derives a stable UUID URN from the title and book codes, so that
+// regenerating the same publication keeps its identifier
+func epubIdentifier(title string, documents []*usfm.Document) string {
+	hash := sha1.New()
+	hash.Write([]byte(title))
+	for _, doc := range documents {
+		hash.Write([]byte("\x00" + doc.BookCode()))
+	}
+	sum := hash.Sum(nil)
+
+	// Format as a version 5 (name-based, SHA-1) UUID
+	sum[6] = sum[6]&0x0f | 0x50
+	sum[8] = sum[8]&0x3f | 0x80
+	return fmt.Sprintf("urn:uuid:%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
+
+// epubPackage builds the package document with the metadata, manifest, and spine
+func epubPackage(title, language, identifier string, modified time.Time, names []string) string {
+	var result strings.Builder
+
+	result.WriteString(xmlHeader)
+	fmt.Fprintf(&result, "<package xmlns=\"http://www.idpf.org/2007/opf\" version=\"3.0\" unique-identifier=\"book-id\" xml:lang=\"%s\">\n",
+		escapeXML(language))
+	result.WriteString("  <metadata xmlns:dc=\"http://purl.org/dc/elements/1.1/\">\n")
+	fmt.Fprintf(&result, "    <dc:identifier id=\"book-id\">%s</dc:identifier>\n", escapeXML(identifier))
+	fmt.Fprintf(&result, "    <dc:title>%s</dc:title>\n", escapeXML(title))
+	fmt.Fprintf(&result, "    <dc:language>%s</dc:language>\n", escapeXML(language))
+	fmt.Fprintf(&result, "    <meta property=\"dcterms:modified\">%s</meta>\n", modified.UTC().Format("2006-01-02T15:04:05Z"))
+	result.WriteString("  </metadata>\n")
+
+	result.WriteString("  <manifest>\n")
+	result.WriteString("    <item id=\"nav\" href=\"nav.xhtml\" media-type=\"application/xhtml+xml\" properties=\"nav\"/>\n")
+	result.WriteString("    <item id=\"style\" href=\"style.css\" media-type=\"text/css\"/>\n")
+	for i, name := range names {
+		fmt.Fprintf(&result, "    <item id=\"book-%d\" href=\"%s\" media-type=\"application/xhtml+xml\"/>\n", i+1, escapeXML(name))
+	}
+	result.WriteString("  </manifest>\n")
+
+	result.WriteString("  <spine>\n")
+	result.WriteString("    <itemref idref=\"nav\"/>\n")
+	for i := range names {
+		fmt.Fprintf(&result, "    <itemref idref=\"book-%d\"/>\n", i+1)
+	}
+	result.WriteString("  </spine>\n")
+	result.WriteString("</package>\n")
+
+	return result.String()
+}
+
+// epubNavigation builds the navigation document with the table of contents and landmarks
+func epubNavigation(title, language string, documents []*usfm.Document, names, ids []string) string {
+	var result strings.Builder
+
+	writeEPUBHeader(&result, title, language)
+	result.WriteString("<nav epub:type=\"toc\" id=\"toc\" role=\"doc-toc\">\n")
+	fmt.Fprintf(&result, "<h1>%s</h1>\n", html.EscapeString(title))
+	result.WriteString("<ol>\n")
+	for i, doc := range documents {
+		fmt.Fprintf(&result, "<li><a href=\"%s#%s\">%s</a>", names[i], ids[i], html.EscapeString(epubBookLabel(doc)))
+		if len(doc.Chapters) > 1 {
+			result.WriteString("\n<ol>\n")
+			for _, chapter := range doc.Chapters {
+				fmt.Fprintf(&result, "<li><a href=\"%s#%s-%d\">%d</a></li>\n", names[i], ids[i], chapter.Number, chapter.Number)
+			}
+			result.WriteString("</ol>\n")
+		}
+		result.WriteString("</li>\n")
+	}
+	result.WriteString("</ol>\n</nav>\n")
+
+	result.WriteString("<nav epub:type=\"landmarks\" hidden=\"hidden\">\n<ol>\n")
+	result.WriteString("<li><a epub:type=\"toc\" href=\"#toc\">Table of Contents</a></li>\n")
+	fmt.Fprintf(&result, "<li><a epub:type=\"bodymatter\" href=\"%s\">Start</a></li>\n", names[0])
+	result.WriteString("</ol>\n</nav>\n")
+	result.WriteString("</body>\n</html>\n")
+
+	return result.String()
+}
+
+// epubBookLabel returns the name of a book for the table of contents, preferring
+// the long table of contents entry (\toc1) over the display title
+func epubBookLabel(doc *usfm.Document) string {
+	for _, entry := range doc.TableOfContents {
+		if entry.Level == 1 && entry.Text != "" {
+			return entry.Text
+		}
+	}
+	return bookTitle(doc)
+}
+
+// writeEPUBHeader writes the XML declaration, head, and opening body of an XHTML content document
+func writeEPUBHeader(result *strings.Builder, title, language string) {
+	result.WriteString(xmlHeader)
+	result.WriteString("<!DOCTYPE html>\n")
+	fmt.Fprintf(result, "<html xmlns=\"http://www.w3.org/1999/xhtml\" xmlns:epub=\"http://www.idpf.org/2007/ops\" lang=\"%s\" xml:lang=\"%s\">\n",
+		escapeXML(language), escapeXML(language))
+	result.WriteString("<head>\n")
+	result.WriteString("<meta charset=\"utf-8\"/>\n")
+	fmt.Fprintf(result, "<title>%s</title>\n", escapeXML(title))
+	result.WriteString("<link rel=\"stylesheet\" type=\"text/css\" href=\"style.css\"/>\n")
+	result.WriteString("</head>\n<body>\n")
+}
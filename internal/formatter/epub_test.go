@@ -0,0 +1,103 @@
+package formatter
+
+import (
+	"archive/zip"
+	"encoding/binary"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arenzana/usfmp/pkg/usfm"
+)
+
+// TestFormatEPUB tests EPUB packaging, metadata, navigation, and footnotes
+func TestFormatEPUB(t *testing.T) {
+	genesis := createTestDocument()
+	exodus := createTestDocument()
+	exodus.ID = "EXO"
+	exodus.MainTitle = "Exodus"
+	exodus.TableOfContents = []usfm.TOCEntry{{Level: 1, Text: "The Second Book of Moses"}}
+	exodus.Chapters = append(exodus.Chapters, usfm.Chapter{Number: 2})
+
+	options := EPUBOptions{
+		Title:            "Test & Bible",
+		Language:         "en",
+		Identifier:       "urn:isbn:9780000000000",
+		IncludeFootnotes: true,
+		Modified:         time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	result, err := FormatEPUB([]*usfm.Document{genesis, exodus}, options)
+	if err != nil {
+		t.Fatalf("FormatEPUB failed: %v", err)
+	}
+
+	files, contents := readZip(t, result)
+	if files[0].Name != "mimetype" || files[0].Method != zip.Store || contents["mimetype"] != "application/epub+zip" {
+		t.Error("The first file should be the uncompressed mimetype")
+	}
+	// The local header of mimetype has no extra field (its length is at byte 28)
+	if extra := binary.LittleEndian.Uint16(result[28:30]); extra != 0 {
+		t.Errorf("Expected no extra field in the mimetype header, got %d bytes", extra)
+	}
+	// MS-DOS times count seconds in steps of two
+	if modified := options.Modified.Truncate(2 * time.Second); !files[1].Modified.Equal(modified) {
+		t.Errorf("Expected files modified at %v, got %v", modified, files[1].Modified)
+	}
+
+	expected := map[string][]string{
+		"META-INF/container.xml": {`full-path="OEBPS/content.opf"`},
+		"OEBPS/content.opf": {
+			`<dc:identifier id="book-id">urn:isbn:9780000000000</dc:identifier>`,
+			`<dc:title>Test &amp; Bible</dc:title>`,
+			`<dc:language>en</dc:language>`,
+			`<meta property="dcterms:modified">2025-01-02T03:04:05Z</meta>`,
+			`properties="nav"`,
+			`<item id="book-2" href="02-EXO.xhtml" media-type="application/xhtml+xml"/>`,
+			`<itemref idref="book-2"/>`,
+		},
+		"OEBPS/nav.xhtml": {
+			`<nav epub:type="toc" id="toc" role="doc-toc">`,
+			`<a href="01-GEN.xhtml#GEN">Genesis</a>`,
+			`<a href="02-EXO.xhtml#EXO">The Second Book of Moses</a>`,
+			`<a href="02-EXO.xhtml#EXO-2">2</a>`,
+		},
+		"OEBPS/01-GEN.xhtml": {
+			`xmlns:epub="http://www.idpf.org/2007/ops" lang="en" xml:lang="en"`,
+			`<link rel="stylesheet" type="text/css" href="style.css"/>`,
+			`<sup class="v" id="GEN-1-1">1</sup>`,
+			`<a class="fn-ref" id="GEN-fnref-1" href="#GEN-fn-1" epub:type="noteref" role="doc-noteref"><sup>1</sup></a>`,
+			`<aside class="footnote" id="GEN-fn-1" epub:type="footnote" role="doc-footnote">`,
+			"Hebrew: Elohim",
+		},
+		"OEBPS/style.css": {".wj"},
+	}
+	for name, values := range expected {
+		content, ok := contents[name]
+		if !ok {
+			t.Errorf("EPUB should contain %s", name)
+			continue
+		}
+		for _, exp := range values {
+			if !strings.Contains(content, exp) {
+				t.Errorf("%s should contain '%s'", name, exp)
+			}
+		}
+	}
+
+	// Without an identifier, a stable one is derived from the title and books
+	options.Identifier = ""
+	first, _ := FormatEPUB([]*usfm.Document{genesis}, options)
+	second, _ := FormatEPUB([]*usfm.Document{genesis}, options)
+	_, firstContents := readZip(t, first)
+	_, secondContents := readZip(t, second)
+	if !strings.Contains(firstContents["OEBPS/content.opf"], "urn:uuid:") {
+		t.Error("Default identifier should be a UUID URN")
+	}
+	if firstContents["OEBPS/content.opf"] != secondContents["OEBPS/content.opf"] {
+		t.Error("Default identifier should be stable")
+	}
+
+	if _, err := FormatEPUB(nil, options); err == nil {
+		t.Error("Expected error for no documents")
+	}
+}
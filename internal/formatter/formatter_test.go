@@ -78,6 +78,15 @@ func TestFormatJSON(t *testing.T) {
 	if !strings.Contains(result, `"The Creation"`) {
 		t.Error("JSON output should contain section title")
 	}
+
+	// Verse lines are layout data and would repeat the text of every verse
+	doc.Chapters[0].Sections[0].Verses[0].Lines = []usfm.Line{{Marker: "q1", Text: "In the beginning", Break: true}}
+	if result, err = FormatJSON(documents); err != nil {
+		t.Fatalf("FormatJSON failed: %v", err)
+	}
+	if strings.Contains(result, `"lines"`) {
+		t.Error("JSON output should not contain verse lines")
+	}
 }
 
 // TestFormatJSONMultipleDocuments tests JSON formatting with multiple documents
@@ -161,7 +170,7 @@ func TestFormatTSV(t *testing.T) {
 	doc := createTestDocument()
 	documents := []*usfm.Document{doc}
 
-	result, err := FormatTSV(documents)
+	result, err := FormatTSV(documents, TableOptions{})
 	if err != nil {
 		t.Fatalf("FormatTSV failed: %v", err)
 	}
@@ -0,0 +1,331 @@
+package formatter
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	"github.com/arenzana/usfmp/pkg/usfm"
+)
+
+// HTMLOptions configures the HTML output.
+type HTMLOptions struct {
+	Title            string // Page title; defaults to the book title for a single book, or "Bible"
+	Language         string // Language code written to the lang attribute (e.g., "en"); omitted when empty
+	ClassOnly        bool   // Write class names only, without the embedded stylesheet
+	IncludeFootnotes bool   // Whether to write footnotes as linked notes
+}
+
+// htmlStyle is the stylesheet embedded in HTML output unless ClassOnly is set.
+// The class names it styles are the ones written by FormatHTML, so a site stylesheet
+// can replace it when ClassOnly is used.
+const htmlStyle = `body { font-family: Georgia, "Times New Roman", serif; line-height: 1.6; max-width: 40em; margin: 0 auto; padding: 1em; color: #222; }
+.book > h1 { text-align: center; }
+nav ul { list-style: none; padding: 0; display: flex; flex-wrap: wrap; gap: 0.25em 0.75em; }
+.chapter > h2 { margin-top: 1.5em; }
+.chapter > h3, .chapter > h4, .chapter > h5 { margin-bottom: 0.25em; }
+.chapter > p { margin: 0; }
+.chapter > p.r { font-style: italic; margin-bottom: 0.5em; }
+.p { text-indent: 1.5em; }
+.q, .q1 { padding-left: 2em; text-indent: -1em; }
+.q2 { padding-left: 3em; text-indent: -1em; }
+.q3 { padding-left: 4em; text-indent: -1em; }
+.q4 { padding-left: 5em; text-indent: -1em; }
+.pi, .pi1 { padding-left: 1.5em; text-indent: 1.5em; }
+.pi2 { padding-left: 3em; text-indent: 1.5em; }
+.pi3 { padding-left: 4.5em; text-indent: 1.5em; }
+.v { color: #777; font-size: 0.7em; }
+.wj { color: #c00; }
+.nd, .sc { font-variant: small-caps; }
+.add, .tl { font-style: italic; }
+.fn-ref { position: relative; text-decoration: none; }
+.fn-ref[data-note]:hover::after, .fn-ref[data-note]:focus::after { content: attr(data-note); position: absolute; left: 0; top: 1.5em; z-index: 1; width: 20em; padding: 0.5em; border: 1px solid #ccc; background: #fff; color: #222; font-size: 0.85rem; line-height: 1.4; text-indent: 0; }
+.footnotes { margin-top: 1em; border-top: 1px solid #ccc; font-size: 0.85em; }
+:target { background: #ffefa0; }
+`
+
+// FormatHTML formats USFM documents as a single self-contained HTML5 page.
+// Each document is written as an <article class="book">; multiple books get a book index.
+//
+// The HTML output uses:
+//   - Anchored IDs for books ("GEN"), chapters ("GEN-1"), and verses ("GEN-1-1")
+//   - <h3>-<h5> section headings for \s1-\s3, with cross-references in <p class="r">
+//   - Paragraphs and poetry lines as <p> elements classed by their USFM marker ("p", "q1", "q2")
+//   - Footnotes numbered per book, linked to notes listed at the end of each chapter
+//   - <span class="wj">, <span class="nd">, and <i class="add"> for red letters,
+//     the divine name, and translator additions
+//
+// The embedded stylesheet shows footnotes as popups on hover or focus.
+// With ClassOnly set, only the class names are written so that a site stylesheet can be used.
+func FormatHTML(documents []*usfm.Document, options HTMLOptions) (string, error) {
+	var result strings.Builder
+	if err := WriteHTML(&result, documents, options); err != nil {
+		return "", err
+	}
+	return result.String(), nil
+}
+
+// WriteHTML writes USFM documents as a single HTML5 page to w, in the layout described
+// for FormatHTML. The output is written one book at a time.
+func WriteHTML(w io.Writer, documents []*usfm.Document, options HTMLOptions) error {
+	title := options.Title
+	if title == "" {
+		title = "Bible"
+		if len(documents) == 1 {
+			title = bookTitle(documents[0])
+		}
+	}
+
+	var result strings.Builder
+	writeHTMLHeader(&result, title, htmlStyle, options)
+
+	ids := htmlBookIDs(documents)
+	if len(documents) > 1 {
+		result.WriteString("<nav class=\"books\" aria-label=\"Books\">\n<ul>\n")
+		for i, doc := range documents {
+			fmt.Fprintf(&result, "<li><a href=\"#%s\">%s</a></li>\n", ids[i], html.EscapeString(bookTitle(doc)))
+		}
+		result.WriteString("</ul>\n</nav>\n")
+	}
+
+	for i, doc := range documents {
+		writeHTMLBook(&result, doc, ids[i], options, false)
+		if err := flushOutput(w, &result); err != nil {
+			return err
+		}
+	}
+
+	writeHTMLFooter(&result)
+	return flushOutput(w, &result)
+}
+
+// FormatHTMLBooks formats each USFM document as its own self-contained HTML5 page.
+// The pages are laid out as described for FormatHTML, and are named after the book
+// they contain (e.g., "01-GEN.html").
+func FormatHTMLBooks(documents []*usfm.Document, options HTMLOptions) ([]OutputFile, error) {
+	names := bookFileNames(documents, "html")
+	ids := htmlBookIDs(documents)
+
+	files := make([]OutputFile, 0, len(documents))
+	for i, doc := range documents {
+		title := bookTitle(doc)
+		if options.Title != "" {
+			title = options.Title + " - " + title
+		}
+
+		var result strings.Builder
+		writeHTMLHeader(&result, title, htmlStyle, options)
+		writeHTMLBook(&result, doc, ids[i], options, false)
+		writeHTMLFooter(&result)
+
+		files = append(files, OutputFile{Name: names[i], Data: []byte(result.String())})
+	}
+
+	return files, nil
+}
+
+// writeHTMLHeader writes the doctype, head, and opening body of a page, with the stylesheet unless ClassOnly is set
+func writeHTMLHeader(result *strings.Builder, title, style string, options HTMLOptions) {
+	result.WriteString("<!DOCTYPE html>\n")
+	if options.Language != "" {
+		fmt.Fprintf(result, "<html lang=\"%s\">\n", html.EscapeString(options.Language))
+	} else {
+		result.WriteString("<html>\n")
+	}
+	result.WriteString("<head>\n")
+	result.WriteString("<meta charset=\"utf-8\">\n")
+	result.WriteString("<meta name=\"viewport\" content=\"width=device-width, initial-scale=1\">\n")
+	fmt.Fprintf(result, "<title>%s</title>\n", html.EscapeString(title))
+	if !options.ClassOnly {
+		result.WriteString("<style>\n" + style + "</style>\n")
+	}
+	result.WriteString("</head>\n<body>\n<main>\n")
+}
+
+// writeHTMLFooter closes the elements opened by writeHTMLHeader
+func writeHTMLFooter(result *strings.Builder) {
+	result.WriteString("</main>\n</body>\n</html>\n")
+}
+
+// htmlBookIDs returns a unique element ID for each document, based on its book code
+func htmlBookIDs(documents []*usfm.Document) []string {
+	ids := make([]string, len(documents))
+	used := make(map[string]bool)
+
+	for i, doc := range documents {
+		base := doc.BookCode()
+		if base == "" {
+			base = fmt.Sprintf("book-%d", i+1)
+		}
+
+		id := base
+		for n := 2; used[id]; n++ {
+			id = fmt.Sprintf("%s-%d", base, n)
+		}
+		used[id] = true
+		ids[i] = html.EscapeString(id)
+	}
+
+	return ids
+}
+
+// htmlNote is a footnote waiting to be listed at the end of its chapter
+type htmlNote struct {
+	number   int
+	verseID  string
+	footnote usfm.Footnote
+}
+
+// writeHTMLBook writes one document as an <article> with its chapter index and chapters.
+// For EPUB content documents, footnotes are marked with epub:type so that
+// reading systems can show them as popups, and each note is written as its own <aside>.
+func writeHTMLBook(result *strings.Builder, doc *usfm.Document, id string, options HTMLOptions, epub bool) {
+	fmt.Fprintf(result, "<article class=\"book\" id=\"%s\" aria-labelledby=\"%s-title\">\n", id, id)
+	fmt.Fprintf(result, "<h1 id=\"%s-title\">%s</h1>\n", id, html.EscapeString(bookTitle(doc)))
+
+	if len(doc.Chapters) > 1 {
+		result.WriteString("<nav class=\"chapters\" aria-label=\"Chapters\">\n<ul>\n")
+		for _, chapter := range doc.Chapters {
+			fmt.Fprintf(result, "<li><a href=\"#%s-%d\">%d</a></li>\n", id, chapter.Number, chapter.Number)
+		}
+		result.WriteString("</ul>\n</nav>\n")
+	}
+
+	noteNumber := 0
+	for _, chapter := range doc.Chapters {
+		chapterID := fmt.Sprintf("%s-%d", id, chapter.Number)
+		fmt.Fprintf(result, "<section class=\"chapter\" id=\"%s\" aria-labelledby=\"%s-heading\">\n", chapterID, chapterID)
+		fmt.Fprintf(result, "<h2 id=\"%s-heading\">Chapter %d</h2>\n", chapterID, chapter.Number)
+
+		var notes []htmlNote
+		for _, section := range chapter.Sections {
+			if section.Title != "" {
+				level := section.Level
+				if level < 1 || level > 3 {
+					level = 1
+				}
+				fmt.Fprintf(result, "<h%d class=\"s%d\">%s</h%d>\n", level+2, level, htmlInline(section.Title), level+2)
+			}
+			if section.Reference != "" {
+				fmt.Fprintf(result, "<p class=\"r\">%s</p>\n", html.EscapeString(section.Reference))
+			}
+
+			// Verses flow into paragraphs; a line break starts a new paragraph or poetry line
+			open := false
+			for _, verse := range section.Verses {
+				verseID := fmt.Sprintf("%s-%d", chapterID, verse.Number)
+				lines := verse.Lines
+				if len(lines) == 0 {
+					lines = []usfm.Line{{Text: verse.Text}}
+				}
+
+				for i, line := range lines {
+					if line.Break || !open {
+						if open {
+							result.WriteString("</p>\n")
+						}
+						marker := line.Marker
+						if marker == "" {
+							marker = "p"
+						}
+						fmt.Fprintf(result, "<p class=\"%s\">", html.EscapeString(marker))
+						open = true
+					} else {
+						result.WriteString(" ")
+					}
+
+					if i == 0 {
+						fmt.Fprintf(result, "<sup class=\"v\" id=\"%s\">%d</sup> ", verseID, verse.Number)
+					}
+					result.WriteString(htmlInline(line.Text))
+				}
+
+				if options.IncludeFootnotes {
+					for _, footnote := range verse.Footnotes {
+						noteNumber++
+						note := htmlNote{number: noteNumber, verseID: verseID, footnote: footnote}
+						notes = append(notes, note)
+
+						if epub {
+							fmt.Fprintf(result, "<a class=\"fn-ref\" id=\"%s-fnref-%d\" href=\"#%s-fn-%d\" epub:type=\"noteref\" role=\"doc-noteref\"><sup>%d</sup></a>",
+								id, noteNumber, id, noteNumber, noteNumber)
+							continue
+						}
+
+						noteText := usfm.PlainText(footnote.Text)
+						if footnote.Reference != "" {
+							noteText = footnote.Reference + " " + noteText
+						}
+						fmt.Fprintf(result, "<a class=\"fn-ref\" id=\"%s-fnref-%d\" href=\"#%s-fn-%d\" role=\"doc-noteref\" data-note=\"%s\"><sup>%d</sup></a>",
+							id, noteNumber, id, noteNumber, html.EscapeString(noteText), noteNumber)
+					}
+				}
+			}
+			if open {
+				result.WriteString("</p>\n")
+			}
+		}
+
+		if len(notes) > 0 && epub {
+			for _, note := range notes {
+				fmt.Fprintf(result, "<aside class=\"footnote\" id=\"%s-fn-%d\" epub:type=\"footnote\" role=\"doc-footnote\"><p>", id, note.number)
+				fmt.Fprintf(result, "<a href=\"#%s-fnref-%d\">%d</a> ", id, note.number, note.number)
+				if note.footnote.Reference != "" {
+					fmt.Fprintf(result, "<b>%s</b> ", html.EscapeString(note.footnote.Reference))
+				}
+				result.WriteString(htmlInline(note.footnote.Text))
+				result.WriteString("</p></aside>\n")
+			}
+		} else if len(notes) > 0 {
+			result.WriteString("<aside class=\"footnotes\" role=\"doc-endnotes\" aria-label=\"Footnotes\">\n<ol>\n")
+			for _, note := range notes {
+				fmt.Fprintf(result, "<li id=\"%s-fn-%d\" value=\"%d\">", id, note.number, note.number)
+				if note.footnote.Reference != "" {
+					fmt.Fprintf(result, "<a href=\"#%s\"><b>%s</b></a> ", note.verseID, html.EscapeString(note.footnote.Reference))
+				}
+				result.WriteString(htmlInline(note.footnote.Text))
+				fmt.Fprintf(result, " <a href=\"#%s-fnref-%d\" role=\"doc-backlink\" aria-label=\"Back to text\">&#8617;</a></li>\n",
+					id, note.number)
+			}
+			result.WriteString("</ol>\n</aside>\n")
+		}
+
+		result.WriteString("</section>\n")
+	}
+
+	result.WriteString("</article>\n")
+}
+
+// htmlInline converts text with USFM character markup into escaped HTML inline elements
+func htmlInline(text string) string {
+	return renderInline(text, htmlElement, html.EscapeString)
+}
+
+// htmlElement returns the opening and closing HTML tags for a USFM character marker.
+// Markers without an HTML equivalent return empty tags so that only their text is kept.
+func htmlElement(marker string, attributes map[string]string) (string, string) {
+	switch marker {
+	case "w":
+		if strong := attributes["strong"]; strong != "" {
+			return fmt.Sprintf("<span class=\"w\" data-strong=\"%s\">", html.EscapeString(strong)), "</span>"
+		}
+		return "", ""
+	case "wj":
+		return `<span class="wj">`, "</span>"
+	case "nd":
+		return `<span class="nd">`, "</span>"
+	case "sc":
+		return `<span class="sc">`, "</span>"
+	case "add":
+		return `<i class="add">`, "</i>"
+	case "tl":
+		return `<i class="tl">`, "</i>"
+	case "it", "em":
+		return "<em>", "</em>"
+	case "bd":
+		return "<strong>", "</strong>"
+	default:
+		return "", ""
+	}
+}
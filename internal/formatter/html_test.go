@@ -0,0 +1,99 @@
+package formatter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/arenzana/usfmp/pkg/usfm"
+)
+
+// TestFormatHTML tests single-page HTML formatting
+func TestFormatHTML(t *testing.T) {
+	doc := createTestDocument()
+	doc.Chapters[0].Sections[0].Verses[1].Lines = []usfm.Line{
+		{Marker: "q1", Text: `Now the \nd Lord\nd* said,`, Break: true},
+		{Marker: "q2", Text: `\wj \+w I|strong="G1473"\+w* \add am\add* <here>\wj*`, Break: true},
+	}
+
+	result, err := FormatHTML([]*usfm.Document{doc}, HTMLOptions{Language: "en", IncludeFootnotes: true})
+	if err != nil {
+		t.Fatalf("FormatHTML failed: %v", err)
+	}
+
+	expected := []string{
+		"<!DOCTYPE html>",
+		`<html lang="en">`,
+		"<title>Genesis</title>",
+		"<style>",
+		`<article class="book" id="GEN" aria-labelledby="GEN-title">`,
+		`<section class="chapter" id="GEN-1" aria-labelledby="GEN-1-heading">`,
+		`<h3 class="s1">The Creation</h3>`,
+		`<p class="r">(John 1:1–5)</p>`,
+		`<p class="p"><sup class="v" id="GEN-1-1">1</sup> In the beginning God created the heavens and the earth.` +
+			`<a class="fn-ref" id="GEN-fnref-1" href="#GEN-fn-1" role="doc-noteref" data-note="1:1 Hebrew: Elohim"><sup>1</sup></a></p>`,
+		`<p class="q1"><sup class="v" id="GEN-1-2">2</sup> Now the <span class="nd">Lord</span> said,</p>`,
+		`<p class="q2"><span class="wj"><span class="w" data-strong="G1473">I</span> <i class="add">am</i> &lt;here&gt;</span></p>`,
+		`<li id="GEN-fn-1" value="1"><a href="#GEN-1-1"><b>1:1</b></a> Hebrew: Elohim`,
+	}
+	for _, exp := range expected {
+		if !strings.Contains(result, exp) {
+			t.Errorf("HTML output should contain '%s'", exp)
+		}
+	}
+
+	// Class names only, without footnotes
+	result, err = FormatHTML([]*usfm.Document{doc}, HTMLOptions{ClassOnly: true})
+	if err != nil {
+		t.Fatalf("FormatHTML failed: %v", err)
+	}
+	if strings.Contains(result, "<style>") {
+		t.Error("Class-only output should not embed a stylesheet")
+	}
+	if strings.Contains(result, "fn-ref") || strings.Contains(result, "footnotes") {
+		t.Error("Footnotes should not be written when disabled")
+	}
+	if !strings.Contains(result, `<span class="wj">`) {
+		t.Error("Class-only output should keep class names")
+	}
+}
+
+// TestFormatHTMLBooks tests per-book HTML formatting
+func TestFormatHTMLBooks(t *testing.T) {
+	genesis := createTestDocument()
+	exodus := createTestDocument()
+	exodus.ID = "EXO"
+	exodus.MainTitle = "Exodus"
+
+	// Multiple books on one page get a book index and unique IDs
+	result, err := FormatHTML([]*usfm.Document{genesis, exodus}, HTMLOptions{Title: "Test Bible"})
+	if err != nil {
+		t.Fatalf("FormatHTML failed: %v", err)
+	}
+	for _, exp := range []string{"<title>Test Bible</title>", `<a href="#EXO">Exodus</a>`, `id="EXO-1-1"`} {
+		if !strings.Contains(result, exp) {
+			t.Errorf("HTML output should contain '%s'", exp)
+		}
+	}
+
+	files, err := FormatHTMLBooks([]*usfm.Document{genesis, exodus, {ID: "FRT"}, {}}, HTMLOptions{Title: "Test Bible"})
+	if err != nil {
+		t.Fatalf("FormatHTMLBooks failed: %v", err)
+	}
+
+	names := []string{"01-GEN.html", "02-EXO.html", "FRT.html", "book-4.html"}
+	if len(files) != len(names) {
+		t.Fatalf("Expected %d files, got %d", len(names), len(files))
+	}
+	for i, name := range names {
+		if files[i].Name != name {
+			t.Errorf("Expected file name %s, got %s", name, files[i].Name)
+		}
+	}
+
+	if !strings.Contains(string(files[1].Data), "<title>Test Bible - Exodus</title>") {
+		t.Error("Per-book page title should include the Bible and book title")
+	}
+	if strings.Contains(string(files[1].Data), "Genesis") {
+		t.Error("Per-book page should only contain its own book")
+	}
+}
@@ -0,0 +1,51 @@
+package formatter
+
+import (
+	"strings"
+
+	"github.com/arenzana/usfmp/pkg/usfm"
+)
+
+// inlineElement returns the opening and closing markup for a USFM character marker
+type inlineElement func(marker string, attributes map[string]string) (string, string)
+
+// renderInline converts text with USFM character markup into markup of an output format,
+// using element to map character markers and escape to escape plain text.
+// Elements that continue across adjacent spans (such as \wj around several \+w words)
+// are kept open instead of being closed and reopened for every span.
+func renderInline(text string, element inlineElement, escape func(string) string) string {
+	var result strings.Builder
+	var markers, closers []string
+
+	for _, span := range usfm.ParseInline(text) {
+		// Keep the common outer elements open; word elements carry their own attributes
+		keep := 0
+		for keep < len(markers) && keep < len(span.Markers) &&
+			markers[keep] == span.Markers[keep] && markers[keep] != "w" {
+			keep++
+		}
+		for len(closers) > keep {
+			result.WriteString(closers[len(closers)-1])
+			closers = closers[:len(closers)-1]
+		}
+		markers = append(markers[:keep], span.Markers[keep:]...)
+
+		for i := keep; i < len(span.Markers); i++ {
+			var attributes map[string]string
+			if i == len(span.Markers)-1 {
+				attributes = span.Attributes
+			}
+			open, close := element(span.Markers[i], attributes)
+			result.WriteString(open)
+			closers = append(closers, close)
+		}
+
+		result.WriteString(escape(span.Text))
+	}
+
+	for i := len(closers) - 1; i >= 0; i-- {
+		result.WriteString(closers[i])
+	}
+
+	return result.String()
+}
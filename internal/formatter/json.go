@@ -5,6 +5,8 @@ package formatter
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"strings"
 
 	"github.com/arenzana/usfmp/pkg/usfm"
 )
@@ -29,24 +31,54 @@ import (
 //	  {"id": "EXO", ...}
 //	]
 func FormatJSON(documents []*usfm.Document) (string, error) {
+	var result strings.Builder
+	if err := WriteJSON(&result, documents); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(result.String(), "\n"), nil
+}
+
+// WriteJSON writes one or more USFM documents as JSON to w, in the layout described
+// for FormatJSON followed by a newline. Multiple documents are encoded one at a time,
+// so the output is written as it is produced.
+func WriteJSON(w io.Writer, documents []*usfm.Document) error {
 	if len(documents) == 0 {
-		return "[]", nil
+		_, err := io.WriteString(w, "[]\n")
+		return err
 	}
 
-	// If single document, return it directly (not wrapped in array)
+	// If single document, write it directly (not wrapped in array)
 	if len(documents) == 1 {
-		data, err := json.MarshalIndent(documents[0], "", "  ")
-		if err != nil {
-			return "", fmt.Errorf("failed to marshal document to JSON: %w", err)
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(documents[0]); err != nil {
+			return fmt.Errorf("failed to marshal document to JSON: %w", err)
 		}
-		return string(data), nil
+		return nil
 	}
 
-	// Multiple documents - return as array
-	data, err := json.MarshalIndent(documents, "", "  ")
-	if err != nil {
-		return "", fmt.Errorf("failed to marshal documents to JSON: %w", err)
+	// Multiple documents - write as array, indented as json.MarshalIndent would
+	for i, doc := range documents {
+		separator := ",\n  "
+		if i == 0 {
+			separator = "[\n  "
+		}
+		if _, err := io.WriteString(w, separator); err != nil {
+			return err
+		}
+
+		// Encode ends each document with a newline, which is replaced by the separator
+		var data strings.Builder
+		encoder := json.NewEncoder(&data)
+		encoder.SetIndent("  ", "  ")
+		if err := encoder.Encode(doc); err != nil {
+			return fmt.Errorf("failed to marshal documents to JSON: %w", err)
+		}
+		if _, err := io.WriteString(w, strings.TrimSuffix(data.String(), "\n")); err != nil {
+			return err
+		}
 	}
 
-	return string(data), nil
+	_, err := io.WriteString(w, "\n]\n")
+	return err
 }
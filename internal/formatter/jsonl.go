@@ -0,0 +1,203 @@
+package formatter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/arenzana/usfmp/pkg/ref"
+	"github.com/arenzana/usfmp/pkg/usfm"
+)
+
+// JSONLRecord selects what each line of the JSON Lines output describes.
+type JSONLRecord string
+
+// JSON Lines record types
+const (
+	JSONLVerse    JSONLRecord = "verse"    // One record per verse (default)
+	JSONLFootnote JSONLRecord = "footnote" // One record per footnote
+	JSONLSection  JSONLRecord = "section"  // One record per section, with the text of all its verses
+)
+
+// JSONLOptions configures the JSON Lines output.
+type JSONLOptions struct {
+	Record JSONLRecord // Record type written on each line; defaults to JSONLVerse
+}
+
+// ParseJSONLRecord parses a JSON Lines record type name (verse, footnote, or section).
+func ParseJSONLRecord(value string) (JSONLRecord, error) {
+	switch record := JSONLRecord(strings.ToLower(value)); record {
+	case JSONLVerse, JSONLFootnote, JSONLSection:
+		return record, nil
+	default:
+		return "", fmt.Errorf("invalid record type %q (valid: verse, footnote, section)", value)
+	}
+}
+
+// jsonlVerse is a verse record of the JSON Lines output
+type jsonlVerse struct {
+	Book         string          `json:"book"`
+	Chapter      int             `json:"chapter"`
+	Verse        int             `json:"verse"`
+	SectionTitle string          `json:"section_title,omitempty"`
+	SectionLevel int             `json:"section_level,omitempty"`
+	Text         string          `json:"text"`
+	Footnotes    []usfm.Footnote `json:"footnotes,omitempty"`
+	Reference    string          `json:"reference,omitempty"`
+	Parsed       []ref.Range     `json:"parsed_reference,omitempty"`
+	SourceFile   string          `json:"source_file,omitempty"`
+}
+
+// jsonlFootnote is a footnote record of the JSON Lines output
+type jsonlFootnote struct {
+	Book       string      `json:"book"`
+	Chapter    int         `json:"chapter"`
+	Verse      int         `json:"verse"`
+	Caller     string      `json:"caller"`
+	Reference  string      `json:"reference,omitempty"`
+	Parsed     []ref.Range `json:"parsed_reference,omitempty"`
+	Text       string      `json:"text"`
+	SourceFile string      `json:"source_file,omitempty"`
+}
+
+// jsonlSection is a section record of the JSON Lines output
+type jsonlSection struct {
+	Book         string      `json:"book"`
+	Chapter      int         `json:"chapter"`
+	FirstVerse   int         `json:"first_verse"`
+	LastVerse    int         `json:"last_verse"`
+	SectionTitle string      `json:"section_title,omitempty"`
+	SectionLevel int         `json:"section_level,omitempty"`
+	Reference    string      `json:"reference,omitempty"`
+	Parsed       []ref.Range `json:"parsed_reference,omitempty"`
+	Text         string      `json:"text"`
+	SourceFile   string      `json:"source_file,omitempty"`
+}
+
+// WriteJSONL writes USFM documents as JSON Lines: one compact JSON object per line,
+// written to w as soon as it is built so that large Bibles can be piped into
+// tools such as jq or Spark without holding the output in memory.
+//
+// Verse records (the default) have the fields book, chapter, verse, section_title,
+// section_level, text, footnotes, reference, and source_file, and parsed_reference when the
+// documents were parsed with ParseReferences. Footnote and section records carry the same
+// location fields with the footnote or combined section text.
+// Text is written without USFM character markup, and book is the USFM book code.
+//
+// Example verse record:
+//
+//	{"book":"GEN","chapter":1,"verse":1,"section_title":"The Creation","section_level":1,"text":"In the beginning...","reference":"(John 1:1–5)","source_file":"GEN.usfm"}
+func WriteJSONL(w io.Writer, documents []*usfm.Document, options JSONLOptions) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+
+	record := options.Record
+	if record == "" {
+		record = JSONLVerse
+	}
+
+	for _, doc := range documents {
+		book := doc.BookCode()
+		if book == "" {
+			book = "UNKNOWN"
+		}
+
+		for _, chapter := range doc.Chapters {
+			for _, section := range chapter.Sections {
+				var err error
+				switch record {
+				case JSONLVerse:
+					err = writeJSONLVerses(encoder, doc, book, chapter.Number, section)
+				case JSONLFootnote:
+					err = writeJSONLFootnotes(encoder, doc, book, chapter.Number, section)
+				case JSONLSection:
+					err = writeJSONLSection(encoder, doc, book, chapter.Number, section)
+				default:
+					return fmt.Errorf("unsupported record type: %s", record)
+				}
+				if err != nil {
+					return fmt.Errorf("failed to write JSON Lines record: %w", err)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// writeJSONLVerses writes one record for each verse of a section
+func writeJSONLVerses(encoder *json.Encoder, doc *usfm.Document, book string, chapter int, section usfm.Section) error {
+	for _, verse := range section.Verses {
+		record := jsonlVerse{
+			Book:         book,
+			Chapter:      chapter,
+			Verse:        verse.Number,
+			SectionTitle: usfm.PlainText(section.Title),
+			SectionLevel: section.Level,
+			Text:         usfm.PlainText(verse.Text),
+			Reference:    section.Reference,
+			Parsed:       section.ParsedReference,
+			SourceFile:   doc.SourceFile,
+		}
+		for _, footnote := range verse.Footnotes {
+			footnote.Text = usfm.PlainText(footnote.Text)
+			record.Footnotes = append(record.Footnotes, footnote)
+		}
+
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeJSONLFootnotes writes one record for each footnote in a section
+func writeJSONLFootnotes(encoder *json.Encoder, doc *usfm.Document, book string, chapter int, section usfm.Section) error {
+	for _, verse := range section.Verses {
+		for _, footnote := range verse.Footnotes {
+			record := jsonlFootnote{
+				Book:       book,
+				Chapter:    chapter,
+				Verse:      verse.Number,
+				Caller:     footnote.Caller,
+				Reference:  footnote.Reference,
+				Parsed:     footnote.ParsedReference,
+				Text:       usfm.PlainText(footnote.Text),
+				SourceFile: doc.SourceFile,
+			}
+			if err := encoder.Encode(record); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// writeJSONLSection writes a single record for a section with the text of all its verses.
+// Sections without verses are skipped.
+func writeJSONLSection(encoder *json.Encoder, doc *usfm.Document, book string, chapter int, section usfm.Section) error {
+	if len(section.Verses) == 0 {
+		return nil
+	}
+
+	record := jsonlSection{
+		Book:         book,
+		Chapter:      chapter,
+		FirstVerse:   section.Verses[0].Number,
+		SectionTitle: usfm.PlainText(section.Title),
+		SectionLevel: section.Level,
+		Reference:    section.Reference,
+		Parsed:       section.ParsedReference,
+		SourceFile:   doc.SourceFile,
+	}
+
+	var texts []string
+	for _, verse := range section.Verses {
+		record.LastVerse = verse.Number
+		texts = append(texts, usfm.PlainText(verse.Text))
+	}
+	record.Text = strings.Join(texts, " ")
+
+	return encoder.Encode(record)
+}
@@ -0,0 +1,74 @@
+package formatter
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/arenzana/usfmp/pkg/usfm"
+)
+
+// failingWriter is an io.Writer that always fails
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+// TestWriteJSONL tests JSON Lines output for each record type
+func TestWriteJSONL(t *testing.T) {
+	doc := createTestDocument()
+	doc.Chapters[0].Sections[0].Verses[1].Text = `Now the \w earth|strong="H0776"\w* was <formless> & void.`
+
+	tests := []struct {
+		record   JSONLRecord
+		expected []string
+	}{
+		{JSONLVerse, []string{
+			`{"book":"GEN","chapter":1,"verse":1,"section_title":"The Creation","section_level":1,"text":"In the beginning God created the heavens and the earth.","footnotes":[{"caller":"+","reference":"1:1","text":"Hebrew: Elohim"}],"reference":"(John 1:1–5)","source_file":"test.sfm"}`,
+			`{"book":"GEN","chapter":1,"verse":2,"section_title":"The Creation","section_level":1,"text":"Now the earth was <formless> & void.","reference":"(John 1:1–5)","source_file":"test.sfm"}`,
+		}},
+		{JSONLFootnote, []string{
+			`{"book":"GEN","chapter":1,"verse":1,"caller":"+","reference":"1:1","text":"Hebrew: Elohim","source_file":"test.sfm"}`,
+		}},
+		{JSONLSection, []string{
+			`{"book":"GEN","chapter":1,"first_verse":1,"last_verse":2,"section_title":"The Creation","section_level":1,"reference":"(John 1:1–5)","text":"In the beginning God created the heavens and the earth. Now the earth was <formless> & void.","source_file":"test.sfm"}`,
+		}},
+	}
+
+	for _, test := range tests {
+		var buf bytes.Buffer
+		if err := WriteJSONL(&buf, []*usfm.Document{doc}, JSONLOptions{Record: test.record}); err != nil {
+			t.Fatalf("WriteJSONL(%s) failed: %v", test.record, err)
+		}
+
+		lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+		if len(lines) != len(test.expected) {
+			t.Fatalf("Expected %d %s records, got %d", len(test.expected), test.record, len(lines))
+		}
+		for i, line := range lines {
+			if !json.Valid([]byte(line)) {
+				t.Errorf("Line %d is not valid JSON: %s", i+1, line)
+			}
+			if line != test.expected[i] {
+				t.Errorf("Unexpected %s record.\nExpected: %s\nGot:      %s", test.record, test.expected[i], line)
+			}
+		}
+	}
+
+	if err := WriteJSONL(failingWriter{}, []*usfm.Document{doc}, JSONLOptions{}); err == nil {
+		t.Error("Expected error when the writer fails")
+	}
+}
+
+// TestParseJSONLRecord tests parsing of record type names
+func TestParseJSONLRecord(t *testing.T) {
+	if record, err := ParseJSONLRecord("Footnote"); err != nil || record != JSONLFootnote {
+		t.Errorf("Expected footnote record, got %q (%v)", record, err)
+	}
+	if _, err := ParseJSONLRecord("chapter"); err == nil {
+		t.Error("Expected error for unknown record type")
+	}
+}
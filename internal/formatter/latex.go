@@ -0,0 +1,207 @@
+package formatter
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/arenzana/usfmp/pkg/usfm"
+)
+
+// LaTeXOptions configures the LaTeX output.
+type LaTeXOptions struct {
+	Title            string // Title for the title page; no title page is written when empty
+	IncludeFootnotes bool   // Whether to write footnotes as \usfmfootnote
+}
+
+// latexPreamble is the document preamble with the macros used by the LaTeX output.
+// Typesetters can restyle the output by redefining these macros.
+// The document compiles with pdfLaTeX for Latin text; use XeLaTeX or LuaLaTeX
+// (which load fontspec) for Greek, Hebrew, and other scripts.
+const latexPreamble = `\documentclass[11pt]{book}
+\usepackage{iftex}
+\ifPDFTeX
+  \usepackage[utf8]{inputenc}
+  \usepackage[T1]{fontenc}
+\else
+  \usepackage{fontspec}
+\fi
+\usepackage{xcolor}
+
+\setlength{\parindent}{1em}
+\setlength{\parskip}{0pt}
+
+% Structure: \usfmbook{title}, \usfmchapter{number}, \usfmsection{level}{title}, \usfmreference{text}
+\newcommand{\usfmbook}[1]{\chapter*{#1}\addcontentsline{toc}{chapter}{#1}\markboth{#1}{#1}}
+\newcommand{\usfmchapter}[1]{\par\addvspace{\bigskipamount}\noindent{\Large\bfseries Chapter #1}\par\nopagebreak}
+\newcommand{\usfmsection}[2]{\par\addvspace{\medskipamount}\noindent{\ifnum#1=1\large\fi\bfseries #2}\par\nopagebreak}
+\newcommand{\usfmreference}[1]{\noindent{\small\itshape #1}\par\nopagebreak}
+
+% Paragraphs: \usfmp (indented), \usfmm (flush left), \usfmpi{level}, and poetry lines \usfmq{level}
+\newcommand{\usfmp}{\par\indent}
+\newcommand{\usfmm}{\par\noindent}
+\newcommand{\usfmpi}[1]{\par\hangindent=#1\parindent\hangafter=0\indent}
+\newcommand{\usfmq}[1]{\par\noindent\hangindent=\dimexpr#1\parindent+2\parindent\relax\hangafter=1\hspace*{#1\parindent}}
+
+% Verses and notes: \usfmverse{number}, \usfmfootnote{reference}{text}
+\newcommand{\usfmverse}[1]{\textsuperscript{#1}\,}
+\newcommand{\usfmfootnote}[2]{\footnote{\textbf{#1}~#2}}
+
+% Character styles: words of Jesus, divine name, translator additions
+\newcommand{\usfmwj}[1]{\textcolor{red!70!black}{#1}}
+\newcommand{\usfmnd}[1]{\textsc{#1}}
+\newcommand{\usfmadd}[1]{\textit{#1}}
+`
+
+// FormatLaTeX formats USFM documents as a complete LaTeX document for typesetting.
+// The document starts with a small preamble that defines a macro for each element,
+// so the layout can be changed by redefining macros rather than editing the text.
+//
+// The LaTeX output uses:
+//   - \usfmbook{title} for each document, which also adds it to the table of contents
+//   - \usfmchapter{N}, \usfmsection{level}{title}, and \usfmreference{text} for \c, \s1-\s3, and \r
+//   - \usfmp, \usfmm, \usfmpi{level}, and \usfmq{level} to start paragraphs and poetry lines
+//   - \usfmverse{N} before the verse text and \usfmfootnote{reference}{text} after it
+//   - \usfmwj, \usfmnd, and \usfmadd for \wj, \nd, and \add character markup
+//
+// LaTeX special characters in the text are escaped.
+func FormatLaTeX(documents []*usfm.Document, options LaTeXOptions) (string, error) {
+	var result strings.Builder
+	if err := WriteLaTeX(&result, documents, options); err != nil {
+		return "", err
+	}
+	return result.String(), nil
+}
+
+// WriteLaTeX writes USFM documents as a LaTeX document to w, in the layout described
+// for FormatLaTeX. The output is written one chapter at a time.
+func WriteLaTeX(w io.Writer, documents []*usfm.Document, options LaTeXOptions) error {
+	var result strings.Builder
+
+	result.WriteString(latexPreamble)
+	result.WriteString("\n\\begin{document}\n")
+
+	if options.Title != "" {
+		fmt.Fprintf(&result, "\\title{%s}\n\\author{}\n\\date{}\n\\maketitle\n", escapeLaTeX(options.Title))
+	}
+	if len(documents) > 1 {
+		result.WriteString("\\tableofcontents\n")
+	}
+
+	for _, doc := range documents {
+		fmt.Fprintf(&result, "\n\\usfmbook{%s}\n", escapeLaTeX(bookTitle(doc)))
+
+		for _, chapter := range doc.Chapters {
+			fmt.Fprintf(&result, "\n\\usfmchapter{%d}\n", chapter.Number)
+
+			for _, section := range chapter.Sections {
+				writeLaTeXSection(&result, section, options)
+			}
+			if err := flushOutput(w, &result); err != nil {
+				return err
+			}
+		}
+	}
+
+	result.WriteString("\n\\end{document}\n")
+	return flushOutput(w, &result)
+}
+
+// writeLaTeXSection writes a section heading, its cross-reference, and its verses
+func writeLaTeXSection(result *strings.Builder, section usfm.Section, options LaTeXOptions) {
+	if section.Title != "" {
+		level := section.Level
+		if level < 1 || level > 3 {
+			level = 1
+		}
+		fmt.Fprintf(result, "\\usfmsection{%d}{%s}\n", level, latexInline(section.Title))
+	}
+	if section.Reference != "" {
+		fmt.Fprintf(result, "\\usfmreference{%s}\n", escapeLaTeX(section.Reference))
+	}
+
+	// Verses flow into paragraphs; a line break starts a new paragraph or poetry line
+	started := false
+	for _, verse := range section.Verses {
+		lines := verse.Lines
+		if len(lines) == 0 {
+			lines = []usfm.Line{{Text: verse.Text}}
+		}
+
+		for i, line := range lines {
+			if line.Break || !started {
+				if started {
+					result.WriteString("\n")
+				}
+				result.WriteString(latexParagraph(line))
+				started = true
+			}
+			result.WriteString(" ")
+
+			if i == 0 {
+				fmt.Fprintf(result, "\\usfmverse{%d}", verse.Number)
+			}
+			result.WriteString(latexInline(strings.TrimSpace(line.Text)))
+		}
+
+		if options.IncludeFootnotes {
+			for _, footnote := range verse.Footnotes {
+				fmt.Fprintf(result, "\\usfmfootnote{%s}{%s}", escapeLaTeX(footnote.Reference), latexInline(footnote.Text))
+			}
+		}
+	}
+	if started {
+		result.WriteString("\n")
+	}
+}
+
+// latexParagraph returns the macro that starts a paragraph or poetry line for a line's marker
+func latexParagraph(line usfm.Line) string {
+	switch {
+	case line.IsPoetry():
+		return fmt.Sprintf("\\usfmq{%d}", line.Indent())
+	case strings.HasPrefix(line.Marker, "pi"):
+		return fmt.Sprintf("\\usfmpi{%d}", line.Indent())
+	case line.Marker == "p" || line.Marker == "":
+		return "\\usfmp"
+	default:
+		return "\\usfmm"
+	}
+}
+
+// latexInline converts text with USFM character markup into escaped LaTeX
+func latexInline(text string) string {
+	return renderInline(text, latexElement, escapeLaTeX)
+}
+
+// latexElement returns the opening and closing LaTeX for a USFM character marker.
+// Markers without a LaTeX equivalent return empty strings so that only their text is kept.
+func latexElement(marker string, attributes map[string]string) (string, string) {
+	switch marker {
+	case "wj":
+		return "\\usfmwj{", "}"
+	case "nd":
+		return "\\usfmnd{", "}"
+	case "add":
+		return "\\usfmadd{", "}"
+	case "it", "em", "tl":
+		return "\\emph{", "}"
+	case "bd":
+		return "\\textbf{", "}"
+	case "sc":
+		return "\\textsc{", "}"
+	default:
+		return "", ""
+	}
+}
+
+// latexEscaper escapes the characters that have a special meaning in LaTeX
+var latexEscaper = strings.NewReplacer(
+	`\`, `\textbackslash{}`, `{`, `\{`, `}`, `\}`, `$`, `\$`, `&`, `\&`,
+	`#`, `\#`, `_`, `\_`, `%`, `\%`, `^`, `\textasciicircum{}`, `~`, `\textasciitilde{}`,
+)
+
+// escapeLaTeX escapes text so that it is typeset literally by LaTeX
+func escapeLaTeX(text string) string {
+	return latexEscaper.Replace(text)
+}
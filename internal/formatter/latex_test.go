@@ -0,0 +1,73 @@
+package formatter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/arenzana/usfmp/pkg/usfm"
+)
+
+// TestFormatLaTeX tests LaTeX formatting and escaping
+func TestFormatLaTeX(t *testing.T) {
+	doc := createTestDocument()
+	doc.Chapters[0].Sections[0].Verses[0].Footnotes[0].Text = "Hebrew: Elohim; 50% of $5 & #1_a^b~{c}"
+	doc.Chapters[0].Sections[0].Verses[1].Lines = []usfm.Line{
+		{Marker: "q1", Text: `Now the \nd Lord\nd* said,`, Break: true},
+		{Marker: "q2", Text: `\wj \+w I|strong="G1473"\+w* \add am\add* \ here\wj*`, Break: true},
+	}
+	doc.Chapters[0].Sections = append(doc.Chapters[0].Sections, usfm.Section{
+		Level: 2,
+		Title: "Day & Night",
+		Verses: []usfm.Verse{
+			{Number: 3, Lines: []usfm.Line{{Marker: "m", Text: "And God said.", Break: true}}},
+			{Number: 4, Lines: []usfm.Line{{Marker: "pi2", Text: "Indented.", Break: true}}},
+		},
+	})
+
+	result, err := FormatLaTeX([]*usfm.Document{doc}, LaTeXOptions{Title: "Test Bible", IncludeFootnotes: true})
+	if err != nil {
+		t.Fatalf("FormatLaTeX failed: %v", err)
+	}
+
+	expected := []string{
+		`\documentclass`,
+		`\newcommand{\usfmverse}`,
+		`\title{Test Bible}`,
+		`\usfmbook{Genesis}`,
+		`\usfmchapter{1}`,
+		`\usfmsection{1}{The Creation}`,
+		`\usfmreference{(John 1:1–5)}`,
+		`\usfmp \usfmverse{1}In the beginning God created the heavens and the earth.` +
+			`\usfmfootnote{1:1}{Hebrew: Elohim; 50\% of \$5 \& \#1\_a\textasciicircum{}b\textasciitilde{}\{c\}}`,
+		`\usfmq{1} \usfmverse{2}Now the \usfmnd{Lord} said,`,
+		`\usfmq{2} \usfmwj{I \usfmadd{am} \textbackslash{} here}`,
+		`\usfmsection{2}{Day \& Night}`,
+		`\usfmm \usfmverse{3}And God said.`,
+		`\usfmpi{2} \usfmverse{4}Indented.`,
+		`\end{document}`,
+	}
+	for _, exp := range expected {
+		if !strings.Contains(result, exp) {
+			t.Errorf("LaTeX output should contain '%s'", exp)
+		}
+	}
+
+	if strings.Contains(result, `\tableofcontents`) {
+		t.Error("A single book should not have a table of contents")
+	}
+
+	// Footnotes are optional, and the title page is only written with a title
+	result, err = FormatLaTeX([]*usfm.Document{doc, doc}, LaTeXOptions{})
+	if err != nil {
+		t.Fatalf("FormatLaTeX failed: %v", err)
+	}
+	if strings.Contains(result, `\usfmfootnote{1:1}`) {
+		t.Error("Footnotes should not be written when disabled")
+	}
+	if strings.Contains(result, `\maketitle`) {
+		t.Error("Title page should not be written without a title")
+	}
+	if !strings.Contains(result, `\tableofcontents`) {
+		t.Error("Multiple books should have a table of contents")
+	}
+}
@@ -0,0 +1,275 @@
+package formatter
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/arenzana/usfmp/pkg/ref"
+	"github.com/arenzana/usfmp/pkg/usfm"
+)
+
+// MarkdownOptions configures the Markdown output.
+type MarkdownOptions struct {
+	IncludeFootnotes bool // Whether to write footnotes as Markdown footnotes ([^1])
+}
+
+// FormatMarkdown formats USFM documents as CommonMark with GitHub Flavored Markdown footnotes.
+//
+// The Markdown output uses:
+//   - "# Book" and "## Chapter N" headings, and "###" to "#####" for \s1 to \s3 section headings
+//   - Cross-references in italics below their section heading
+//   - Bold superscript verse numbers (**<sup>1</sup>**) within running paragraphs
+//   - Poetry lines (\q1, \q2) as blockquotes, one line per poetry line, indented by level
+//   - Footnote references ([^1]) with their definitions at the end of each chapter
+//   - Italics for translator additions (\add) and other italic character markup
+func FormatMarkdown(documents []*usfm.Document, options MarkdownOptions) (string, error) {
+	var result strings.Builder
+	if err := WriteMarkdown(&result, documents, options); err != nil {
+		return "", err
+	}
+	return result.String(), nil
+}
+
+// WriteMarkdown writes USFM documents as Markdown to w, in the layout described
+// for FormatMarkdown. The output is written one chapter at a time.
+func WriteMarkdown(w io.Writer, documents []*usfm.Document, options MarkdownOptions) error {
+	var result strings.Builder
+	noteNumber := 0
+
+	for i, doc := range documents {
+		if i > 0 {
+			result.WriteString("\n")
+		}
+		fmt.Fprintf(&result, "# %s\n", escapeMarkdown(bookTitle(doc)))
+
+		for _, chapter := range doc.Chapters {
+			fmt.Fprintf(&result, "\n## Chapter %d\n", chapter.Number)
+			writeMarkdownChapter(&result, chapter, options, &noteNumber)
+			if err := flushOutput(w, &result); err != nil {
+				return err
+			}
+		}
+	}
+
+	return flushOutput(w, &result)
+}
+
+// FormatMarkdownChapters formats each chapter of the USFM documents as its own Markdown file,
+// for note-taking apps such as Obsidian that work with a folder of notes.
+// The chapters of a book are written into a folder named after the book (e.g., "01-GEN"),
+// and each file is named after the book title and chapter (e.g., "01-GEN/Genesis 1.md").
+// Chapters are laid out as described for FormatMarkdown, with footnotes numbered per file.
+// Each file ends with links to the previous and next chapter in canonical order; when the
+// documents contain the same book more than once, links go to the chapters of its first copy.
+func FormatMarkdownChapters(documents []*usfm.Document, options MarkdownOptions) ([]OutputFile, error) {
+	var files []OutputFile
+	names := bookFileNames(documents, "md")
+
+	// Name the chapter files first so that each file can link to its neighbours
+	fileNames := make([][]string, len(documents))
+	chapterFiles := make(map[ref.Reference]string)
+	for i, doc := range documents {
+		folder := strings.TrimSuffix(names[i], ".md")
+		fileTitle := strings.NewReplacer("/", "-", "\\", "-", ":", "-").Replace(bookTitle(doc))
+		for _, chapter := range doc.Chapters {
+			name := fmt.Sprintf("%s/%s %d.md", folder, fileTitle, chapter.Number)
+			fileNames[i] = append(fileNames[i], name)
+			if reference := (ref.Reference{Book: doc.BookCode(), Chapter: chapter.Number}); chapterFiles[reference] == "" {
+				chapterFiles[reference] = name
+			}
+		}
+	}
+	bible, err := usfm.NewBible("", firstBooks(documents))
+	if err != nil {
+		return nil, err
+	}
+
+	for i, doc := range documents {
+		title := bookTitle(doc)
+
+		for j, chapter := range doc.Chapters {
+			var result strings.Builder
+			noteNumber := 0
+
+			fmt.Fprintf(&result, "# %s %d\n", escapeMarkdown(title), chapter.Number)
+			writeMarkdownChapter(&result, chapter, options, &noteNumber)
+			if doc.BookCode() != "" {
+				writeMarkdownNavigation(&result, bible, ref.Reference{Book: doc.BookCode(), Chapter: chapter.Number}, chapterFiles)
+			}
+
+			files = append(files, OutputFile{
+				Name: fileNames[i][j],
+				Data: []byte(result.String()),
+			})
+		}
+	}
+
+	return files, nil
+}
+
+// writeMarkdownNavigation writes links to the files of the previous and next chapter
+func writeMarkdownNavigation(result *strings.Builder, bible *usfm.Bible, chapter ref.Reference, chapterFiles map[ref.Reference]string) {
+	var links []string
+	if previous, ok := bible.PreviousChapter(chapter); ok {
+		links = append(links, markdownChapterLink("← ", previous, "", chapterFiles))
+	}
+	if next, ok := bible.NextChapter(chapter); ok {
+		links = append(links, markdownChapterLink("", next, " →", chapterFiles))
+	}
+	if len(links) > 0 {
+		fmt.Fprintf(result, "\n---\n\n%s\n", strings.Join(links, " | "))
+	}
+}
+
+// markdownChapterLink links to the file of a chapter relative to another chapter file
+// (e.g., "[← Genesis 1](../01-GEN/Genesis%201.md)")
+func markdownChapterLink(prefix string, chapter ref.Reference, suffix string, chapterFiles map[ref.Reference]string) string {
+	name := chapterFiles[chapter]
+	parts := strings.Split(name, "/")
+	for i, part := range parts {
+		parts[i] = url.PathEscape(part)
+	}
+	label := strings.TrimSuffix(path.Base(name), ".md")
+	return fmt.Sprintf("[%s%s%s](../%s)", prefix, escapeMarkdown(label), suffix, strings.Join(parts, "/"))
+}
+
+// markdownBlock is a paragraph or poetry line waiting to be written
+type markdownBlock struct {
+	marker string
+	text   strings.Builder
+}
+
+// writeMarkdownChapter writes the sections, verses, and footnote definitions of a chapter.
+// Footnotes are numbered from noteNumber, which is advanced past the notes written.
+func writeMarkdownChapter(result *strings.Builder, chapter usfm.Chapter, options MarkdownOptions, noteNumber *int) {
+	var notes []string
+
+	for _, section := range chapter.Sections {
+		if section.Title != "" {
+			level := section.Level
+			if level < 1 || level > 3 {
+				level = 1
+			}
+			fmt.Fprintf(result, "\n%s %s\n", strings.Repeat("#", level+2), markdownInline(section.Title))
+		}
+		if section.Reference != "" {
+			fmt.Fprintf(result, "\n*%s*\n", escapeMarkdown(section.Reference))
+		}
+
+		// Verses flow into paragraphs; a line break starts a new paragraph or poetry line
+		var blocks []*markdownBlock
+		for _, verse := range section.Verses {
+			lines := verse.Lines
+			if len(lines) == 0 {
+				lines = []usfm.Line{{Text: verse.Text}}
+			}
+
+			for i, line := range lines {
+				if line.Break || len(blocks) == 0 {
+					blocks = append(blocks, &markdownBlock{marker: line.Marker})
+				}
+				block := blocks[len(blocks)-1]
+				if block.text.Len() > 0 {
+					block.text.WriteString(" ")
+				}
+				if i == 0 {
+					fmt.Fprintf(&block.text, "**<sup>%d</sup>** ", verse.Number)
+				}
+				block.text.WriteString(markdownInline(line.Text))
+			}
+
+			if options.IncludeFootnotes {
+				block := blocks[len(blocks)-1]
+				for _, footnote := range verse.Footnotes {
+					*noteNumber++
+					fmt.Fprintf(&block.text, "[^%d]", *noteNumber)
+
+					note := fmt.Sprintf("[^%d]: ", *noteNumber)
+					if footnote.Reference != "" {
+						note += "**" + escapeMarkdown(footnote.Reference) + "** "
+					}
+					notes = append(notes, note+markdownInline(footnote.Text))
+				}
+			}
+		}
+
+		writeMarkdownBlocks(result, blocks)
+	}
+
+	if len(notes) > 0 {
+		result.WriteString("\n")
+		for _, note := range notes {
+			result.WriteString(note + "\n")
+		}
+	}
+}
+
+// writeMarkdownBlocks writes paragraphs separated by blank lines, and runs of
+// consecutive poetry lines as a single blockquote with hard line breaks
+func writeMarkdownBlocks(result *strings.Builder, blocks []*markdownBlock) {
+	for i, block := range blocks {
+		line := usfm.Line{Marker: block.marker}
+		text := strings.TrimSpace(block.text.String())
+
+		if !line.IsPoetry() {
+			fmt.Fprintf(result, "\n%s\n", text)
+			continue
+		}
+
+		previousPoetry := i > 0 && (usfm.Line{Marker: blocks[i-1].marker}).IsPoetry()
+		nextPoetry := i < len(blocks)-1 && (usfm.Line{Marker: blocks[i+1].marker}).IsPoetry()
+		if !previousPoetry {
+			result.WriteString("\n")
+		}
+
+		// Deeper poetry levels are indented with em spaces, which Markdown does not collapse
+		result.WriteString("> " + strings.Repeat("&emsp;", line.Indent()-1) + text)
+		if nextPoetry {
+			result.WriteString("\\")
+		}
+		result.WriteString("\n")
+	}
+}
+
+// markdownInline converts text with USFM character markup into escaped Markdown,
+// with italics for translator additions and other italic markers and bold for \bd
+func markdownInline(text string) string {
+	var result strings.Builder
+
+	for _, span := range usfm.ParseInline(text) {
+		content := escapeMarkdown(span.Text)
+		delimiter := ""
+		switch {
+		case span.Has("bd"):
+			delimiter = "**"
+		case span.Has("add"), span.Has("it"), span.Has("em"), span.Has("tl"):
+			delimiter = "*"
+		}
+
+		// Emphasis delimiters must touch the text, so surrounding spaces are kept outside
+		trimmed := strings.TrimSpace(content)
+		if delimiter == "" || trimmed == "" {
+			result.WriteString(content)
+			continue
+		}
+		leading := content[:strings.Index(content, trimmed)]
+		trailing := content[len(leading)+len(trimmed):]
+		result.WriteString(leading + delimiter + trimmed + delimiter + trailing)
+	}
+
+	return result.String()
+}
+
+// markdownEscaper escapes characters that Markdown could interpret as syntax
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", `*`, `\*`, `_`, `\_`, `[`, `\[`, `]`, `\]`,
+	`<`, `\<`, `>`, `\>`, `#`, `\#`, `|`, `\|`,
+)
+
+// escapeMarkdown escapes text so that it is rendered literally in Markdown
+func escapeMarkdown(text string) string {
+	return markdownEscaper.Replace(text)
+}
@@ -0,0 +1,95 @@
+package formatter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/arenzana/usfmp/pkg/usfm"
+)
+
+// TestFormatMarkdown tests Markdown formatting
+func TestFormatMarkdown(t *testing.T) {
+	doc := createTestDocument()
+	doc.Chapters[0].Sections[0].Verses = append(doc.Chapters[0].Sections[0].Verses,
+		usfm.Verse{Number: 3, Lines: []usfm.Line{
+			{Marker: "q1", Text: `The \add *Lord*\add* is`, Break: true},
+			{Marker: "q2", Text: "my shepherd.", Break: true},
+		}},
+		usfm.Verse{Number: 4, Lines: []usfm.Line{{Marker: "m", Text: "After the psalm.", Break: true}}},
+	)
+
+	result, err := FormatMarkdown([]*usfm.Document{doc}, MarkdownOptions{IncludeFootnotes: true})
+	if err != nil {
+		t.Fatalf("FormatMarkdown failed: %v", err)
+	}
+
+	expected := "# Genesis\n" +
+		"\n## Chapter 1\n" +
+		"\n### The Creation\n" +
+		"\n*(John 1:1–5)*\n" +
+		"\n**<sup>1</sup>** In the beginning God created the heavens and the earth.[^1] **<sup>2</sup>** Now the earth was formless and void.\n" +
+		"\n> **<sup>3</sup>** The *\\*Lord\\** is\\\n" +
+		"> &emsp;my shepherd.\n" +
+		"\n**<sup>4</sup>** After the psalm.\n" +
+		"\n[^1]: **1:1** Hebrew: Elohim\n"
+	if result != expected {
+		t.Errorf("Unexpected Markdown output.\nExpected:\n%s\nGot:\n%s", expected, result)
+	}
+
+	// Footnotes are optional
+	result, err = FormatMarkdown([]*usfm.Document{doc}, MarkdownOptions{})
+	if err != nil {
+		t.Fatalf("FormatMarkdown failed: %v", err)
+	}
+	if strings.Contains(result, "[^") {
+		t.Error("Footnotes should not be written when disabled")
+	}
+}
+
+// TestFormatMarkdownChapters tests per-chapter Markdown files
+func TestFormatMarkdownChapters(t *testing.T) {
+	doc := createTestDocument()
+	doc.Chapters = append(doc.Chapters, usfm.Chapter{
+		Number: 2,
+		Sections: []usfm.Section{{Verses: []usfm.Verse{
+			{Number: 1, Text: "Thus the heavens were completed.", Footnotes: []usfm.Footnote{{Text: "Note"}}},
+		}}},
+	})
+
+	files, err := FormatMarkdownChapters([]*usfm.Document{doc}, MarkdownOptions{IncludeFootnotes: true})
+	if err != nil {
+		t.Fatalf("FormatMarkdownChapters failed: %v", err)
+	}
+
+	if len(files) != 2 {
+		t.Fatalf("Expected 2 files, got %d", len(files))
+	}
+	if files[0].Name != "01-GEN/Genesis 1.md" || files[1].Name != "01-GEN/Genesis 2.md" {
+		t.Errorf("Unexpected file names %s, %s", files[0].Name, files[1].Name)
+	}
+
+	second := string(files[1].Data)
+	if !strings.HasPrefix(second, "# Genesis 2\n") {
+		t.Error("Chapter file should start with the book and chapter heading")
+	}
+	// Footnotes are numbered per file
+	if !strings.Contains(second, "completed.[^1]") || !strings.Contains(second, "[^1]: Note") {
+		t.Error("Chapter file footnotes should be numbered from 1")
+	}
+
+	// Chapters link to their neighbours
+	if !strings.HasSuffix(string(files[0].Data), "\n---\n\n[Genesis 2 →](../01-GEN/Genesis%202.md)\n") {
+		t.Errorf("Expected a link to the next chapter, got %q", files[0].Data)
+	}
+	if !strings.HasSuffix(second, "\n---\n\n[← Genesis 1](../01-GEN/Genesis%201.md)\n") {
+		t.Errorf("Expected a link to the previous chapter, got %q", second)
+	}
+
+	// A book given twice links to the chapters of its first copy
+	if files, err = FormatMarkdownChapters([]*usfm.Document{doc, doc}, MarkdownOptions{}); err != nil {
+		t.Fatalf("FormatMarkdownChapters failed: %v", err)
+	}
+	if len(files) != 4 || !strings.HasSuffix(string(files[2].Data), "[Genesis 2 →](../01-GEN/Genesis%202.md)\n") {
+		t.Errorf("Expected the second copy to link to the first, got %d files", len(files))
+	}
+}
@@ -0,0 +1,69 @@
+package formatter
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/arenzana/usfmp/pkg/usfm"
+)
+
+// FormatOpenSong formats USFM documents as an OpenSong XML Bible.
+//
+// OpenSong Bibles only carry book names, chapter numbers, and verse text, so section
+// headings, cross-references, and footnotes are not included. Books are written in
+// canonical order using their English names; documents whose \id code is not a
+// canonical book are skipped, and so are later copies of a book. Verse text is written
+// without USFM character markup.
+//
+// OpenSong verse numbers cannot be ranges, so the text of a verse bridge such as \v 1-2
+// is written as the first verse of the bridge and the other verses are left out.
+//
+// Example output:
+//
+//	<bible>
+//	  <b n="Genesis">
+//	    <c n="1">
+//	      <v n="1">In the beginning...</v>
+func FormatOpenSong(documents []*usfm.Document) (string, error) {
+	var result strings.Builder
+	if err := WriteOpenSong(&result, documents); err != nil {
+		return "", err
+	}
+	return result.String(), nil
+}
+
+// WriteOpenSong writes USFM documents as an OpenSong XML Bible to w, in the layout described
+// for FormatOpenSong. The output is written one chapter at a time.
+func WriteOpenSong(w io.Writer, documents []*usfm.Document) error {
+	var result strings.Builder
+
+	result.WriteString(xmlHeader)
+	result.WriteString("<bible>\n")
+
+	for _, entry := range canonicalBooks(documents) {
+		fmt.Fprintf(&result, "  <b n=\"%s\">\n", escapeXML(entry.Book.Name))
+
+		for _, chapter := range entry.Document.Chapters {
+			fmt.Fprintf(&result, "    <c n=\"%d\">\n", chapter.Number)
+
+			for _, section := range chapter.Sections {
+				for _, verse := range section.Verses {
+					fmt.Fprintf(&result, "      <v n=\"%d\">%s</v>\n",
+						verse.Number, escapeXML(usfm.PlainText(verse.Text)))
+				}
+			}
+
+			result.WriteString("    </c>\n")
+			if err := flushOutput(w, &result); err != nil {
+				return err
+			}
+		}
+
+		result.WriteString("  </b>\n")
+	}
+
+	result.WriteString("</bible>\n")
+
+	return flushOutput(w, &result)
+}
@@ -0,0 +1,221 @@
+package formatter
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/arenzana/usfmp/pkg/ref"
+	"github.com/arenzana/usfmp/pkg/usfm"
+)
+
+// FormatOSIS formats USFM documents as an OSIS 2.1 XML document.
+// All documents are written into a single <osisText> element, one <div type="book"> per document,
+// so that multiple books can be consumed by SWORD and other OSIS-based tools in one pass.
+//
+// The OSIS output uses:
+//   - osisIDs built from the OSIS book name, chapter, and verse (e.g., "Gen.1.1"), listing
+//     every verse of a verse bridge ("Gen.1.1 Gen.1.2")
+//   - <div type="section"> with a <title> for each section
+//   - <title type="parallel"> with a <reference osisRef="..."> for each \r cross-reference
+//   - <title type="psalm" canonical="true"> for \d descriptive titles that follow a section heading
+//   - <note> elements for footnotes, placed at the end of the verse or descriptive title they belong to
+//   - <w lemma="strong:...">, <q who="Jesus">, <divineName>, and <transChange> for character markup
+//
+// Books that are not in the canonical registry use their USFM code as the OSIS book name.
+func FormatOSIS(documents []*usfm.Document) (string, error) {
+	var result strings.Builder
+	if err := WriteOSIS(&result, documents); err != nil {
+		return "", err
+	}
+	return result.String(), nil
+}
+
+// WriteOSIS writes USFM documents as an OSIS 2.1 XML document to w, in the layout described
+// for FormatOSIS. The output is written one chapter at a time.
+func WriteOSIS(w io.Writer, documents []*usfm.Document) error {
+	var result strings.Builder
+
+	result.WriteString(xmlHeader)
+	result.WriteString(`<osis xmlns="http://www.bibletechnologies.net/2003/OSIS/namespace"` +
+		` xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance"` +
+		` xsi:schemaLocation="http://www.bibletechnologies.net/2003/OSIS/namespace` +
+		` http://www.bibletechnologies.net/osisCore.2.1.1.xsd">` + "\n")
+	result.WriteString(`  <osisText osisIDWork="Bible" osisRefWork="Bible">` + "\n")
+	result.WriteString("    <header>\n")
+	result.WriteString(`      <work osisWork="Bible">` + "\n")
+	result.WriteString("        <title>Bible</title>\n")
+	result.WriteString("        <refSystem>Bible</refSystem>\n")
+	result.WriteString("      </work>\n")
+	result.WriteString("    </header>\n")
+
+	for _, doc := range documents {
+		bookID := osisBookID(doc)
+		code := doc.BookCode()
+
+		fmt.Fprintf(&result, "    <div type=\"book\" osisID=\"%s\">\n", escapeXML(bookID))
+		if doc.MainTitle != "" {
+			fmt.Fprintf(&result, "      <title type=\"main\">%s</title>\n", escapeXML(doc.MainTitle))
+		}
+
+		for _, chapter := range doc.Chapters {
+			chapterID := fmt.Sprintf("%s.%d", bookID, chapter.Number)
+			fmt.Fprintf(&result, "      <chapter osisID=\"%s\">\n", escapeXML(chapterID))
+
+			for _, section := range chapter.Sections {
+				hasTitle := section.Title != "" || section.Reference != ""
+				indent := "        "
+				if hasTitle {
+					result.WriteString("        <div type=\"section\">\n")
+					indent = "          "
+					if section.Title != "" {
+						fmt.Fprintf(&result, "%s<title>%s</title>\n", indent, escapeXML(section.Title))
+					}
+					references, descriptiveTitle := sectionReferences(section, code, chapter.Number)
+					if len(references) > 0 {
+						fmt.Fprintf(&result, "%s<title type=\"parallel\">%s</title>\n", indent, osisReferences(references))
+					}
+					if descriptiveTitle != "" {
+						fmt.Fprintf(&result, "%s<title type=\"psalm\" canonical=\"true\">%s", indent, escapeXML(descriptiveTitle))
+						for _, footnote := range section.Footnotes {
+							writeOSISNote(&result, chapterID, footnote)
+						}
+						result.WriteString("</title>\n")
+					} else if len(section.Footnotes) > 0 {
+						result.WriteString(indent)
+						for _, footnote := range section.Footnotes {
+							writeOSISNote(&result, chapterID, footnote)
+						}
+						result.WriteString("\n")
+					}
+				}
+
+				for _, verse := range section.Verses {
+					verseID := fmt.Sprintf("%s.%d", chapterID, verse.Number)
+					verseIDs := []string{verseID}
+					for number := verse.Number + 1; number <= verse.EndNumber; number++ {
+						verseIDs = append(verseIDs, fmt.Sprintf("%s.%d", chapterID, number))
+					}
+					noteRef := verseID
+					if len(verseIDs) > 1 {
+						noteRef += "-" + verseIDs[len(verseIDs)-1]
+					}
+					fmt.Fprintf(&result, "%s<verse osisID=\"%s\">", indent, escapeXML(strings.Join(verseIDs, " ")))
+					result.WriteString(osisInline(verse.Text))
+
+					for _, footnote := range verse.Footnotes {
+						writeOSISNote(&result, noteRef, footnote)
+					}
+
+					result.WriteString("</verse>\n")
+				}
+
+				if hasTitle {
+					result.WriteString("        </div>\n")
+				}
+			}
+
+			result.WriteString("      </chapter>\n")
+			if err := flushOutput(w, &result); err != nil {
+				return err
+			}
+		}
+
+		result.WriteString("    </div>\n")
+	}
+
+	result.WriteString("  </osisText>\n")
+	result.WriteString("</osis>\n")
+
+	return flushOutput(w, &result)
+}
+
+// osisBookID returns the OSIS book name for a document, falling back to its USFM code
+func osisBookID(doc *usfm.Document) string {
+	code := doc.BookCode()
+	if book, ok := usfm.LookupBook(code); ok {
+		return book.OSIS
+	}
+	if code == "" {
+		return "UNKNOWN"
+	}
+	return code
+}
+
+// writeOSISNote writes a footnote as a <note> on the verses or chapter named by osisRef
+func writeOSISNote(result *strings.Builder, osisRef string, footnote usfm.Footnote) {
+	fmt.Fprintf(result, "<note osisRef=\"%s\" n=\"%s\">", escapeXML(osisRef), escapeXML(footnote.Caller))
+	if footnote.Reference != "" {
+		fmt.Fprintf(result, "<reference type=\"annotateRef\">%s</reference> ", escapeXML(footnote.Reference))
+	}
+	result.WriteString(osisInline(footnote.Text))
+	result.WriteString("</note>")
+}
+
+// osisReferences returns one <reference> element per cross-reference, separated by semicolons
+func osisReferences(ranges []ref.Range) string {
+	items := make([]string, len(ranges))
+	for i, r := range ranges {
+		items[i] = fmt.Sprintf("<reference osisRef=\"%s\">%s</reference>",
+			escapeXML(ref.OSIS([]ref.Range{r})), escapeXML(ref.Format([]ref.Range{r}, ref.Default)))
+	}
+	return strings.Join(items, "; ")
+}
+
+// osisInline converts verse text with USFM character markup into escaped OSIS inline elements
+func osisInline(text string) string {
+	var result strings.Builder
+
+	for _, span := range usfm.ParseInline(text) {
+		var closers []string
+		for i, marker := range span.Markers {
+			var attributes map[string]string
+			if i == len(span.Markers)-1 {
+				attributes = span.Attributes
+			}
+			open, close := osisElement(marker, attributes)
+			result.WriteString(open)
+			closers = append(closers, close)
+		}
+
+		result.WriteString(escapeXML(span.Text))
+
+		for i := len(closers) - 1; i >= 0; i-- {
+			result.WriteString(closers[i])
+		}
+	}
+
+	return result.String()
+}
+
+// osisElement returns the opening and closing OSIS tags for a USFM character marker.
+// Markers without an OSIS equivalent return empty tags so that only their text is kept.
+func osisElement(marker string, attributes map[string]string) (string, string) {
+	switch marker {
+	case "w":
+		if strong := attributes["strong"]; strong != "" {
+			var lemmas []string
+			for _, number := range strings.Split(strong, ",") {
+				lemmas = append(lemmas, "strong:"+strings.TrimSpace(number))
+			}
+			return fmt.Sprintf("<w lemma=\"%s\">", escapeXML(strings.Join(lemmas, " "))), "</w>"
+		}
+		return "<w>", "</w>"
+	case "wj":
+		return `<q who="Jesus" marker="">`, "</q>"
+	case "nd":
+		return "<divineName>", "</divineName>"
+	case "add":
+		return `<transChange type="added">`, "</transChange>"
+	case "tl":
+		return "<foreign>", "</foreign>"
+	case "it", "em":
+		return `<hi type="italic">`, "</hi>"
+	case "bd":
+		return `<hi type="bold">`, "</hi>"
+	case "sc":
+		return `<hi type="small-caps">`, "</hi>"
+	default:
+		return "", ""
+	}
+}
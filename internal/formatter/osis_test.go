@@ -0,0 +1,142 @@
+package formatter
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/arenzana/usfmp/pkg/usfm"
+)
+
+// TestFormatOSIS tests OSIS XML formatting
+func TestFormatOSIS(t *testing.T) {
+	doc := createTestDocument()
+	doc.Chapters[0].Sections[0].Verses[1].Text = `Now the \nd LORD\nd* \add was\add* \w formless|strong="H8414"\w* & void.`
+	documents := []*usfm.Document{doc}
+
+	result, err := FormatOSIS(documents)
+	if err != nil {
+		t.Fatalf("FormatOSIS failed: %v", err)
+	}
+
+	// The output must be well-formed XML
+	decoder := xml.NewDecoder(strings.NewReader(result))
+	for {
+		if _, err := decoder.Token(); err != nil {
+			if err != io.EOF {
+				t.Fatalf("OSIS output is not well-formed XML: %v", err)
+			}
+			break
+		}
+	}
+
+	expected := []string{
+		`<osisText osisIDWork="Bible" osisRefWork="Bible">`,
+		`<div type="book" osisID="Gen">`,
+		`<chapter osisID="Gen.1">`,
+		`<div type="section">`,
+		`<title>The Creation</title>`,
+		`<title type="parallel"><reference osisRef="John.1.1-John.1.5">John 1:1–5</reference></title>`,
+		`<verse osisID="Gen.1.1">In the beginning`,
+		`<note osisRef="Gen.1.1" n="+"><reference type="annotateRef">1:1</reference> Hebrew: Elohim</note>`,
+		`<divineName>LORD</divineName>`,
+		`<transChange type="added">was</transChange>`,
+		`<w lemma="strong:H8414">formless</w> &amp; void.`,
+	}
+
+	for _, exp := range expected {
+		if !strings.Contains(result, exp) {
+			t.Errorf("OSIS output should contain '%s'", exp)
+		}
+	}
+}
+
+// TestFormatOSISMultipleDocuments tests that multiple books share one osisText
+func TestFormatOSISMultipleDocuments(t *testing.T) {
+	doc1 := createTestDocument()
+	doc2 := createTestDocument()
+	doc2.ID = "EXO"
+
+	result, err := FormatOSIS([]*usfm.Document{doc1, doc2})
+	if err != nil {
+		t.Fatalf("FormatOSIS failed: %v", err)
+	}
+
+	if strings.Count(result, "<osisText") != 1 {
+		t.Error("Multiple documents should be written into a single osisText")
+	}
+
+	if !strings.Contains(result, `osisID="Gen"`) || !strings.Contains(result, `osisID="Exod"`) {
+		t.Error("OSIS output should contain both books")
+	}
+}
+
+// TestFormatOSISVerseBridge tests that a verse bridge lists the osisID of every verse it covers
+func TestFormatOSISVerseBridge(t *testing.T) {
+	doc := createTestDocument()
+	verses := doc.Chapters[0].Sections[0].Verses
+	verses[0].EndNumber = 2
+	doc.Chapters[0].Sections[0].Verses = verses[:1]
+
+	result, err := FormatOSIS([]*usfm.Document{doc})
+	if err != nil {
+		t.Fatalf("FormatOSIS failed: %v", err)
+	}
+
+	for _, exp := range []string{
+		`<verse osisID="Gen.1.1 Gen.1.2">In the beginning`,
+		`<note osisRef="Gen.1.1-Gen.1.2" n="+">`,
+	} {
+		if !strings.Contains(result, exp) {
+			t.Errorf("OSIS output should contain '%s'", exp)
+		}
+	}
+}
+
+// TestFormatOSISCrossReferences tests one osisRef per cross-reference and descriptive titles
+func TestFormatOSISCrossReferences(t *testing.T) {
+	doc := createTestDocument()
+	doc.Chapters[0].Sections[0].Reference = "(Matthew 5:3–12; Luke 6:20–23); A Psalm of David."
+
+	result, err := FormatOSIS([]*usfm.Document{doc})
+	if err != nil {
+		t.Fatalf("FormatOSIS failed: %v", err)
+	}
+
+	expected := `<title type="parallel"><reference osisRef="Matt.5.3-Matt.5.12">Matthew 5:3–12</reference>; ` +
+		`<reference osisRef="Luke.6.20-Luke.6.23">Luke 6:20–23</reference></title>` + "\n" +
+		`          <title type="psalm" canonical="true">A Psalm of David.</title>`
+	if !strings.Contains(result, expected) {
+		t.Errorf("OSIS output should contain %q, got:\n%s", expected, result)
+	}
+}
+
+// TestFormatOSISDescriptiveTitleFootnotes tests that footnotes of a \d title are written as notes
+func TestFormatOSISDescriptiveTitleFootnotes(t *testing.T) {
+	input := `\id PSA - Test Bible
+\c 6
+\s1 A Plea for Mercy
+\d For the choirmaster.\f + \fr 6:0 \ft Sheminith is probably a musical term.\f* A Psalm of David.
+\q1
+\v 1 O LORD, do not rebuke me in Your anger.`
+
+	doc, err := usfm.NewParser(usfm.DefaultParseOptions()).Parse(strings.NewReader(input), "test.sfm")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	result, err := FormatOSIS([]*usfm.Document{doc})
+	if err != nil {
+		t.Fatalf("FormatOSIS failed: %v", err)
+	}
+
+	expected := `<title type="psalm" canonical="true">For the choirmaster. A Psalm of David.` +
+		`<note osisRef="Ps.6" n="+"><reference type="annotateRef">6:0</reference> ` +
+		`Sheminith is probably a musical term.</note></title>`
+	if !strings.Contains(result, expected) {
+		t.Errorf("OSIS output should contain %q, got:\n%s", expected, result)
+	}
+	if strings.Contains(result, `\f`) {
+		t.Errorf("OSIS output should not contain footnote markup, got:\n%s", result)
+	}
+}
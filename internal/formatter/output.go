@@ -0,0 +1,51 @@
+package formatter
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/arenzana/usfmp/pkg/usfm"
+)
+
+// OutputFile is one file of a multi-file output, such as one HTML page per book.
+type OutputFile struct {
+	Name string // File name relative to the output directory
+	Data []byte // File contents
+}
+
+// bookFileNames returns a unique file name with the given extension for each document.
+// Canonical books are prefixed with their book number so that the files sort in
+// canonical order (e.g., "01-GEN.html"); other documents use their book code, or
+// their position when they have none.
+func bookFileNames(documents []*usfm.Document, extension string) []string {
+	names := make([]string, len(documents))
+	used := make(map[string]bool)
+
+	for i, doc := range documents {
+		code := doc.BookCode()
+		base := code
+		if book, ok := usfm.LookupBook(code); ok {
+			base = fmt.Sprintf("%02d-%s", book.Number, book.Code)
+		} else if code == "" {
+			base = fmt.Sprintf("book-%d", i+1)
+		}
+
+		name := base + "." + extension
+		for n := 2; used[name]; n++ {
+			name = fmt.Sprintf("%s-%d.%s", base, n, extension)
+		}
+		used[name] = true
+		names[i] = name
+	}
+
+	return names
+}
+
+// flushOutput writes the output built so far to w and empties the buffer, so that formats
+// that build a chapter at a time can write it as soon as it is complete
+func flushOutput(w io.Writer, result *strings.Builder) error {
+	_, err := io.WriteString(w, result.String())
+	result.Reset()
+	return err
+}
@@ -0,0 +1,162 @@
+package formatter
+
+import (
+	"encoding/csv"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	"github.com/arenzana/usfmp/pkg/parallel"
+	"github.com/arenzana/usfmp/pkg/ref"
+	"github.com/arenzana/usfmp/pkg/usfm"
+)
+
+// parallelStyle is added to htmlStyle for parallel text pages, which use the full page width
+const parallelStyle = `body { max-width: none; }
+.parallel { width: 100%; border-collapse: collapse; table-layout: fixed; }
+.parallel th, .parallel td { padding: 0.25em 0.75em; vertical-align: top; text-align: left; }
+.parallel thead th { position: sticky; top: 0; background: #fff; border-bottom: 2px solid #ccc; }
+.parallel .ref { width: 4em; }
+.parallel tr.chapter th { padding-top: 1em; font-size: 1.2em; border-bottom: 1px solid #ccc; }
+.parallel .vref { color: #777; font-size: 0.7em; margin-right: 0.25em; }
+`
+
+// parallelHeader returns the header row of a parallel table: "reference" and the names of the translations
+func parallelHeader(names []string) []string {
+	return append([]string{"reference"}, names...)
+}
+
+// parallelRecord returns the fields of a parallel table row: the reference and the text of each translation
+func parallelRecord(row parallel.Row) []string {
+	record := make([]string, 0, len(row.Verses)+1)
+	record = append(record, ref.Format([]ref.Range{{Start: row.Reference}}, ref.USFM))
+	for _, verse := range row.Verses {
+		record = append(record, verse.Text)
+	}
+	return record
+}
+
+// WriteParallelTSV writes aligned translations as TSV with one row per verse: the reference
+// ("GEN 1:1") followed by one column per translation, headed by its name. Text is cleaned of
+// tabs and newlines as in WriteTSV.
+func WriteParallelTSV(w io.Writer, names []string, rows []parallel.Row) error {
+	if _, err := io.WriteString(w, strings.Join(parallelHeader(names), "\t")+"\n"); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		record := parallelRecord(row)
+		for i, field := range record {
+			record[i] = cleanTSVField(field)
+		}
+		if _, err := io.WriteString(w, strings.Join(record, "\t")+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteParallelCSV writes aligned translations as RFC 4180 CSV with the columns of WriteParallelTSV.
+func WriteParallelCSV(w io.Writer, names []string, rows []parallel.Row) error {
+	writer := csv.NewWriter(w)
+	writer.UseCRLF = true
+
+	if err := writer.Write(parallelHeader(names)); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	for _, row := range rows {
+		if err := writer.Write(parallelRecord(row)); err != nil {
+			return fmt.Errorf("failed to write CSV: %w", err)
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
+
+// WriteParallelHTML writes aligned translations as a self-contained HTML5 page with a table
+// of one column per translation, headed by its name, and one row per verse, with a heading
+// row for every chapter.
+//
+// The table uses:
+//   - Anchored IDs for chapters ("GEN-1") and verses ("GEN-1-1")
+//   - The verse number of a translation before its text when the translation numbers the
+//     verse differently or joins it into a verse bridge, as <span class="vref">
+//
+// The title defaults to the names of the translations.
+func WriteParallelHTML(w io.Writer, names []string, rows []parallel.Row, options HTMLOptions) error {
+	title := options.Title
+	if title == "" {
+		title = strings.Join(names, " / ")
+	}
+
+	var result strings.Builder
+	writeHTMLHeader(&result, title, htmlStyle+parallelStyle, options)
+	result.WriteString("<table class=\"parallel\">\n<thead>\n<tr><th class=\"ref\"></th>")
+	for _, name := range names {
+		fmt.Fprintf(&result, "<th>%s</th>", html.EscapeString(name))
+	}
+	result.WriteString("</tr>\n</thead>\n")
+
+	var chapter ref.Reference
+	for i, row := range rows {
+		if current := (ref.Reference{Book: row.Reference.Book, Chapter: row.Reference.Chapter}); current != chapter {
+			if i > 0 {
+				result.WriteString("</tbody>\n")
+			}
+			chapter = current
+			fmt.Fprintf(&result, "<tbody id=\"%s-%d\">\n<tr class=\"chapter\"><th colspan=\"%d\">%s</th></tr>\n",
+				chapter.Book, chapter.Chapter, len(names)+1, html.EscapeString(chapterTitle(chapter)))
+		}
+
+		fmt.Fprintf(&result, "<tr id=\"%s-%d-%d\"><td class=\"ref\">%d</td>",
+			row.Reference.Book, row.Reference.Chapter, row.Reference.Verse, row.Reference.Verse)
+		for _, verse := range row.Verses {
+			result.WriteString("<td>")
+			if verse.Text != "" && (!verse.Reference.End.IsZero() || verse.Reference.Start != row.Reference) {
+				fmt.Fprintf(&result, "<span class=\"vref\">%s</span>", verseNumbers(verse.Reference))
+			}
+			result.WriteString(html.EscapeString(verse.Text))
+			result.WriteString("</td>")
+		}
+		result.WriteString("</tr>\n")
+
+		if _, err := io.WriteString(w, result.String()); err != nil {
+			return err
+		}
+		result.Reset()
+	}
+	if len(rows) > 0 {
+		result.WriteString("</tbody>\n")
+	}
+	result.WriteString("</table>\n")
+
+	writeHTMLFooter(&result)
+	_, err := io.WriteString(w, result.String())
+	return err
+}
+
+// chapterTitle returns the heading of a chapter in a parallel table (e.g., "Genesis 1")
+func chapterTitle(chapter ref.Reference) string {
+	name := chapter.Book
+	if book, ok := usfm.LookupBook(chapter.Book); ok {
+		name = book.Name
+	}
+	return fmt.Sprintf("%s %d", name, chapter.Chapter)
+}
+
+// verseNumbers writes the chapter and verse of a verse or verse bridge without the book
+// (e.g., "50:3" or "1:1-2")
+func verseNumbers(r ref.Range) string {
+	result := fmt.Sprintf("%d:%d", r.Start.Chapter, r.Start.Verse)
+	switch {
+	case r.End.IsZero():
+	case r.End.Chapter != r.Start.Chapter:
+		result += fmt.Sprintf("-%d:%d", r.End.Chapter, r.End.Verse)
+	default:
+		result += fmt.Sprintf("-%d", r.End.Verse)
+	}
+	return result
+}
@@ -0,0 +1,86 @@
+package formatter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/arenzana/usfmp/pkg/parallel"
+	"github.com/arenzana/usfmp/pkg/ref"
+)
+
+// testParallelRows returns two rows of two translations, the second numbering Psalm 51 like the Hebrew text
+func testParallelRows() []parallel.Row {
+	verse := func(chapter, number int) ref.Range {
+		return ref.Range{Start: ref.Reference{Book: "PSA", Chapter: chapter, Verse: number}}
+	}
+	return []parallel.Row{
+		{Reference: verse(51, 1).Start, Verses: []parallel.Verse{
+			{Translation: "BSB", Reference: verse(51, 1), Text: "Have mercy on me, O God,\taccording to Your loving devotion;"},
+			{Translation: "HEB", Reference: verse(51, 3), Text: `Be gracious to me, O God, "according" to your <kindness>`},
+		}},
+		{Reference: verse(51, 2).Start, Verses: []parallel.Verse{
+			{Translation: "BSB", Reference: verse(51, 2), Text: "Wash me clean of my iniquity."},
+			{Translation: "HEB"},
+		}},
+	}
+}
+
+// TestWriteParallelTSV tests the parallel TSV and CSV tables
+func TestWriteParallelTSV(t *testing.T) {
+	names := []string{"BSB", "HEB"}
+
+	var result strings.Builder
+	if err := WriteParallelTSV(&result, names, testParallelRows()); err != nil {
+		t.Fatalf("WriteParallelTSV failed: %v", err)
+	}
+	expected := "reference\tBSB\tHEB\n" +
+		"PSA 51:1\tHave mercy on me, O God, according to Your loving devotion;\tBe gracious to me, O God, \"according\" to your <kindness>\n" +
+		"PSA 51:2\tWash me clean of my iniquity.\t\n"
+	if result.String() != expected {
+		t.Errorf("Expected:\n%q\ngot:\n%q", expected, result.String())
+	}
+
+	result.Reset()
+	if err := WriteParallelCSV(&result, names, testParallelRows()); err != nil {
+		t.Fatalf("WriteParallelCSV failed: %v", err)
+	}
+	expected = "reference,BSB,HEB\r\n" +
+		"PSA 51:1,\"Have mercy on me, O God,\taccording to Your loving devotion;\",\"Be gracious to me, O God, \"\"according\"\" to your <kindness>\"\r\n" +
+		"PSA 51:2,Wash me clean of my iniquity.,\r\n"
+	if result.String() != expected {
+		t.Errorf("Expected:\n%q\ngot:\n%q", expected, result.String())
+	}
+}
+
+// TestWriteParallelHTML tests the side-by-side HTML table
+func TestWriteParallelHTML(t *testing.T) {
+	var result strings.Builder
+	if err := WriteParallelHTML(&result, []string{"BSB", "HEB"}, testParallelRows(), HTMLOptions{Language: "en"}); err != nil {
+		t.Fatalf("WriteParallelHTML failed: %v", err)
+	}
+	html := result.String()
+
+	expected := []string{
+		`<html lang="en">`,
+		`<title>BSB / HEB</title>`,
+		`.parallel { width: 100%;`,
+		`<tr><th class="ref"></th><th>BSB</th><th>HEB</th></tr>`,
+		`<tbody id="PSA-51">` + "\n" + `<tr class="chapter"><th colspan="3">Psalms 51</th></tr>`,
+		`<tr id="PSA-51-1"><td class="ref">1</td><td>Have mercy on me, O God,` + "\t" + `according to Your loving devotion;</td>` +
+			`<td><span class="vref">51:3</span>Be gracious to me, O God, &#34;according&#34; to your &lt;kindness&gt;</td></tr>`,
+		`<tr id="PSA-51-2"><td class="ref">2</td><td>Wash me clean of my iniquity.</td><td></td></tr>` + "\n</tbody>\n</table>",
+	}
+	for _, e := range expected {
+		if !strings.Contains(html, e) {
+			t.Errorf("Expected HTML to contain %q, got:\n%s", e, html)
+		}
+	}
+
+	result.Reset()
+	if err := WriteParallelHTML(&result, []string{"BSB"}, nil, HTMLOptions{Title: "Empty", ClassOnly: true}); err != nil {
+		t.Fatalf("WriteParallelHTML failed: %v", err)
+	}
+	if strings.Contains(result.String(), "<style>") || !strings.Contains(result.String(), "<title>Empty</title>") || strings.Contains(result.String(), "</tbody>") {
+		t.Errorf("Unexpected HTML for no rows:\n%s", result.String())
+	}
+}
@@ -0,0 +1,634 @@
+package formatter
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/arenzana/usfmp/internal/pdf"
+	"github.com/arenzana/usfmp/pkg/usfm"
+)
+
+// PDFOptions configures the PDF output.
+type PDFOptions struct {
+	PageWidth    float64    // Page width in points (1/72 inch)
+	PageHeight   float64    // Page height in points
+	Margins      PDFMargins // Page margins in points
+	FontSize     float64    // Body text size in points
+	FontFile     string     // TrueType font for body text; required for scripts outside Latin-1
+	BoldFontFile string     // TrueType font for headings; defaults to FontFile when empty
+	Title        string     // Document title; defaults to the main title of the first document
+}
+
+// PDFMargins holds the page margins in points.
+type PDFMargins struct {
+	Top    float64
+	Right  float64
+	Bottom float64
+	Left   float64
+}
+
+// pageSizes holds the named page sizes accepted by ParsePageSize, in points
+var pageSizes = map[string][2]float64{
+	"a4":     {595.28, 841.89},
+	"a5":     {419.53, 595.28},
+	"letter": {612, 792},
+	"legal":  {612, 1008},
+}
+
+// DefaultPDFOptions returns A4 pages with 54pt (0.75 inch) margins,
+// 11pt body text, and the built-in Helvetica fonts.
+func DefaultPDFOptions() PDFOptions {
+	size := pageSizes["a4"]
+	return PDFOptions{
+		PageWidth:  size[0],
+		PageHeight: size[1],
+		Margins:    PDFMargins{Top: 54, Right: 54, Bottom: 54, Left: 54},
+		FontSize:   11,
+	}
+}
+
+// ParsePageSize parses a page size name (a4, a5, letter, legal) or explicit
+// dimensions in the form WIDTHxHEIGHT (e.g., "6inx9in" or "148mmx210mm").
+// It returns the width and height in points.
+func ParsePageSize(value string) (float64, float64, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if size, ok := pageSizes[value]; ok {
+		return size[0], size[1], nil
+	}
+
+	parts := strings.Split(value, "x")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid page size %q (use a4, a5, letter, legal, or WIDTHxHEIGHT)", value)
+	}
+	width, err := ParseLength(parts[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid page width: %w", err)
+	}
+	height, err := ParseLength(parts[1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid page height: %w", err)
+	}
+	return width, height, nil
+}
+
+// ParseLength parses a positive length with an optional unit (pt, mm, cm, in)
+// and returns it in points. A bare number is taken as points.
+//
+// Example:
+//
+//	formatter.ParseLength("20mm") // 56.69
+//	formatter.ParseLength("1in")  // 72
+func ParseLength(value string) (float64, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	scale := 1.0
+	for unit, factor := range map[string]float64{"pt": 1, "mm": 72 / 25.4, "cm": 72 / 2.54, "in": 72} {
+		if strings.HasSuffix(value, unit) {
+			value = strings.TrimSuffix(value, unit)
+			scale = factor
+			break
+		}
+	}
+
+	number, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || number < 0 {
+		return 0, fmt.Errorf("invalid length %q", value)
+	}
+	return number * scale, nil
+}
+
+// ParseMargins parses page margins given as one length (all sides),
+// two lengths (vertical, horizontal), or four lengths (top, right, bottom, left),
+// separated by commas, following the CSS shorthand order.
+func ParseMargins(value string) (PDFMargins, error) {
+	var lengths []float64
+	for _, part := range strings.Split(value, ",") {
+		length, err := ParseLength(part)
+		if err != nil {
+			return PDFMargins{}, err
+		}
+		lengths = append(lengths, length)
+	}
+
+	switch len(lengths) {
+	case 1:
+		return PDFMargins{lengths[0], lengths[0], lengths[0], lengths[0]}, nil
+	case 2:
+		return PDFMargins{lengths[0], lengths[1], lengths[0], lengths[1]}, nil
+	case 4:
+		return PDFMargins{lengths[0], lengths[1], lengths[2], lengths[3]}, nil
+	default:
+		return PDFMargins{}, fmt.Errorf("invalid margins %q (use 1, 2, or 4 comma-separated lengths)", value)
+	}
+}
+
+// FormatPDF formats USFM documents as a PDF document.
+// The PDF is generated in pure Go without external tools.
+//
+// The layout uses:
+//   - Each book starting on a new page with its main title centered
+//   - "Chapter N" headings and bold section headings with cross-references in italics
+//   - Superscript verse numbers within running paragraphs
+//   - Poetry lines (\q1, \q2) and indented paragraphs (\pi) on their own indented lines
+//   - Footnotes numbered per book and printed at the bottom of the page where they occur
+//   - Page numbers centered in the bottom margin
+//
+// Text is set in Helvetica by default, which only covers Latin-1 characters.
+// Set FontFile (and optionally BoldFontFile) to a TrueType font to embed it,
+// which is required for Greek, Cyrillic, Hebrew, and other scripts.
+func FormatPDF(documents []*usfm.Document, options PDFOptions) ([]byte, error) {
+	var result bytes.Buffer
+	if err := WritePDF(&result, documents, options); err != nil {
+		return nil, err
+	}
+	return result.Bytes(), nil
+}
+
+// WritePDF writes USFM documents as a PDF document to w, as described for FormatPDF.
+// Pages are laid out in memory first, since the PDF ends with an index of its objects.
+func WritePDF(w io.Writer, documents []*usfm.Document, options PDFOptions) error {
+	if options.FontSize <= 0 {
+		return fmt.Errorf("invalid font size: %g", options.FontSize)
+	}
+	textWidth := options.PageWidth - options.Margins.Left - options.Margins.Right
+	textHeight := options.PageHeight - options.Margins.Top - options.Margins.Bottom
+	if textWidth < options.FontSize*10 || textHeight < options.FontSize*10 {
+		return fmt.Errorf("page size too small for the given margins")
+	}
+
+	fonts, err := loadPDFFonts(options)
+	if err != nil {
+		return err
+	}
+
+	doc := pdf.New(options.PageWidth, options.PageHeight)
+	title := options.Title
+	if title == "" && len(documents) > 0 {
+		title = documents[0].MainTitle
+	}
+	doc.SetTitle(title)
+
+	layout := &pdfLayout{
+		doc:     doc,
+		options: options,
+		fonts:   fonts,
+		width:   textWidth,
+	}
+
+	for _, document := range documents {
+		layout.addDocument(document)
+	}
+	if layout.page == nil {
+		// An empty input still produces a valid single-page PDF
+		layout.newPage()
+	}
+	layout.finishPage()
+
+	if err := doc.Write(w); err != nil {
+		return fmt.Errorf("failed to write PDF: %w", err)
+	}
+	return nil
+}
+
+// pdfFonts holds the fonts used for the different kinds of text
+type pdfFonts struct {
+	regular pdf.Font
+	bold    pdf.Font
+	italic  pdf.Font
+}
+
+// loadPDFFonts returns the built-in Helvetica fonts or the configured TrueType fonts
+func loadPDFFonts(options PDFOptions) (pdfFonts, error) {
+	if options.FontFile == "" {
+		return pdfFonts{regular: pdf.Helvetica, bold: pdf.HelveticaBold, italic: pdf.HelveticaOblique}, nil
+	}
+
+	regular, err := pdf.LoadTrueTypeFile(options.FontFile)
+	if err != nil {
+		return pdfFonts{}, fmt.Errorf("failed to load font %s: %w", options.FontFile, err)
+	}
+
+	bold := regular
+	if options.BoldFontFile != "" {
+		bold, err = pdf.LoadTrueTypeFile(options.BoldFontFile)
+		if err != nil {
+			return pdfFonts{}, fmt.Errorf("failed to load font %s: %w", options.BoldFontFile, err)
+		}
+	}
+
+	// Embedded fonts have no separate italic face; references are set in the regular face
+	return pdfFonts{regular: regular, bold: bold, italic: regular}, nil
+}
+
+// pdfRun is a piece of text set in one font, size, and baseline offset
+type pdfRun struct {
+	font pdf.Font
+	size float64
+	rise float64 // Baseline offset for superscripts
+	text string
+}
+
+// pdfWord is an unbreakable group of runs, such as a verse number with the first word
+type pdfWord struct {
+	runs  []pdfRun
+	notes []*pdfNote // Footnotes whose caller is part of this word
+}
+
+// width returns the total width of the word
+func (w pdfWord) width() float64 {
+	total := 0.0
+	for _, run := range w.runs {
+		total += run.font.Width(run.text, run.size)
+	}
+	return total
+}
+
+// space returns the width of the space that precedes the word, set in the font of its first run
+func (w pdfWord) space() float64 {
+	if len(w.runs) == 0 {
+		return 0
+	}
+	return w.runs[0].font.Width(" ", w.runs[0].size)
+}
+
+// pdfNote is a footnote already broken into lines at footnote size
+type pdfNote struct {
+	lines []pdfLine
+}
+
+// height returns the vertical space taken by the footnote
+func (n *pdfNote) height() float64 {
+	total := 0.0
+	for _, line := range n.lines {
+		total += line.height
+	}
+	return total
+}
+
+// pdfLine is a laid-out line of words ready to be placed on a page
+type pdfLine struct {
+	words        []pdfWord
+	indent       float64 // Offset from the left margin
+	height       float64 // Line height (leading)
+	spaceBefore  float64 // Extra space above the line, dropped at the top of a page
+	centered     bool    // Whether the line is centered within the text width
+	keepWithNext bool    // Whether the line must stay on the same page as the next line
+	notes        []*pdfNote
+}
+
+// pdfLayout places lines on pages and collects footnotes at the bottom of each page
+type pdfLayout struct {
+	doc     *pdf.Document
+	options PDFOptions
+	fonts   pdfFonts
+	width   float64 // Text width between the margins
+
+	page        *pdf.Page
+	y           float64    // Top of the free area on the current page
+	pageNotes   []*pdfNote // Footnotes to print at the bottom of the current page
+	notesHeight float64    // Height of the footnotes collected for the current page
+	noteNumber  int        // Running footnote number within the current book
+	held        []pdfLine  // Lines waiting for the line they must be kept with
+}
+
+// addDocument lays out one book, starting on a new page
+func (l *pdfLayout) addDocument(doc *usfm.Document) {
+	size := l.options.FontSize
+	l.flushHeld()
+	l.newPage()
+	l.noteNumber = 0
+
+	if title := bookTitle(doc); title != "" {
+		for i, line := range l.wrap(l.textWords(title, l.fonts.bold, size*2), 0, 0, size*2.6) {
+			line.centered = true
+			if i == 0 {
+				line.spaceBefore = size
+			}
+			l.add(line)
+		}
+	}
+
+	for _, chapter := range doc.Chapters {
+		heading := l.wrap(l.textWords(fmt.Sprintf("Chapter %d", chapter.Number), l.fonts.bold, size*1.5), 0, 0, size*2)
+		for i := range heading {
+			heading[i].keepWithNext = true
+			if i == 0 {
+				heading[i].spaceBefore = size * 1.5
+			}
+			l.add(heading[i])
+		}
+
+		for _, section := range chapter.Sections {
+			l.addSection(section)
+		}
+	}
+	l.flushHeld()
+}
+
+// addSection lays out a section heading, its cross-reference, and its verses
+func (l *pdfLayout) addSection(section usfm.Section) {
+	size := l.options.FontSize
+
+	if section.Title != "" {
+		headingSize := size * 1.15
+		if section.Level > 1 {
+			headingSize = size
+		}
+		for i, line := range l.wrap(l.textWords(section.Title, l.fonts.bold, headingSize), 0, 0, headingSize*1.4) {
+			line.keepWithNext = true
+			if i == 0 {
+				line.spaceBefore = size * 0.8
+			}
+			l.add(line)
+		}
+	}
+	if section.Reference != "" {
+		for _, line := range l.wrap(l.textWords(section.Reference, l.fonts.italic, size*0.9), 0, 0, size*1.3) {
+			line.keepWithNext = true
+			l.add(line)
+		}
+	}
+
+	// Verses flow into paragraphs; a line break starts a new paragraph or poetry line
+	var words []pdfWord
+	marker := ""
+	flush := func() {
+		if len(words) == 0 {
+			return
+		}
+		first, rest, spaceBefore := l.paragraphIndent(marker)
+		for i, line := range l.wrap(words, first, rest, size*1.35) {
+			if i == 0 {
+				line.spaceBefore = spaceBefore
+			}
+			l.add(line)
+		}
+		words = nil
+	}
+
+	for _, verse := range section.Verses {
+		lines := verse.Lines
+		if len(lines) == 0 {
+			lines = []usfm.Line{{Text: verse.Text}}
+		}
+
+		for i, line := range lines {
+			if line.Break {
+				flush()
+				marker = line.Marker
+			}
+
+			lineWords := l.textWords(usfm.PlainText(line.Text), l.fonts.regular, size)
+			if i == 0 {
+				// The verse number is attached to the first word so they are never separated
+				number := pdfRun{font: l.fonts.regular, size: size * 0.6, rise: size * 0.35, text: fmt.Sprintf("%d ", verse.Number)}
+				if len(lineWords) == 0 {
+					lineWords = []pdfWord{{}}
+				}
+				lineWords[0].runs = append([]pdfRun{number}, lineWords[0].runs...)
+			}
+			if i == len(lines)-1 && len(verse.Footnotes) > 0 {
+				if len(lineWords) == 0 {
+					lineWords = []pdfWord{{}}
+				}
+				last := &lineWords[len(lineWords)-1]
+				for j, footnote := range verse.Footnotes {
+					l.noteNumber++
+					caller := strconv.Itoa(l.noteNumber)
+					text := caller
+					if j > 0 {
+						// Separate adjacent callers so that 4 and 5 do not read as 45
+						text = "," + caller
+					}
+					last.runs = append(last.runs, pdfRun{font: l.fonts.regular, size: size * 0.6, rise: size * 0.35, text: text})
+					last.notes = append(last.notes, l.footnote(caller, footnote))
+				}
+			}
+			words = append(words, lineWords...)
+		}
+	}
+	flush()
+}
+
+// paragraphIndent returns the first-line indent, continuation indent, and space
+// before a paragraph for a paragraph or poetry marker
+func (l *pdfLayout) paragraphIndent(marker string) (float64, float64, float64) {
+	unit := l.options.FontSize * 1.5
+	line := usfm.Line{Marker: marker}
+	level := float64(line.Indent())
+
+	switch {
+	case line.IsPoetry():
+		// Poetry lines are indented by level; wrapped text hangs further in
+		return level * unit, (level + 2) * unit, 0
+	case strings.HasPrefix(marker, "pi"):
+		return (level + 1) * unit, level * unit, l.options.FontSize * 0.3
+	case marker == "p":
+		return unit, 0, l.options.FontSize * 0.3
+	default:
+		return 0, 0, l.options.FontSize * 0.3
+	}
+}
+
+// footnote builds a footnote with its caller, reference, and text
+func (l *pdfLayout) footnote(caller string, footnote usfm.Footnote) *pdfNote {
+	size := l.options.FontSize * 0.8
+	words := []pdfWord{{runs: []pdfRun{{font: l.fonts.regular, size: size * 0.7, rise: size * 0.35, text: caller}}}}
+	if footnote.Reference != "" {
+		words = append(words, l.textWords(footnote.Reference, l.fonts.bold, size)...)
+	}
+	words = append(words, l.textWords(usfm.PlainText(footnote.Text), l.fonts.regular, size)...)
+	return &pdfNote{lines: l.wrap(words, 0, size, size*1.25)}
+}
+
+// textWords splits text into words set in a single font and size
+func (l *pdfLayout) textWords(text string, font pdf.Font, size float64) []pdfWord {
+	var words []pdfWord
+	for _, word := range strings.Fields(text) {
+		words = append(words, pdfWord{runs: []pdfRun{{font: font, size: size, text: word}}})
+	}
+	return words
+}
+
+// wrap breaks words into lines that fit the text width, with separate indents
+// for the first line and the following lines
+func (l *pdfLayout) wrap(words []pdfWord, firstIndent, restIndent, height float64) []pdfLine {
+	var lines []pdfLine
+
+	current := pdfLine{indent: firstIndent, height: height}
+	used := 0.0
+	for _, word := range words {
+		width := word.width()
+		space := word.space()
+		if len(current.words) > 0 && current.indent+used+space+width > l.width {
+			lines = append(lines, current)
+			current = pdfLine{indent: restIndent, height: height}
+			used = 0
+		}
+		if len(current.words) > 0 {
+			used += space
+		}
+		used += width
+		current.words = append(current.words, word)
+		current.notes = append(current.notes, word.notes...)
+	}
+	if len(current.words) > 0 {
+		lines = append(lines, current)
+	}
+	return lines
+}
+
+// add places a line, holding lines that must be kept with the line that follows them
+func (l *pdfLayout) add(line pdfLine) {
+	l.held = append(l.held, line)
+	if line.keepWithNext {
+		return
+	}
+	l.flushHeld()
+}
+
+// flushHeld places all held lines, moving them to a new page together if they do not fit
+func (l *pdfLayout) flushHeld() {
+	if len(l.held) == 0 {
+		return
+	}
+	lines := l.held
+	l.held = nil
+
+	if l.page == nil {
+		l.newPage()
+	}
+	if !l.fits(lines) {
+		l.newPage()
+	}
+	for _, line := range lines {
+		if !l.fits([]pdfLine{line}) {
+			l.newPage()
+		}
+		l.place(line)
+	}
+}
+
+// fits reports whether lines and their footnotes fit in the remaining space on the page.
+// An empty page always fits, so that oversized content cannot cause endless page breaks.
+func (l *pdfLayout) fits(lines []pdfLine) bool {
+	top := l.options.PageHeight - l.options.Margins.Top
+	if l.y >= top {
+		return true
+	}
+
+	needed := 0.0
+	notesHeight := l.notesHeight
+	for _, line := range lines {
+		needed += line.spaceBefore + line.height
+		for _, note := range line.notes {
+			notesHeight += note.height()
+		}
+	}
+	if notesHeight > 0 {
+		needed += l.options.FontSize
+	}
+	return l.y-needed-notesHeight >= l.options.Margins.Bottom
+}
+
+// place draws a line at the current position and registers its footnotes
+func (l *pdfLayout) place(line pdfLine) {
+	top := l.options.PageHeight - l.options.Margins.Top
+	if l.y < top {
+		l.y -= line.spaceBefore
+	}
+	baseline := l.y - line.height*0.75
+	l.drawLine(line, l.options.Margins.Left, baseline)
+	l.y -= line.height
+
+	for _, note := range line.notes {
+		l.pageNotes = append(l.pageNotes, note)
+		l.notesHeight += note.height()
+	}
+}
+
+// drawLine draws the words of a line starting at the left margin x and the given baseline.
+// Consecutive runs with the same font, size, and rise are drawn as one piece of text
+// including the spaces between words, which keeps the output compact and lets
+// PDF viewers extract the text with its word spacing.
+func (l *pdfLayout) drawLine(line pdfLine, left, baseline float64) {
+	x := left + line.indent
+	if line.centered {
+		width := 0.0
+		for i, word := range line.words {
+			if i > 0 {
+				width += word.space()
+			}
+			width += word.width()
+		}
+		x = left + (l.width-width)/2
+	}
+
+	var pending pdfRun
+	pendingX := x
+	flush := func() {
+		l.page.Text(pendingX, baseline+pending.rise, pending.font, pending.size, pending.text)
+		pending = pdfRun{}
+	}
+
+	for i, word := range line.words {
+		for j, run := range word.runs {
+			sameStyle := pending.font == run.font && pending.size == run.size && pending.rise == run.rise
+			if i > 0 && j == 0 {
+				if sameStyle {
+					pending.text += " "
+				}
+				x += word.space()
+			}
+			if !sameStyle {
+				if pending.text != "" {
+					flush()
+				}
+				pending = run
+				pendingX = x
+			} else {
+				pending.text += run.text
+			}
+			x += run.font.Width(run.text, run.size)
+		}
+	}
+	if pending.text != "" {
+		flush()
+	}
+}
+
+// newPage finishes the current page and starts a new one
+func (l *pdfLayout) newPage() {
+	l.finishPage()
+	l.page = l.doc.AddPage()
+	l.y = l.options.PageHeight - l.options.Margins.Top
+	l.pageNotes = nil
+	l.notesHeight = 0
+}
+
+// finishPage draws the footnotes and page number of the current page
+func (l *pdfLayout) finishPage() {
+	if l.page == nil {
+		return
+	}
+	margins := l.options.Margins
+
+	if len(l.pageNotes) > 0 {
+		y := margins.Bottom + l.notesHeight
+		separator := y + l.options.FontSize*0.5
+		l.page.Line(margins.Left, separator, margins.Left+l.width/3, separator, 0.5)
+		for _, note := range l.pageNotes {
+			for _, line := range note.lines {
+				l.drawLine(line, margins.Left, y-line.height*0.75)
+				y -= line.height
+			}
+		}
+	}
+
+	number := strconv.Itoa(l.doc.PageCount())
+	size := l.options.FontSize * 0.8
+	width := l.fonts.regular.Width(number, size)
+	l.page.Text(margins.Left+(l.width-width)/2, margins.Bottom/2, l.fonts.regular, size, number)
+}
@@ -0,0 +1,135 @@
+package formatter
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/arenzana/usfmp/pkg/usfm"
+)
+
+// pdfText decompresses all content streams of a PDF and returns their concatenated text
+func pdfText(t *testing.T, data []byte) string {
+	var result strings.Builder
+	streamRegex := regexp.MustCompile(`<< /Length (\d+) /Filter /FlateDecode >>\nstream\n`)
+	for _, match := range streamRegex.FindAllSubmatchIndex(data, -1) {
+		length, _ := strconv.Atoi(string(data[match[2]:match[3]]))
+		reader, err := zlib.NewReader(bytes.NewReader(data[match[1] : match[1]+length]))
+		if err != nil {
+			t.Fatalf("Invalid content stream: %v", err)
+		}
+		content, err := io.ReadAll(reader)
+		if err != nil {
+			t.Fatalf("Invalid content stream: %v", err)
+		}
+		result.Write(content)
+	}
+	return result.String()
+}
+
+// TestFormatPDF tests PDF formatting with the built-in fonts
+func TestFormatPDF(t *testing.T) {
+	doc := createTestDocument()
+	doc.Chapters[0].Sections[0].Verses[1].Lines = []usfm.Line{
+		{Marker: "q1", Text: "Now the earth", Break: true},
+		{Marker: "q2", Text: "was formless and void.", Break: true},
+	}
+
+	result, err := FormatPDF([]*usfm.Document{doc}, DefaultPDFOptions())
+	if err != nil {
+		t.Fatalf("FormatPDF failed: %v", err)
+	}
+
+	if !bytes.HasPrefix(result, []byte("%PDF-")) {
+		t.Fatal("Output should be a PDF document")
+	}
+
+	content := pdfText(t, result)
+	expected := []string{
+		"(Genesis) Tj",
+		"(Chapter 1) Tj",
+		"(The Creation) Tj",
+		"(\\(John 1:1\\2265\\)) Tj",
+		"(1 ) Tj",
+		"(In the beginning God created the heavens and the earth.) Tj",
+		"(Now the earth) Tj",
+		"(was formless and void.) Tj",
+		"(Hebrew: Elohim) Tj",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(content, exp) {
+			t.Errorf("PDF content should contain '%s'", exp)
+		}
+	}
+
+	// The second poetry line is indented further than the first
+	positions := regexp.MustCompile(`([\d.]+) [\d.]+ Td \((Now the earth|was formless and void\.)\)`).FindAllStringSubmatch(content, -1)
+	if len(positions) != 2 {
+		t.Fatalf("Expected both poetry lines in content, got %v", positions)
+	}
+	first, _ := strconv.ParseFloat(positions[0][1], 64)
+	second, _ := strconv.ParseFloat(positions[1][1], 64)
+	if second <= first {
+		t.Errorf("Expected q2 line (x=%g) to be indented more than q1 line (x=%g)", second, first)
+	}
+}
+
+// TestFormatPDFPageBreaks tests that long books flow onto multiple pages
+func TestFormatPDFPageBreaks(t *testing.T) {
+	doc := createTestDocument()
+	verses := make([]usfm.Verse, 0, 200)
+	for i := 1; i <= 200; i++ {
+		verses = append(verses, usfm.Verse{Number: i, Text: strings.Repeat("word ", 30)})
+	}
+	doc.Chapters[0].Sections[0].Verses = verses
+
+	options := DefaultPDFOptions()
+	options.PageWidth, options.PageHeight, _ = ParsePageSize("a5")
+
+	result, err := FormatPDF([]*usfm.Document{doc}, options)
+	if err != nil {
+		t.Fatalf("FormatPDF failed: %v", err)
+	}
+
+	count := regexp.MustCompile(`/Type /Pages /Kids \[[^\]]*\] /Count (\d+)`).FindSubmatch(result)
+	if count == nil {
+		t.Fatal("PDF should contain a page tree")
+	}
+	if pages, _ := strconv.Atoi(string(count[1])); pages < 5 {
+		t.Errorf("Expected long book to span several pages, got %d", pages)
+	}
+}
+
+// TestParsePDFLengths tests parsing of page sizes, lengths, and margins
+func TestParsePDFLengths(t *testing.T) {
+	width, height, err := ParsePageSize("Letter")
+	if err != nil || width != 612 || height != 792 {
+		t.Errorf("Expected letter to be 612x792, got %gx%g (%v)", width, height, err)
+	}
+
+	width, height, err = ParsePageSize("6inx9in")
+	if err != nil || width != 432 || height != 648 {
+		t.Errorf("Expected 6inx9in to be 432x648, got %gx%g (%v)", width, height, err)
+	}
+
+	if _, _, err := ParsePageSize("huge"); err == nil {
+		t.Error("Expected error for unknown page size")
+	}
+
+	margins, err := ParseMargins("1in,0.5in")
+	if err != nil || margins != (PDFMargins{Top: 72, Right: 36, Bottom: 72, Left: 36}) {
+		t.Errorf("Unexpected margins %+v (%v)", margins, err)
+	}
+
+	if length, err := ParseLength("25.4mm"); err != nil || length < 71.99 || length > 72.01 {
+		t.Errorf("Expected 25.4mm to be 72pt, got %g (%v)", length, err)
+	}
+
+	if _, err := ParseMargins("1in,2in,3in"); err == nil {
+		t.Error("Expected error for three margin values")
+	}
+}
@@ -0,0 +1,312 @@
+package formatter
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/arenzana/usfmp/pkg/ref"
+	"github.com/arenzana/usfmp/pkg/usfm"
+)
+
+// relationalColumn is a column of a relational table with its SQL definition
+type relationalColumn struct {
+	name       string // Column name
+	definition string // SQL type and constraints
+}
+
+// relationalTable is a normalized table of the relational export.
+// Row values are strings, ints, or nil for SQL NULL.
+type relationalTable struct {
+	name    string
+	columns []relationalColumn
+	rows    [][]any
+}
+
+// relationalIDs hands out unique text IDs, adding a suffix ("-2", "-3", ...) to repeated IDs
+// so that books or verses that occur twice in the input still get their own key
+type relationalIDs map[string]bool
+
+// unique returns id, or id with a suffix if it was already handed out
+func (ids relationalIDs) unique(id string) string {
+	result := id
+	for n := 2; ids[result]; n++ {
+		result = fmt.Sprintf("%s-%d", id, n)
+	}
+	ids[result] = true
+	return result
+}
+
+// relationalTables builds the normalized tables for the relational export.
+//
+// The IDs are text keys derived from the position in the text, so they stay the same
+// when the same books are exported again: "GEN" for a book, "GEN.1" for a chapter,
+// "GEN.1.s1" for the first section of a chapter, "GEN.1.1" for a verse,
+// "GEN.1.1.f1" for the first footnote of a verse, and "GEN.1.s1.r1" for the first cross-reference
+// of a section.
+func relationalTables(documents []*usfm.Document) []*relationalTable {
+	books := &relationalTable{name: "books", columns: []relationalColumn{
+		{"id", "TEXT PRIMARY KEY"},
+		{"position", "INTEGER NOT NULL"},
+		{"code", "TEXT"},
+		{"identification", "TEXT"},
+		{"title", "TEXT"},
+		{"source_file", "TEXT"},
+	}}
+	chapters := &relationalTable{name: "chapters", columns: []relationalColumn{
+		{"id", "TEXT PRIMARY KEY"},
+		{"book_id", "TEXT NOT NULL REFERENCES books(id)"},
+		{"number", "INTEGER NOT NULL"},
+	}}
+	sections := &relationalTable{name: "sections", columns: []relationalColumn{
+		{"id", "TEXT PRIMARY KEY"},
+		{"chapter_id", "TEXT NOT NULL REFERENCES chapters(id)"},
+		{"position", "INTEGER NOT NULL"},
+		{"level", "INTEGER"},
+		{"title", "TEXT"},
+	}}
+	verses := &relationalTable{name: "verses", columns: []relationalColumn{
+		{"id", "TEXT PRIMARY KEY"},
+		{"chapter_id", "TEXT NOT NULL REFERENCES chapters(id)"},
+		{"section_id", "TEXT NOT NULL REFERENCES sections(id)"},
+		{"number", "INTEGER NOT NULL"},
+		{"end_number", "INTEGER"},
+		{"text", "TEXT"},
+		{"plain_text", "TEXT"},
+	}}
+	footnotes := &relationalTable{name: "footnotes", columns: []relationalColumn{
+		{"id", "TEXT PRIMARY KEY"},
+		{"verse_id", "TEXT NOT NULL REFERENCES verses(id)"},
+		{"position", "INTEGER NOT NULL"},
+		{"caller", "TEXT"},
+		{"reference", "TEXT"},
+		{"text", "TEXT"},
+		{"plain_text", "TEXT"},
+	}}
+	crossReferences := &relationalTable{name: "cross_references", columns: []relationalColumn{
+		{"id", "TEXT PRIMARY KEY"},
+		{"section_id", "TEXT NOT NULL REFERENCES sections(id)"},
+		{"position", "INTEGER NOT NULL"},
+		{"book", "TEXT NOT NULL"},
+		{"chapter", "INTEGER"},
+		{"verse", "INTEGER"},
+		{"end_book", "TEXT NOT NULL"},
+		{"end_chapter", "INTEGER"},
+		{"end_verse", "INTEGER"},
+		{"reference", "TEXT NOT NULL"},
+	}}
+
+	ids := relationalIDs{}
+	for i, doc := range documents {
+		code := doc.BookCode()
+		base := code
+		if base == "" {
+			base = "UNKNOWN"
+		}
+		bookID := ids.unique(base)
+		books.rows = append(books.rows, []any{bookID, i + 1, nullString(code), nullString(doc.ID), bookTitle(doc), nullString(doc.SourceFile)})
+
+		for _, chapter := range doc.Chapters {
+			chapterID := ids.unique(fmt.Sprintf("%s.%d", bookID, chapter.Number))
+			chapters.rows = append(chapters.rows, []any{chapterID, bookID, chapter.Number})
+
+			for s, section := range chapter.Sections {
+				sectionID := ids.unique(fmt.Sprintf("%s.s%d", chapterID, s+1))
+				level := any(nil)
+				if section.Title != "" {
+					level = section.Level
+				}
+				sections.rows = append(sections.rows, []any{sectionID, chapterID, s + 1, level, nullString(section.Title)})
+
+				references, _ := sectionReferences(section, code, chapter.Number)
+				for r, reference := range references {
+					end := reference.Last()
+					crossReferences.rows = append(crossReferences.rows, []any{ids.unique(fmt.Sprintf("%s.r%d", sectionID, r+1)), sectionID, r + 1,
+						reference.Start.Book, nullInt(reference.Start.Chapter), nullInt(reference.Start.Verse),
+						end.Book, nullInt(end.Chapter), nullInt(end.Verse), ref.Format([]ref.Range{reference}, ref.Default)})
+				}
+
+				for _, verse := range section.Verses {
+					verseID := ids.unique(fmt.Sprintf("%s.%d", chapterID, verse.Number))
+					endNumber := any(nil)
+					if verse.EndNumber > 0 {
+						endNumber = verse.EndNumber
+					}
+					verses.rows = append(verses.rows, []any{verseID, chapterID, sectionID, verse.Number, endNumber, verse.Text, usfm.PlainText(verse.Text)})
+
+					for f, footnote := range verse.Footnotes {
+						footnoteID := ids.unique(fmt.Sprintf("%s.f%d", verseID, f+1))
+						footnotes.rows = append(footnotes.rows, []any{footnoteID, verseID, f + 1,
+							nullString(footnote.Caller), nullString(footnote.Reference), footnote.Text, usfm.PlainText(footnote.Text)})
+					}
+				}
+			}
+		}
+	}
+
+	// Tables are listed so that every table comes after the tables it references
+	return []*relationalTable{books, chapters, sections, verses, footnotes, crossReferences}
+}
+
+// sectionReferences returns the cross-references of a section, parsed relative to its chapter,
+// and the rest of its reference text. The parser appends a \d descriptive title to
+// Section.Reference after "; ", so the longest run of leading "; "-separated items that parses
+// is taken as the cross-references; a text that does not parse at all, such as a descriptive
+// title on its own, is all rest.
+func sectionReferences(section usfm.Section, book string, chapter int) ([]ref.Range, string) {
+	items := strings.Split(section.Reference, "; ")
+	for n := len(items); n > 0; n-- {
+		ranges, err := ref.ParseRelative(strings.Join(items[:n], "; "), ref.Reference{Book: book, Chapter: chapter})
+		if err == nil {
+			if len(section.ParsedReference) > 0 {
+				ranges = section.ParsedReference
+			}
+			return ranges, strings.Join(items[n:], "; ")
+		}
+	}
+	return section.ParsedReference, section.Reference
+}
+
+// nullInt returns nil (SQL NULL) for zero, such as the verse of a chapter reference
+func nullInt(value int) any {
+	if value == 0 {
+		return nil
+	}
+	return value
+}
+
+// nullString returns nil (SQL NULL) for an empty string
+func nullString(value string) any {
+	if value == "" {
+		return nil
+	}
+	return value
+}
+
+// FormatCSVTables formats USFM documents as normalized tables, one RFC 4180 CSV file per table:
+// books.csv, chapters.csv, sections.csv, verses.csv, footnotes.csv, and cross_references.csv.
+//
+// Each table has a text ID column and refers to its parent by ID (e.g., verses.chapter_id
+// and verses.section_id), so footnotes and cross-references can be joined to their verses
+// and sections without re-splitting packed cells. The cross_references table has one row per
+// reference of a \r line, with the book, chapter, and verse the reference starts and ends at.
+// IDs are derived from the position in the text ("GEN", "GEN.1", "GEN.1.s1", "GEN.1.1",
+// "GEN.1.1.f1", "GEN.1.s1.r1"), so they are stable across exports. Empty optional values are
+// written as empty fields.
+func FormatCSVTables(documents []*usfm.Document) ([]OutputFile, error) {
+	var files []OutputFile
+
+	for _, table := range relationalTables(documents) {
+		records := make([][]string, 0, len(table.rows)+1)
+
+		header := make([]string, len(table.columns))
+		for i, column := range table.columns {
+			header[i] = column.name
+		}
+		records = append(records, header)
+
+		for _, row := range table.rows {
+			record := make([]string, len(row))
+			for i, value := range row {
+				switch value := value.(type) {
+				case string:
+					record[i] = value
+				case int:
+					record[i] = strconv.Itoa(value)
+				}
+			}
+			records = append(records, record)
+		}
+
+		data, err := writeCSV(records)
+		if err != nil {
+			return nil, fmt.Errorf("failed to write %s table: %w", table.name, err)
+		}
+		files = append(files, OutputFile{Name: table.name + ".csv", Data: []byte(data)})
+	}
+
+	return files, nil
+}
+
+// sqlInsertBatch is the number of rows written per INSERT statement
+const sqlInsertBatch = 500
+
+// FormatSQL formats USFM documents as a SQL script that creates and fills the normalized
+// tables described for FormatCSVTables. The script runs in a single transaction and uses
+// only TEXT and INTEGER columns, multi-row INSERT statements, and standard string quoting,
+// so it loads into both SQLite (sqlite3 bible.db < bible.sql) and PostgreSQL (psql -f bible.sql).
+func FormatSQL(documents []*usfm.Document) (string, error) {
+	var result strings.Builder
+	if err := WriteSQL(&result, documents); err != nil {
+		return "", err
+	}
+	return result.String(), nil
+}
+
+// WriteSQL writes USFM documents as the SQL script described for FormatSQL to w.
+// The tables are built first, and the script is written one INSERT statement at a time.
+func WriteSQL(w io.Writer, documents []*usfm.Document) error {
+	var result strings.Builder
+	tables := relationalTables(documents)
+
+	result.WriteString("-- Generated by usfmp\n")
+	result.WriteString("BEGIN;\n")
+
+	for _, table := range tables {
+		fmt.Fprintf(&result, "\nCREATE TABLE %s (\n", table.name)
+		for i, column := range table.columns {
+			fmt.Fprintf(&result, "  %s %s", column.name, column.definition)
+			if i < len(table.columns)-1 {
+				result.WriteString(",")
+			}
+			result.WriteString("\n")
+		}
+		result.WriteString(");\n")
+	}
+
+	for _, table := range tables {
+		names := make([]string, len(table.columns))
+		for i, column := range table.columns {
+			names[i] = column.name
+		}
+		columns := strings.Join(names, ", ")
+
+		for start := 0; start < len(table.rows); start += sqlInsertBatch {
+			end := min(start+sqlInsertBatch, len(table.rows))
+			fmt.Fprintf(&result, "\nINSERT INTO %s (%s) VALUES\n", table.name, columns)
+			for i, row := range table.rows[start:end] {
+				if i > 0 {
+					result.WriteString(",\n")
+				}
+				result.WriteString("  (")
+				for j, value := range row {
+					if j > 0 {
+						result.WriteString(", ")
+					}
+					result.WriteString(sqlValue(value))
+				}
+				result.WriteString(")")
+			}
+			result.WriteString(";\n")
+			if err := flushOutput(w, &result); err != nil {
+				return err
+			}
+		}
+	}
+
+	result.WriteString("\nCOMMIT;\n")
+	return flushOutput(w, &result)
+}
+
+// sqlValue returns a value as a SQL literal
+func sqlValue(value any) string {
+	switch value := value.(type) {
+	case nil:
+		return "NULL"
+	case int:
+		return strconv.Itoa(value)
+	default:
+		return "'" + strings.ReplaceAll(fmt.Sprint(value), "'", "''") + "'"
+	}
+}
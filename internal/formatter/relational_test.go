@@ -0,0 +1,182 @@
+package formatter
+
+import (
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"github.com/arenzana/usfmp/pkg/ref"
+	"github.com/arenzana/usfmp/pkg/usfm"
+)
+
+// TestFormatCSVTables tests the normalized tables and their foreign keys
+func TestFormatCSVTables(t *testing.T) {
+	doc := createTestDocument()
+	doc.Chapters[0].Sections[0].Verses[1].EndNumber = 3
+	// The parser appends a \d descriptive title to the cross-references
+	doc.Chapters[0].Sections[0].Reference = "(John 1:1–5; Hebrews 11; Ps 33:6); A Psalm of David."
+
+	files, err := FormatCSVTables([]*usfm.Document{doc})
+	if err != nil {
+		t.Fatalf("FormatCSVTables failed: %v", err)
+	}
+
+	tables := make(map[string][][]string)
+	for _, file := range files {
+		records, err := csv.NewReader(strings.NewReader(string(file.Data))).ReadAll()
+		if err != nil {
+			t.Fatalf("%s does not parse: %v", file.Name, err)
+		}
+		tables[file.Name] = records
+	}
+
+	expected := map[string][][]string{
+		"books.csv": {
+			{"id", "position", "code", "identification", "title", "source_file"},
+			{"GEN", "1", "GEN", "GEN", "Genesis", "test.sfm"},
+		},
+		"chapters.csv": {
+			{"id", "book_id", "number"},
+			{"GEN.1", "GEN", "1"},
+		},
+		"sections.csv": {
+			{"id", "chapter_id", "position", "level", "title"},
+			{"GEN.1.s1", "GEN.1", "1", "1", "The Creation"},
+		},
+		"verses.csv": {
+			{"id", "chapter_id", "section_id", "number", "end_number", "text", "plain_text"},
+			{"GEN.1.1", "GEN.1", "GEN.1.s1", "1", "", "In the beginning God created the heavens and the earth.", "In the beginning God created the heavens and the earth."},
+			{"GEN.1.2", "GEN.1", "GEN.1.s1", "2", "3", "Now the earth was formless and void.", "Now the earth was formless and void."},
+		},
+		"footnotes.csv": {
+			{"id", "verse_id", "position", "caller", "reference", "text", "plain_text"},
+			{"GEN.1.1.f1", "GEN.1.1", "1", "+", "1:1", "Hebrew: Elohim", "Hebrew: Elohim"},
+		},
+		"cross_references.csv": {
+			{"id", "section_id", "position", "book", "chapter", "verse", "end_book", "end_chapter", "end_verse", "reference"},
+			{"GEN.1.s1.r1", "GEN.1.s1", "1", "JHN", "1", "1", "JHN", "1", "5", "John 1:1–5"},
+			{"GEN.1.s1.r2", "GEN.1.s1", "2", "HEB", "11", "", "HEB", "11", "", "Hebrews 11"},
+			{"GEN.1.s1.r3", "GEN.1.s1", "3", "PSA", "33", "6", "PSA", "33", "6", "Psalms 33:6"},
+		},
+	}
+
+	if len(files) != len(expected) {
+		t.Errorf("Expected %d tables, got %d", len(expected), len(files))
+	}
+	for name, records := range expected {
+		got, ok := tables[name]
+		if !ok {
+			t.Errorf("Missing table %s", name)
+			continue
+		}
+		if len(got) != len(records) {
+			t.Errorf("%s: expected %d records, got %d", name, len(records), len(got))
+			continue
+		}
+		for i := range records {
+			if strings.Join(got[i], "|") != strings.Join(records[i], "|") {
+				t.Errorf("%s record %d: expected %v, got %v", name, i, records[i], got[i])
+			}
+		}
+	}
+}
+
+// TestSectionReferences tests separating the cross-references of a section from a descriptive title
+func TestSectionReferences(t *testing.T) {
+	testCases := []struct {
+		reference string
+		expected  string
+		rest      string
+	}{
+		{"", "", ""},
+		{"(Matthew 5:3–12; Luke 6:20–23)", "Matthew 5:3–12; Luke 6:20–23", ""},
+		{"(2 Samuel 15:13–29); A Psalm of David, when he fled from his son Absalom.", "2 Samuel 15:13–29", "A Psalm of David, when he fled from his son Absalom."},
+		{"For the choirmaster. A Psalm of David.", "", "For the choirmaster. A Psalm of David."},
+		{"(v. 3; 2:1)", "Genesis 1:3; 2:1", ""},
+	}
+
+	for _, tc := range testCases {
+		ranges, rest := sectionReferences(usfm.Section{Reference: tc.reference}, "GEN", 1)
+		if result := ref.Format(ranges, ref.Default); result != tc.expected || rest != tc.rest {
+			t.Errorf("sectionReferences(%q): expected %q and %q, got %q and %q", tc.reference, tc.expected, tc.rest, result, rest)
+		}
+	}
+}
+
+// TestRelationalIDs tests that repeated books and verses get unique IDs
+func TestRelationalIDs(t *testing.T) {
+	doc := createTestDocument()
+	doc.Chapters[0].Sections[0].Verses[1].Number = 1
+
+	tables := relationalTables([]*usfm.Document{doc, createTestDocument()})
+
+	var bookIDs, verseIDs []string
+	for _, table := range tables {
+		for _, row := range table.rows {
+			switch table.name {
+			case "books":
+				bookIDs = append(bookIDs, row[0].(string))
+			case "verses":
+				verseIDs = append(verseIDs, row[0].(string))
+			}
+		}
+	}
+
+	if strings.Join(bookIDs, ",") != "GEN,GEN-2" {
+		t.Errorf("Expected book IDs GEN,GEN-2, got %v", bookIDs)
+	}
+	if strings.Join(verseIDs, ",") != "GEN.1.1,GEN.1.1-2,GEN-2.1.1,GEN-2.1.2" {
+		t.Errorf("Unexpected verse IDs %v", verseIDs)
+	}
+}
+
+// TestFormatSQL tests the SQL script
+func TestFormatSQL(t *testing.T) {
+	doc := createTestDocument()
+	doc.Chapters[0].Sections[0].Verses[0].Footnotes[0].Text = "Hebrew: God's name"
+
+	result, err := FormatSQL([]*usfm.Document{doc})
+	if err != nil {
+		t.Fatalf("FormatSQL failed: %v", err)
+	}
+
+	expectedContent := []string{
+		"BEGIN;\n",
+		"CREATE TABLE verses (\n  id TEXT PRIMARY KEY,\n  chapter_id TEXT NOT NULL REFERENCES chapters(id),\n",
+		"INSERT INTO books (id, position, code, identification, title, source_file) VALUES\n  ('GEN', 1, 'GEN', 'GEN', 'Genesis', 'test.sfm');\n",
+		"  ('GEN.1.1', 'GEN.1', 'GEN.1.s1', 1, NULL, 'In the beginning God created the heavens and the earth.', ",
+		"'Hebrew: God''s name'",
+		"COMMIT;\n",
+	}
+	for _, expected := range expectedContent {
+		if !strings.Contains(result, expected) {
+			t.Errorf("SQL output should contain %q", expected)
+		}
+	}
+
+	// Referenced tables must be created and filled first
+	if strings.Index(result, "CREATE TABLE books") > strings.Index(result, "CREATE TABLE chapters") ||
+		strings.Index(result, "INSERT INTO verses") > strings.Index(result, "INSERT INTO footnotes") {
+		t.Error("Tables should be created and filled in dependency order")
+	}
+}
+
+// TestSQLValue tests SQL literals
+func TestSQLValue(t *testing.T) {
+	testCases := []struct {
+		value    any
+		expected string
+	}{
+		{nil, "NULL"},
+		{42, "42"},
+		{"text", "'text'"},
+		{"it's", "'it''s'"},
+		{`back\slash`, `'back\slash'`},
+	}
+
+	for _, tc := range testCases {
+		if result := sqlValue(tc.value); result != tc.expected {
+			t.Errorf("sqlValue(%v): expected %s, got %s", tc.value, tc.expected, result)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/arenzana/usfmp/pkg/usfm"
+)
+
+// SSMLOptions configures the SSML output.
+type SSMLOptions struct {
+	Language         string        // Language of the text for the speech engine (e.g., "en"); defaults to "en"
+	ReadHeadings     bool          // Whether to read section headings aloud
+	ReadVerseNumbers bool          // Whether to read verse numbers aloud before each verse
+	ReadFootnotes    bool          // Whether to read footnotes aloud after their verse
+	SectionPause     time.Duration // Pause between sections
+	ParagraphPause   time.Duration // Pause between paragraphs and poetry lines
+}
+
+// DefaultSSMLOptions returns the default SSML options: only the Bible text is read,
+// with a one-second pause between sections and a shorter pause between paragraphs.
+func DefaultSSMLOptions() SSMLOptions {
+	return SSMLOptions{
+		Language:       "en",
+		SectionPause:   time.Second,
+		ParagraphPause: 500 * time.Millisecond,
+	}
+}
+
+// FormatSSML formats each chapter of the USFM documents as an SSML 1.1 document for
+// text-to-speech engines such as Amazon Polly, Azure Speech, and Google Cloud Text-to-Speech.
+// The chapters of a book are written into a folder named after the book
+// (e.g., "01-GEN/01-GEN-001.ssml" for Genesis 1).
+//
+// Each document starts by announcing the book and chapter. Every verse is preceded by
+// a <mark name="GEN.1.1"/> element, which speech engines report with its time offset so
+// that the audio can be aligned with the text. Paragraphs and poetry lines become <p>
+// elements separated by a pause, and a longer pause separates sections. Cross-references,
+// verse numbers, footnotes, and section headings are not read unless enabled in the options.
+func FormatSSML(documents []*usfm.Document, options SSMLOptions) ([]OutputFile, error) {
+	var files []OutputFile
+	names := bookFileNames(documents, "ssml")
+
+	language := options.Language
+	if language == "" {
+		language = "en"
+	}
+
+	for i, doc := range documents {
+		folder := strings.TrimSuffix(names[i], ".ssml")
+		book := doc.BookCode()
+		if book == "" {
+			book = folder
+		}
+
+		for _, chapter := range doc.Chapters {
+			var result strings.Builder
+
+			result.WriteString(xmlHeader)
+			fmt.Fprintf(&result, "<speak version=\"1.1\" xmlns=\"http://www.w3.org/2001/10/synthesis\" xml:lang=\"%s\">\n", escapeXML(language))
+			fmt.Fprintf(&result, "<p><mark name=\"%s.%d\"/>%s, Chapter %d.</p>\n", escapeXML(book), chapter.Number,
+				escapeXML(usfm.PlainText(bookTitle(doc))), chapter.Number)
+			ssmlBreak(&result, options.SectionPause)
+
+			for s, section := range chapter.Sections {
+				if s > 0 {
+					ssmlBreak(&result, options.SectionPause)
+				}
+				writeSSMLSection(&result, book, chapter.Number, section, options)
+			}
+
+			result.WriteString("</speak>\n")
+			files = append(files, OutputFile{
+				Name: fmt.Sprintf("%s/%s-%03d.ssml", folder, folder, chapter.Number),
+				Data: []byte(result.String()),
+			})
+		}
+	}
+
+	return files, nil
+}
+
+// writeSSMLSection writes the heading and verses of a section as SSML paragraphs
+func writeSSMLSection(result *strings.Builder, book string, chapter int, section usfm.Section, options SSMLOptions) {
+	if options.ReadHeadings && section.Title != "" {
+		fmt.Fprintf(result, "<p>%s</p>\n", escapeXML(lineText(section.Title)))
+		ssmlBreak(result, options.ParagraphPause)
+	}
+
+	// Verses flow into paragraphs; a line break starts a new paragraph or poetry line
+	open := false
+	for _, verse := range section.Verses {
+		lines := verse.Lines
+		if len(lines) == 0 {
+			lines = []usfm.Line{{Text: verse.Text}}
+		}
+
+		for i, line := range lines {
+			if line.Break || !open {
+				if open {
+					result.WriteString("</p>\n")
+					ssmlBreak(result, options.ParagraphPause)
+				}
+				result.WriteString("<p>")
+				open = true
+			} else {
+				result.WriteString(" ")
+			}
+
+			if i == 0 {
+				fmt.Fprintf(result, "<mark name=\"%s.%d.%d\"/>", escapeXML(book), chapter, verse.Number)
+				if options.ReadVerseNumbers {
+					fmt.Fprintf(result, "<say-as interpret-as=\"cardinal\">%d</say-as> ", verse.Number)
+				}
+			}
+			result.WriteString(escapeXML(lineText(line.Text)))
+		}
+
+		if options.ReadFootnotes {
+			for _, footnote := range verse.Footnotes {
+				fmt.Fprintf(result, " <break strength=\"medium\"/><emphasis level=\"reduced\">%s</emphasis><break strength=\"medium\"/>",
+					escapeXML(lineText(footnote.Text)))
+			}
+		}
+	}
+	if open {
+		result.WriteString("</p>\n")
+	}
+}
+
+// ssmlBreak writes a pause of the given length; no pause is written for zero or negative lengths
+func ssmlBreak(result *strings.Builder, pause time.Duration) {
+	if pause > 0 {
+		fmt.Fprintf(result, "<break time=\"%dms\"/>\n", pause.Milliseconds())
+	}
+}
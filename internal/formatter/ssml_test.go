@@ -0,0 +1,87 @@
+package formatter
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/arenzana/usfmp/pkg/usfm"
+)
+
+// TestFormatSSML tests SSML output with the default options
+func TestFormatSSML(t *testing.T) {
+	doc := createTestDocument()
+	doc.Chapters[0].Sections[0].Verses[1].Lines = []usfm.Line{
+		{Marker: "q1", Text: `Now the \nd Lord\nd* said,`, Break: true},
+		{Marker: "q2", Text: "<here>", Break: true},
+	}
+	doc.Chapters[0].Sections = append(doc.Chapters[0].Sections, usfm.Section{
+		Level:  1,
+		Title:  "The Garden",
+		Verses: []usfm.Verse{{Number: 3, Text: "And God said."}},
+	})
+
+	files, err := FormatSSML([]*usfm.Document{doc}, DefaultSSMLOptions())
+	if err != nil {
+		t.Fatalf("FormatSSML failed: %v", err)
+	}
+	if len(files) != 1 || files[0].Name != "01-GEN/01-GEN-001.ssml" {
+		t.Fatalf("Expected one file 01-GEN/01-GEN-001.ssml, got %v", files)
+	}
+
+	result := string(files[0].Data)
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<speak version="1.1" xmlns="http://www.w3.org/2001/10/synthesis" xml:lang="en">
+<p><mark name="GEN.1"/>Genesis, Chapter 1.</p>
+<break time="1000ms"/>
+<p><mark name="GEN.1.1"/>In the beginning God created the heavens and the earth.</p>
+<break time="500ms"/>
+<p><mark name="GEN.1.2"/>Now the Lord said,</p>
+<break time="500ms"/>
+<p>&lt;here&gt;</p>
+<break time="1000ms"/>
+<p><mark name="GEN.1.3"/>And God said.</p>
+</speak>
+`
+	if result != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, result)
+	}
+
+	if err := xml.Unmarshal(files[0].Data, new(any)); err != nil {
+		t.Errorf("SSML output is not well-formed XML: %v", err)
+	}
+}
+
+// TestFormatSSMLReadAloud tests reading headings, verse numbers, and footnotes aloud
+func TestFormatSSMLReadAloud(t *testing.T) {
+	options := DefaultSSMLOptions()
+	options.Language = "en-GB"
+	options.ReadHeadings = true
+	options.ReadVerseNumbers = true
+	options.ReadFootnotes = true
+	options.ParagraphPause = 0
+
+	files, err := FormatSSML([]*usfm.Document{createTestDocument()}, options)
+	if err != nil {
+		t.Fatalf("FormatSSML failed: %v", err)
+	}
+	result := string(files[0].Data)
+
+	expectedContent := []string{
+		`xml:lang="en-GB"`,
+		"<p>The Creation</p>\n<p>",
+		`<mark name="GEN.1.1"/><say-as interpret-as="cardinal">1</say-as> In the beginning`,
+		`<break strength="medium"/><emphasis level="reduced">Hebrew: Elohim</emphasis><break strength="medium"/>`,
+	}
+	for _, expected := range expectedContent {
+		if !strings.Contains(result, expected) {
+			t.Errorf("SSML output should contain %q", expected)
+		}
+	}
+	if strings.Contains(result, "John 1:1") {
+		t.Error("SSML output should not read cross-references")
+	}
+	if count := strings.Count(result, `<break time="`); count != 1 {
+		t.Errorf("Expected only the pause after the chapter announcement, got %d pauses", count)
+	}
+}
@@ -0,0 +1,193 @@
+package formatter
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/arenzana/usfmp/pkg/usfm"
+)
+
+// TableOptions configures the CSV and TSV outputs.
+type TableOptions struct {
+	Columns  []string // Column names in output order; defaults to DefaultColumns
+	NoHeader bool     // Whether to leave out the header row
+}
+
+// tableRow is the verse that a row of the CSV or TSV output describes, with its location
+type tableRow struct {
+	doc     *usfm.Document
+	chapter int
+	section usfm.Section
+	verse   usfm.Verse
+}
+
+// tableColumn is a column of the CSV and TSV outputs
+type tableColumn struct {
+	name   string                // Name used to select the column
+	header string                // Text of the header row
+	value  func(tableRow) string // Field value for a verse
+}
+
+// tableColumns lists the columns that can be selected for the CSV and TSV outputs
+var tableColumns = []tableColumn{
+	{"book", "Book", func(row tableRow) string {
+		if row.doc.ID == "" {
+			return "UNKNOWN"
+		}
+		return row.doc.ID
+	}},
+	{"book_code", "Book_Code", func(row tableRow) string { return row.doc.BookCode() }},
+	{"chapter", "Chapter", func(row tableRow) string { return strconv.Itoa(row.chapter) }},
+	{"verse", "Verse", func(row tableRow) string { return strconv.Itoa(row.verse.Number) }},
+	{"verse_end", "Verse_End", func(row tableRow) string { return strconv.Itoa(row.verse.LastNumber()) }},
+	{"section_title", "Section_Title", func(row tableRow) string { return row.section.Title }},
+	{"section_level", "Section_Level", func(row tableRow) string { return strconv.Itoa(row.section.Level) }},
+	{"text", "Verse_Text", func(row tableRow) string { return row.verse.Text }},
+	{"plain_text", "Plain_Text", func(row tableRow) string { return usfm.PlainText(row.verse.Text) }},
+	{"word_count", "Word_Count", func(row tableRow) string {
+		return strconv.Itoa(len(strings.Fields(usfm.PlainText(row.verse.Text))))
+	}},
+	{"paragraph", "Paragraph", func(row tableRow) string {
+		if len(row.verse.Lines) == 0 {
+			return ""
+		}
+		return row.verse.Lines[0].Marker
+	}},
+	{"footnotes", "Footnotes", func(row tableRow) string {
+		var footnotes []string
+		for _, footnote := range row.verse.Footnotes {
+			footnotes = append(footnotes, fmt.Sprintf("%s:%s=%s", footnote.Caller, footnote.Reference, footnote.Text))
+		}
+		return strings.Join(footnotes, "; ")
+	}},
+	{"footnote_count", "Footnote_Count", func(row tableRow) string { return strconv.Itoa(len(row.verse.Footnotes)) }},
+	{"references", "References", func(row tableRow) string { return row.section.Reference }},
+	{"source_file", "Source_File", func(row tableRow) string { return row.doc.SourceFile }},
+}
+
+// DefaultColumns are the columns written when no columns are selected
+var DefaultColumns = []string{"book", "chapter", "verse", "section_title", "section_level", "text", "footnotes", "references"}
+
+// ColumnNames returns the names of all columns that can be selected for the CSV and TSV outputs.
+func ColumnNames() []string {
+	names := make([]string, len(tableColumns))
+	for i, column := range tableColumns {
+		names[i] = column.name
+	}
+	return names
+}
+
+// ParseColumns parses a comma-separated list of column names (e.g., "book,chapter,verse,plain_text").
+// An empty value selects DefaultColumns.
+func ParseColumns(value string) ([]string, error) {
+	if strings.TrimSpace(value) == "" {
+		return DefaultColumns, nil
+	}
+
+	var names []string
+	for _, name := range strings.Split(value, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, err := lookupColumn(name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// lookupColumn finds a column by name
+func lookupColumn(name string) (tableColumn, error) {
+	for _, column := range tableColumns {
+		if column.name == name {
+			return column, nil
+		}
+	}
+	return tableColumn{}, fmt.Errorf("unknown column %q (valid: %s)", name, strings.Join(ColumnNames(), ", "))
+}
+
+// writeTableRecords calls write with the header and one record per verse for the selected columns
+func writeTableRecords(documents []*usfm.Document, options TableOptions, write func(record []string) error) error {
+	names := options.Columns
+	if len(names) == 0 {
+		names = DefaultColumns
+	}
+
+	columns := make([]tableColumn, len(names))
+	for i, name := range names {
+		column, err := lookupColumn(name)
+		if err != nil {
+			return err
+		}
+		columns[i] = column
+	}
+
+	if !options.NoHeader {
+		header := make([]string, len(columns))
+		for i, column := range columns {
+			header[i] = column.header
+		}
+		if err := write(header); err != nil {
+			return err
+		}
+	}
+
+	record := make([]string, len(columns))
+	for _, doc := range documents {
+		for _, chapter := range doc.Chapters {
+			for _, section := range chapter.Sections {
+				for _, verse := range section.Verses {
+					row := tableRow{doc: doc, chapter: chapter.Number, section: section, verse: verse}
+					for i, column := range columns {
+						record[i] = column.value(row)
+					}
+					if err := write(record); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// FormatCSV formats USFM documents as RFC 4180 Comma-Separated Values, one row per verse.
+// The columns are chosen and ordered with options.Columns (see ColumnNames); by default
+// they are the same as the TSV output. Fields that contain commas, quotes, or line breaks
+// are quoted, so text is written unchanged. Lines end with CRLF as required by RFC 4180.
+func FormatCSV(documents []*usfm.Document, options TableOptions) (string, error) {
+	var result strings.Builder
+	if err := WriteCSV(&result, documents, options); err != nil {
+		return "", err
+	}
+	return result.String(), nil
+}
+
+// WriteCSV writes USFM documents as CSV to w, one row at a time, as described for FormatCSV.
+func WriteCSV(w io.Writer, documents []*usfm.Document, options TableOptions) error {
+	writer := csv.NewWriter(w)
+	writer.UseCRLF = true
+
+	if err := writeTableRecords(documents, options, writer.Write); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
+
+// writeCSV writes records as RFC 4180 CSV with CRLF line endings
+func writeCSV(records [][]string) (string, error) {
+	var result strings.Builder
+	writer := csv.NewWriter(&result)
+	writer.UseCRLF = true
+	if err := writer.WriteAll(records); err != nil {
+		return "", err
+	}
+	return result.String(), nil
+}
@@ -0,0 +1,120 @@
+package formatter
+
+import (
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"github.com/arenzana/usfmp/pkg/usfm"
+)
+
+// TestFormatCSV tests that CSV output is quoted according to RFC 4180
+func TestFormatCSV(t *testing.T) {
+	doc := createTestDocument()
+	doc.Chapters[0].Sections[0].Verses[1].Text = "He said, \"Let there be\nlight.\""
+	documents := []*usfm.Document{doc}
+
+	result, err := FormatCSV(documents, TableOptions{})
+	if err != nil {
+		t.Fatalf("FormatCSV failed: %v", err)
+	}
+
+	if !strings.HasPrefix(result, "Book,Chapter,Verse,Section_Title,Section_Level,Verse_Text,Footnotes,References\r\n") {
+		t.Errorf("Expected default header with CRLF, got %q", strings.SplitN(result, "\n", 2)[0])
+	}
+	if !strings.Contains(result, `"He said, ""Let there be`+"\r\n"+`light."""`) {
+		t.Errorf("Expected quoted field with escaped quotes and newline, got %q", result)
+	}
+
+	records, err := csv.NewReader(strings.NewReader(result)).ReadAll()
+	if err != nil {
+		t.Fatalf("CSV output does not parse: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("Expected 3 records, got %d", len(records))
+	}
+	if records[2][5] != "He said, \"Let there be\nlight.\"" {
+		t.Errorf("Expected verse text to round-trip, got %q", records[2][5])
+	}
+	if records[1][6] != "+:1:1=Hebrew: Elohim" {
+		t.Errorf("Expected formatted footnotes, got %q", records[1][6])
+	}
+}
+
+// TestFormatCSVColumns tests column selection, ordering, and the header switch
+func TestFormatCSVColumns(t *testing.T) {
+	doc := createTestDocument()
+	verses := doc.Chapters[0].Sections[0].Verses
+	verses[0].Text = `In the beginning \add God\add* created.`
+	verses[0].Lines = []usfm.Line{{Marker: "q1", Text: verses[0].Text, Break: true}}
+	verses[1].EndNumber = 3
+	documents := []*usfm.Document{doc}
+
+	columns, err := ParseColumns("verse, verse_end,word_count,paragraph,footnote_count,plain_text,source_file,book_code")
+	if err != nil {
+		t.Fatalf("ParseColumns failed: %v", err)
+	}
+
+	result, err := FormatCSV(documents, TableOptions{Columns: columns, NoHeader: true})
+	if err != nil {
+		t.Fatalf("FormatCSV failed: %v", err)
+	}
+
+	expected := "1,1,5,q1,1,In the beginning God created.,test.sfm,GEN\r\n" +
+		"2,3,7,,0,Now the earth was formless and void.,test.sfm,GEN\r\n"
+	if result != expected {
+		t.Errorf("Expected:\n%q\ngot:\n%q", expected, result)
+	}
+}
+
+// TestParseColumns tests parsing of column lists
+func TestParseColumns(t *testing.T) {
+	columns, err := ParseColumns("")
+	if err != nil || strings.Join(columns, ",") != strings.Join(DefaultColumns, ",") {
+		t.Errorf("Expected default columns for an empty value, got %v (%v)", columns, err)
+	}
+
+	columns, err = ParseColumns("Book,TEXT")
+	if err != nil || strings.Join(columns, ",") != "book,text" {
+		t.Errorf("Expected case-insensitive column names, got %v (%v)", columns, err)
+	}
+
+	if _, err := ParseColumns("book,unknown"); err == nil {
+		t.Error("Expected error for unknown column")
+	}
+	if _, err := FormatCSV(nil, TableOptions{Columns: []string{"unknown"}}); err == nil {
+		t.Error("Expected FormatCSV to reject unknown column")
+	}
+}
+
+// TestFormatTSVColumns tests that TSV output shares the column selection
+func TestFormatTSVColumns(t *testing.T) {
+	doc := createTestDocument()
+	doc.Chapters[0].Sections[0].Verses[1].Text = "formless\tand\nvoid"
+
+	result, err := FormatTSV([]*usfm.Document{doc}, TableOptions{Columns: []string{"chapter", "verse", "text"}})
+	if err != nil {
+		t.Fatalf("FormatTSV failed: %v", err)
+	}
+
+	expected := "Chapter\tVerse\tVerse_Text\n" +
+		"1\t1\tIn the beginning God created the heavens and the earth.\n" +
+		"1\t2\tformless and void\n"
+	if result != expected {
+		t.Errorf("Expected:\n%q\ngot:\n%q", expected, result)
+	}
+}
+
+// TestFormatTSVBook tests that the book ID is written as it is, as it was before columns could be chosen
+func TestFormatTSVBook(t *testing.T) {
+	doc := createTestDocument()
+	doc.ID = "GEN  Genesis"
+
+	result, err := FormatTSV([]*usfm.Document{doc}, TableOptions{Columns: []string{"book", "verse"}})
+	if err != nil {
+		t.Fatalf("FormatTSV failed: %v", err)
+	}
+	if !strings.Contains(result, "\nGEN  Genesis\t1\n") {
+		t.Errorf("Expected the book ID unchanged, got:\n%q", result)
+	}
+}
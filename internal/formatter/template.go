@@ -0,0 +1,167 @@
+package formatter
+
+import (
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/arenzana/usfmp/pkg/usfm"
+)
+
+// TemplateOptions configures the template output.
+type TemplateOptions struct {
+	Name  string // Template name used in error messages (e.g., the file name)
+	Text  string // Template source in Go text/template syntax
+	HTML  bool   // Whether to execute the template with html/template, which escapes text for HTML
+	Title string // Title made available to the template as .Title
+}
+
+// TemplateData is the data a template is executed with.
+type TemplateData struct {
+	Title     string           // Title given with the --title flag, if any
+	Documents []*usfm.Document // Parsed documents in input order
+}
+
+// TemplateFuncs returns the helper functions available to templates:
+//
+//	bookName CODE              canonical English book name ("GEN" -> "Genesis"), or CODE if unknown
+//	title DOC                  display title of a document (\mt1, \h, or the book code)
+//	ref CODE CHAPTER VERSE     reference with the book name ("Genesis 1:1"); VERSE is a number or a verse (bridges become "1:1-2")
+//	osisRef CODE CHAPTER VERSE OSIS reference ("Gen.1.1")
+//	plain TEXT                 text without USFM character markup
+//	footnote FOOTNOTE          footnote as "reference text" with the text in plain form
+//	verses CHAPTER             all verses of a chapter across its sections
+//	join SEP LIST              list of strings joined with SEP
+//	upper TEXT, lower TEXT     text in upper or lower case
+func TemplateFuncs() map[string]any {
+	return map[string]any{
+		"bookName": templateBookName,
+		"title":    bookTitle,
+		"ref":      templateRef,
+		"osisRef":  templateOSISRef,
+		"plain":    usfm.PlainText,
+		"footnote": templateFootnote,
+		"verses":   templateVerses,
+		"join":     func(separator string, items []string) string { return strings.Join(items, separator) },
+		"upper":    strings.ToUpper,
+		"lower":    strings.ToLower,
+	}
+}
+
+// FormatTemplate formats USFM documents with a user-supplied Go template, so that new text
+// layouts can be defined without writing a formatter. The template is executed once with
+// TemplateData and can use the helpers described in TemplateFuncs.
+//
+// Example template writing one line per verse:
+//
+//	{{range .Documents}}{{$book := .BookCode}}{{range .Chapters}}{{$chapter := .Number}}{{range verses .}}
+//	{{ref $book $chapter .}}	{{plain .Text}}{{end}}{{end}}{{end}}
+func FormatTemplate(documents []*usfm.Document, options TemplateOptions) (string, error) {
+	var result strings.Builder
+	if err := WriteTemplate(&result, documents, options); err != nil {
+		return "", err
+	}
+	return result.String(), nil
+}
+
+// WriteTemplate executes a user-supplied Go template as described for FormatTemplate,
+// writing its output to w as the template produces it.
+func WriteTemplate(w io.Writer, documents []*usfm.Document, options TemplateOptions) error {
+	name := options.Name
+	if name == "" {
+		name = "template"
+	}
+	data := TemplateData{Title: options.Title, Documents: documents}
+
+	var execute func(w io.Writer) error
+	if options.HTML {
+		tmpl, err := htmltemplate.New(name).Funcs(TemplateFuncs()).Parse(options.Text)
+		if err != nil {
+			return fmt.Errorf("failed to parse template: %w", err)
+		}
+		execute = func(w io.Writer) error { return tmpl.Execute(w, data) }
+	} else {
+		tmpl, err := template.New(name).Funcs(TemplateFuncs()).Parse(options.Text)
+		if err != nil {
+			return fmt.Errorf("failed to parse template: %w", err)
+		}
+		execute = func(w io.Writer) error { return tmpl.Execute(w, data) }
+	}
+
+	if err := execute(w); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+	return nil
+}
+
+// templateBookName returns the canonical English name of a book, or the code itself if unknown
+func templateBookName(code string) string {
+	if book, ok := usfm.LookupBook(code); ok {
+		return book.Name
+	}
+	return code
+}
+
+// templateVerseNumbers returns the verse numbers of a verse number or usfm.Verse argument
+func templateVerseNumbers(verse any) (int, int, error) {
+	switch verse := verse.(type) {
+	case int:
+		return verse, 0, nil
+	case usfm.Verse:
+		return verse.Number, verse.EndNumber, nil
+	case *usfm.Verse:
+		return verse.Number, verse.EndNumber, nil
+	default:
+		return 0, 0, fmt.Errorf("expected a verse number or verse, got %T", verse)
+	}
+}
+
+// templateRef formats a reference with the book name, such as "Genesis 1:1" or "Genesis 1:1-2"
+func templateRef(code string, chapter int, verse any) (string, error) {
+	number, end, err := templateVerseNumbers(verse)
+	if err != nil {
+		return "", err
+	}
+	reference := fmt.Sprintf("%s %d:%d", templateBookName(code), chapter, number)
+	if end > 0 {
+		reference += fmt.Sprintf("-%d", end)
+	}
+	return reference, nil
+}
+
+// templateOSISRef formats an OSIS reference, such as "Gen.1.1" or "Gen.1.1-Gen.1.2"
+func templateOSISRef(code string, chapter int, verse any) (string, error) {
+	number, end, err := templateVerseNumbers(verse)
+	if err != nil {
+		return "", err
+	}
+	book := code
+	if entry, ok := usfm.LookupBook(code); ok {
+		book = entry.OSIS
+	}
+	reference := fmt.Sprintf("%s.%d.%d", book, chapter, number)
+	if end > 0 {
+		reference += fmt.Sprintf("-%s.%d.%d", book, chapter, end)
+	}
+	return reference, nil
+}
+
+// templateFootnote renders a footnote as its reference followed by its plain text
+func templateFootnote(footnote usfm.Footnote) string {
+	text := usfm.PlainText(footnote.Text)
+	if footnote.Reference == "" {
+		return text
+	}
+	return footnote.Reference + " " + text
+}
+
+// templateVerses returns all verses of a chapter across its sections
+func templateVerses(chapter usfm.Chapter) []usfm.Verse {
+	var verses []usfm.Verse
+	for _, section := range chapter.Sections {
+		verses = append(verses, section.Verses...)
+	}
+	return verses
+}
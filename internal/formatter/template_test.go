@@ -0,0 +1,76 @@
+package formatter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/arenzana/usfmp/pkg/usfm"
+)
+
+// TestFormatTemplate tests executing a text template with the helper functions
+func TestFormatTemplate(t *testing.T) {
+	doc := createTestDocument()
+	doc.Chapters[0].Sections[0].Verses[1].Text = `Now the \nd Lord\nd* & earth`
+	doc.Chapters[0].Sections[0].Verses[1].EndNumber = 3
+
+	text := `{{.Title}}
+{{range .Documents}}{{$book := .BookCode}}# {{title .}} ({{bookName $book | upper}})
+{{range .Chapters}}{{$chapter := .Number}}{{range verses .}}{{ref $book $chapter .}} [{{osisRef $book $chapter .}}] {{plain .Text}}
+{{range .Footnotes}}  note: {{footnote .}}
+{{end}}{{end}}{{end}}{{end}}{{ref "XYZ" 2 5}}`
+
+	result, err := FormatTemplate([]*usfm.Document{doc}, TemplateOptions{Text: text, Title: "Test Bible"})
+	if err != nil {
+		t.Fatalf("FormatTemplate failed: %v", err)
+	}
+
+	expected := `Test Bible
+# Genesis (GENESIS)
+Genesis 1:1 [Gen.1.1] In the beginning God created the heavens and the earth.
+  note: 1:1 Hebrew: Elohim
+Genesis 1:2-3 [Gen.1.2-Gen.1.3] Now the Lord & earth
+XYZ 2:5`
+	if result != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, result)
+	}
+}
+
+// TestFormatTemplateHTML tests that HTML templates escape text
+func TestFormatTemplateHTML(t *testing.T) {
+	doc := createTestDocument()
+	doc.Chapters[0].Sections[0].Verses[0].Text = "<script>alert(1)</script>"
+
+	text := `{{range .Documents}}{{range .Chapters}}{{range verses .}}<p>{{plain .Text}}</p>{{end}}{{end}}{{end}}`
+
+	result, err := FormatTemplate([]*usfm.Document{doc}, TemplateOptions{Text: text, HTML: true})
+	if err != nil {
+		t.Fatalf("FormatTemplate failed: %v", err)
+	}
+	if !strings.Contains(result, "<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>") {
+		t.Errorf("Expected escaped verse text, got %s", result)
+	}
+}
+
+// TestFormatTemplateErrors tests template parse and execution errors
+func TestFormatTemplateErrors(t *testing.T) {
+	documents := []*usfm.Document{createTestDocument()}
+
+	testCases := []struct {
+		text     string
+		expected string
+	}{
+		{"{{unknown .}}", "failed to parse template"},
+		{"{{range .Documents}", "failed to parse template"},
+		{`{{ref "GEN" 1 "one"}}`, "failed to execute template"},
+		{"{{.Missing}}", "failed to execute template"},
+	}
+
+	for _, tc := range testCases {
+		for _, html := range []bool{false, true} {
+			_, err := FormatTemplate(documents, TemplateOptions{Name: "test.tmpl", Text: tc.text, HTML: html})
+			if err == nil || !strings.Contains(err.Error(), tc.expected) {
+				t.Errorf("Template %q (html=%v): expected error containing %q, got %v", tc.text, html, tc.expected, err)
+			}
+		}
+	}
+}
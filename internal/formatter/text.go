@@ -2,6 +2,7 @@ package formatter
 
 import (
 	"fmt"
+	"io"
 	"strings"
 
 	"github.com/arenzana/usfmp/pkg/usfm"
@@ -22,8 +23,18 @@ import (
 // Multiple documents are separated by a line of equal signs.
 func FormatText(documents []*usfm.Document) (string, error) {
 	var result strings.Builder
+	if err := WriteText(&result, documents); err != nil {
+		return "", err
+	}
+	return result.String(), nil
+}
 
+// WriteText writes USFM documents as human-readable plain text to w, in the layout
+// described for FormatText. The output is written one chapter at a time.
+func WriteText(w io.Writer, documents []*usfm.Document) error {
 	for i, doc := range documents {
+		var result strings.Builder
+
 		if i > 0 {
 			result.WriteString("\n" + strings.Repeat("=", 80) + "\n\n")
 		}
@@ -46,46 +57,59 @@ func FormatText(documents []*usfm.Document) (string, error) {
 
 		// Chapters
 		for _, chapter := range doc.Chapters {
-			fmt.Fprintf(&result, "Chapter %d\n", chapter.Number)
-			result.WriteString(strings.Repeat("-", 20) + "\n\n")
-
-			// Sections
-			for _, section := range chapter.Sections {
-				if section.Title != "" {
-					// Add indent based on section level
-					indent := strings.Repeat("  ", section.Level-1)
-					fmt.Fprintf(&result, "%s%s\n", indent, section.Title)
-
-					if section.Reference != "" {
-						fmt.Fprintf(&result, "%s(%s)\n", indent, section.Reference)
-					}
-					result.WriteString("\n")
-				}
+			writeTextChapter(&result, chapter)
+			if _, err := io.WriteString(w, result.String()); err != nil {
+				return err
+			}
+			result.Reset()
+		}
 
-				// Verses
-				for _, verse := range section.Verses {
-					fmt.Fprintf(&result, "%d. %s", verse.Number, verse.Text)
-
-					// Add footnotes
-					if len(verse.Footnotes) > 0 {
-						result.WriteString(" [")
-						for j, footnote := range verse.Footnotes {
-							if j > 0 {
-								result.WriteString("; ")
-							}
-							fmt.Fprintf(&result, "%s:%s - %s",
-								footnote.Caller, footnote.Reference, footnote.Text)
-						}
-						result.WriteString("]")
-					}
+		if _, err := io.WriteString(w, result.String()); err != nil {
+			return err
+		}
+	}
 
-					result.WriteString("\n")
-				}
+	return nil
+}
 
-				result.WriteString("\n")
+// writeTextChapter writes the heading, sections, and verses of a chapter as plain text
+func writeTextChapter(result *strings.Builder, chapter usfm.Chapter) {
+	fmt.Fprintf(result, "Chapter %d\n", chapter.Number)
+	result.WriteString(strings.Repeat("-", 20) + "\n\n")
+
+	// Sections
+	for _, section := range chapter.Sections {
+		if section.Title != "" {
+			// Add indent based on section level
+			indent := strings.Repeat("  ", section.Level-1)
+			fmt.Fprintf(result, "%s%s\n", indent, section.Title)
+
+			if section.Reference != "" {
+				fmt.Fprintf(result, "%s(%s)\n", indent, section.Reference)
 			}
+			result.WriteString("\n")
 		}
-	}
 
-	return result.String(), nil
+		// Verses
+		for _, verse := range section.Verses {
+			fmt.Fprintf(result, "%d. %s", verse.Number, verse.Text)
+
+			// Add footnotes
+			if len(verse.Footnotes) > 0 {
+				result.WriteString(" [")
+				for j, footnote := range verse.Footnotes {
+					if j > 0 {
+						result.WriteString("; ")
+					}
+					fmt.Fprintf(result, "%s:%s - %s",
+						footnote.Caller, footnote.Reference, footnote.Text)
+				}
+				result.WriteString("]")
+			}
+
+			result.WriteString("\n")
+		}
+
+		result.WriteString("\n")
+	}
 }
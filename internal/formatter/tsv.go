@@ -1,70 +1,62 @@
 package formatter
 
 import (
-	"fmt"
+	"io"
 	"strings"
 
 	"github.com/arenzana/usfmp/pkg/usfm"
 )
 
 // FormatTSV formats USFM documents as Tab-Separated Values for data analysis.
-// Each verse becomes a row with columns for book, chapter, verse, section info,
+// Each verse becomes a row; the columns are chosen and ordered with options.Columns
+// (see ColumnNames) and default to book, chapter, verse, section info,
 // verse text, footnotes, and references.
 //
-// Column format:
+// Default column format:
 //
 //	Book	Chapter	Verse	Section_Title	Section_Level	Verse_Text	Footnotes	References
 //
 // Footnotes are formatted as "caller:reference=text" and separated by semicolons.
-// Text is cleaned of tabs and newlines to ensure proper TSV format.
+// Text is cleaned of tabs and newlines to ensure proper TSV format; use FormatCSV
+// to keep the text unchanged.
 // Multiple documents are included in the same output with their respective book IDs.
-func FormatTSV(documents []*usfm.Document) (string, error) {
+func FormatTSV(documents []*usfm.Document, options TableOptions) (string, error) {
 	var result strings.Builder
+	if err := WriteTSV(&result, documents, options); err != nil {
+		return "", err
+	}
+	return result.String(), nil
+}
 
-	// Header row
-	result.WriteString("Book\tChapter\tVerse\tSection_Title\tSection_Level\tVerse_Text\tFootnotes\tReferences\n")
-
-	for _, doc := range documents {
-		bookID := doc.ID
-		if bookID == "" {
-			bookID = "UNKNOWN"
-		}
-
-		for _, chapter := range doc.Chapters {
-			for _, section := range chapter.Sections {
-				sectionTitle := cleanTSVField(section.Title)
-				sectionLevel := fmt.Sprintf("%d", section.Level)
-				references := cleanTSVField(section.Reference)
-
-				for _, verse := range section.Verses {
-					verseText := cleanTSVField(verse.Text)
-
-					// Format footnotes
-					var footnotes []string
-					for _, footnote := range verse.Footnotes {
-						footnoteStr := fmt.Sprintf("%s:%s=%s",
-							footnote.Caller, footnote.Reference, footnote.Text)
-						footnotes = append(footnotes, footnoteStr)
-					}
-					footnotesField := cleanTSVField(strings.Join(footnotes, "; "))
+// WriteTSV writes USFM documents as TSV to w, one row at a time, as described for FormatTSV.
+func WriteTSV(w io.Writer, documents []*usfm.Document, options TableOptions) error {
+	names := options.Columns
+	if len(names) == 0 {
+		names = DefaultColumns
+	}
 
-					// Write TSV row
-					fmt.Fprintf(&result, "%s\t%d\t%d\t%s\t%s\t%s\t%s\t%s\n",
-						bookID,
-						chapter.Number,
-						verse.Number,
-						sectionTitle,
-						sectionLevel,
-						verseText,
-						footnotesField,
-						references,
-					)
-				}
+	fields := make([]string, 0, len(names))
+	return writeTableRecords(documents, options, func(record []string) error {
+		fields = fields[:0]
+		for i, field := range record {
+			if tsvTextColumns[names[i]] {
+				field = cleanTSVField(field)
 			}
+			fields = append(fields, field)
 		}
-	}
+		_, err := io.WriteString(w, strings.Join(fields, "\t")+"\n")
+		return err
+	})
+}
 
-	return result.String(), nil
+// tsvTextColumns are the columns of free text that WriteTSV cleans with cleanTSVField;
+// the other columns, such as the book ID, are written as they are
+var tsvTextColumns = map[string]bool{
+	"section_title": true,
+	"text":          true,
+	"plain_text":    true,
+	"footnotes":     true,
+	"references":    true,
 }
 
 // cleanTSVField cleans text for safe TSV output by removing tabs and newlines.
@@ -0,0 +1,145 @@
+package formatter
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/arenzana/usfmp/pkg/usfm"
+	"github.com/arenzana/usfmp/pkg/versification"
+)
+
+// corpusRange is written in a parallel corpus for a verse whose text is part of a verse bridge
+// on an earlier line, following the convention of the eBible corpus
+const corpusRange = "<range>"
+
+// FormatVPL formats USFM documents as verse-per-line text: one line per verse with the
+// book code, chapter, and verse followed by the verse text.
+// Text is written without USFM character markup or footnotes and with whitespace collapsed,
+// so each verse stays on a single line. Verse bridges are written as a range.
+//
+// Example:
+//
+//	GEN 1:1 In the beginning God created the heavens and the earth.
+//	GEN 1:2 Now the earth was formless and void...
+func FormatVPL(documents []*usfm.Document) (string, error) {
+	var result strings.Builder
+	if err := WriteVPL(&result, documents); err != nil {
+		return "", err
+	}
+	return result.String(), nil
+}
+
+// WriteVPL writes USFM documents as verse-per-line text to w, as described for FormatVPL.
+// The output is written one chapter at a time.
+func WriteVPL(w io.Writer, documents []*usfm.Document) error {
+	var result strings.Builder
+
+	for _, doc := range documents {
+		book := doc.BookCode()
+		if book == "" {
+			book = "UNKNOWN"
+		}
+
+		for _, chapter := range doc.Chapters {
+			for _, section := range chapter.Sections {
+				for _, verse := range section.Verses {
+					fmt.Fprintf(&result, "%s %d:%d", book, chapter.Number, verse.Number)
+					if verse.EndNumber > 0 {
+						fmt.Fprintf(&result, "-%d", verse.EndNumber)
+					}
+					result.WriteString(" " + lineText(verse.Text) + "\n")
+				}
+			}
+			if err := flushOutput(w, &result); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// FormatCorpus formats translations as a verse-aligned parallel corpus for NLP and machine translation.
+//
+// The corpus has a vref.txt file with one verse reference per line ("GEN 1:1") for every verse
+// of the versification scheme, in canonical order, and one text file per translation named
+// after it (e.g., "BSB.txt") with the text of the verse on the same line number. Verses missing
+// from a translation are blank lines, verses joined into a verse bridge are written
+// on the line of the first verse with "<range>" on the lines of the others, and verses
+// outside the scheme are left out, so line N of every file describes the same verse.
+func FormatCorpus(translations []*usfm.Bible, scheme *versification.Scheme) ([]OutputFile, error) {
+	if len(translations) == 0 {
+		return nil, fmt.Errorf("no translations to write")
+	}
+
+	var references strings.Builder
+	texts := make([]strings.Builder, len(translations))
+	verses := make([]map[verseKey]string, len(translations))
+	for i, translation := range translations {
+		verses[i] = corpusVerses(translation.Documents)
+	}
+
+	for _, book := range scheme.Books() {
+		for chapter := 1; chapter <= scheme.Chapters(book); chapter++ {
+			for verse := 1; verse <= scheme.Verses(book, chapter); verse++ {
+				key := verseKey{book, chapter, verse}
+				fmt.Fprintf(&references, "%s %d:%d\n", book, chapter, verse)
+				for i := range translations {
+					texts[i].WriteString(verses[i][key] + "\n")
+				}
+			}
+		}
+	}
+
+	files := []OutputFile{{Name: "vref.txt", Data: []byte(references.String())}}
+	used := map[string]bool{"vref": true}
+	for i, translation := range translations {
+		base := strings.NewReplacer("/", "-", "\\", "-", ":", "-").Replace(translation.Name)
+		if base == "" {
+			base = fmt.Sprintf("translation-%d", i+1)
+		}
+		name := base
+		for n := 2; used[name]; n++ {
+			name = fmt.Sprintf("%s-%d", base, n)
+		}
+		used[name] = true
+		files = append(files, OutputFile{Name: name + ".txt", Data: []byte(texts[i].String())})
+	}
+
+	return files, nil
+}
+
+// verseKey identifies a verse by book code, chapter, and verse number
+type verseKey struct {
+	book    string
+	chapter int
+	verse   int
+}
+
+// corpusVerses maps each verse of a translation to its single-line text,
+// marking the later verses of a verse bridge with corpusRange
+func corpusVerses(documents []*usfm.Document) map[verseKey]string {
+	verses := make(map[verseKey]string)
+
+	for _, doc := range documents {
+		book := doc.BookCode()
+		for _, chapter := range doc.Chapters {
+			for _, section := range chapter.Sections {
+				for _, verse := range section.Verses {
+					verses[verseKey{book, chapter.Number, verse.Number}] = lineText(verse.Text)
+					for number := verse.Number + 1; number <= verse.EndNumber; number++ {
+						verses[verseKey{book, chapter.Number, number}] = corpusRange
+					}
+				}
+			}
+		}
+	}
+
+	return verses
+}
+
+// lineText returns verse text without character markup and with whitespace collapsed to single spaces
+func lineText(text string) string {
+	return strings.Join(strings.Fields(usfm.PlainText(text)), " ")
+}
@@ -0,0 +1,97 @@
+package formatter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/arenzana/usfmp/pkg/usfm"
+	"github.com/arenzana/usfmp/pkg/versification"
+)
+
+// TestFormatVPL tests verse-per-line output
+func TestFormatVPL(t *testing.T) {
+	doc := createTestDocument()
+	verses := doc.Chapters[0].Sections[0].Verses
+	verses[0].Text = "In the beginning \\add God\\add* created\nthe heavens."
+	verses[1].EndNumber = 3
+
+	result, err := FormatVPL([]*usfm.Document{doc})
+	if err != nil {
+		t.Fatalf("FormatVPL failed: %v", err)
+	}
+
+	expected := "GEN 1:1 In the beginning God created the heavens.\n" +
+		"GEN 1:2-3 Now the earth was formless and void.\n"
+	if result != expected {
+		t.Errorf("Expected:\n%q\ngot:\n%q", expected, result)
+	}
+}
+
+// TestFormatCorpus tests that corpus files align line by line with vref.txt
+func TestFormatCorpus(t *testing.T) {
+	first := createTestDocument()
+	first.Chapters[0].Sections[0].Verses[1].EndNumber = 3
+
+	// The second translation lacks verse 2 and has a verse outside the versification
+	second := createTestDocument()
+	second.Chapters[0].Sections[0].Verses[1].Number = 40
+
+	translations := []*usfm.Bible{
+		testBible(t, "first", first),
+		testBible(t, "second", second),
+		testBible(t, "first"),
+	}
+
+	files, err := FormatCorpus(translations, versification.English)
+	if err != nil {
+		t.Fatalf("FormatCorpus failed: %v", err)
+	}
+
+	names := make([]string, len(files))
+	contents := make(map[string][]string)
+	for i, file := range files {
+		names[i] = file.Name
+		contents[file.Name] = strings.Split(string(file.Data), "\n")
+	}
+	if strings.Join(names, ",") != "vref.txt,first.txt,second.txt,first-2.txt" {
+		t.Fatalf("Unexpected corpus files %v", names)
+	}
+
+	for name, lines := range contents {
+		if len(lines) != 31102+1 {
+			t.Errorf("%s: expected 31102 lines, got %d", name, len(lines)-1)
+		}
+	}
+
+	expected := map[string][]string{
+		"vref.txt":    {"GEN 1:1", "GEN 1:2", "GEN 1:3", "GEN 1:4"},
+		"first.txt":   {"In the beginning God created the heavens and the earth.", "Now the earth was formless and void.", "<range>", ""},
+		"second.txt":  {"In the beginning God created the heavens and the earth.", "", "", ""},
+		"first-2.txt": {"", "", "", ""},
+	}
+	for name, lines := range expected {
+		for i, line := range lines {
+			if contents[name][i] != line {
+				t.Errorf("%s line %d: expected %q, got %q", name, i+1, line, contents[name][i])
+			}
+		}
+	}
+
+	if last := contents["vref.txt"][31101]; last != "REV 22:21" {
+		t.Errorf("Expected last reference REV 22:21, got %q", last)
+	}
+
+	if _, err := FormatCorpus(nil, versification.English); err == nil {
+		t.Error("Expected error for an empty corpus")
+	}
+}
+
+// testBible builds a translation from test documents
+func testBible(t *testing.T, name string, documents ...*usfm.Document) *usfm.Bible {
+	t.Helper()
+	bible, err := usfm.NewBible(name, documents)
+	if err != nil {
+		t.Fatalf("NewBible failed: %v", err)
+	}
+	return bible
+}
@@ -0,0 +1,17 @@
+package formatter
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+// xmlHeader is the XML declaration written at the top of every XML-based output format.
+const xmlHeader = `<?xml version="1.0" encoding="UTF-8"?>` + "\n"
+
+// escapeXML escapes text for safe use in XML element content and attribute values.
+func escapeXML(text string) string {
+	var result strings.Builder
+	// xml.EscapeText only fails if the writer fails, which strings.Builder never does
+	_ = xml.EscapeText(&result, []byte(text))
+	return result.String()
+}
@@ -0,0 +1,103 @@
+package formatter
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/arenzana/usfmp/pkg/usfm"
+)
+
+// ZefaniaOptions configures the Zefania XML output.
+type ZefaniaOptions struct {
+	BibleName        string // Name of the Bible written to the biblename attribute and <title>
+	IncludeFootnotes bool   // Whether to write footnotes as <NOTE> elements
+}
+
+// FormatZefania formats USFM documents as a Zefania XML Bible, as imported by OpenLP
+// and other presentation software.
+//
+// Books are written in canonical order with their canonical book number (bnumber),
+// English name (bname), and OSIS short name (bsname). Documents whose \id code is not
+// a canonical book are skipped, and so are later copies of a book. Section titles are
+// written as <CAPTION> elements before the verse they introduce, and footnotes as <NOTE>
+// elements when enabled. Verse text is written without USFM character markup.
+//
+// Zefania verse numbers cannot be ranges, so the text of a verse bridge such as \v 1-2
+// is written as the first verse of the bridge and the other verses are left out.
+//
+// Example output:
+//
+//	<XMLBIBLE biblename="BSB" type="x-bible" status="v">
+//	  <BIBLEBOOK bnumber="1" bname="Genesis" bsname="Gen">
+//	    <CHAPTER cnumber="1">
+//	      <CAPTION vref="1">The Creation</CAPTION>
+//	      <VERS vnumber="1">In the beginning...</VERS>
+func FormatZefania(documents []*usfm.Document, options ZefaniaOptions) (string, error) {
+	var result strings.Builder
+	if err := WriteZefania(&result, documents, options); err != nil {
+		return "", err
+	}
+	return result.String(), nil
+}
+
+// WriteZefania writes USFM documents as a Zefania XML Bible to w, in the layout described
+// for FormatZefania. The output is written one chapter at a time.
+func WriteZefania(w io.Writer, documents []*usfm.Document, options ZefaniaOptions) error {
+	var result strings.Builder
+
+	bibleName := options.BibleName
+	if bibleName == "" {
+		bibleName = "Bible"
+	}
+
+	result.WriteString(xmlHeader)
+	fmt.Fprintf(&result, "<XMLBIBLE xmlns:xsi=\"http://www.w3.org/2001/XMLSchema-instance\""+
+		" xsi:noNamespaceSchemaLocation=\"zef2005.xsd\" biblename=\"%s\" type=\"x-bible\""+
+		" status=\"v\" version=\"2.0.1.18\" revision=\"1\">\n", escapeXML(bibleName))
+	result.WriteString("  <INFORMATION>\n")
+	fmt.Fprintf(&result, "    <title>%s</title>\n", escapeXML(bibleName))
+	result.WriteString("    <format>Zefania XML Bible Markup Language</format>\n")
+	result.WriteString("  </INFORMATION>\n")
+
+	for _, entry := range canonicalBooks(documents) {
+		fmt.Fprintf(&result, "  <BIBLEBOOK bnumber=\"%d\" bname=\"%s\" bsname=\"%s\">\n",
+			entry.Book.Number, escapeXML(entry.Book.Name), escapeXML(entry.Book.OSIS))
+
+		for _, chapter := range entry.Document.Chapters {
+			fmt.Fprintf(&result, "    <CHAPTER cnumber=\"%d\">\n", chapter.Number)
+
+			for _, section := range chapter.Sections {
+				if section.Title != "" && len(section.Verses) > 0 {
+					fmt.Fprintf(&result, "      <CAPTION vref=\"%d\">%s</CAPTION>\n",
+						section.Verses[0].Number, escapeXML(section.Title))
+				}
+
+				for _, verse := range section.Verses {
+					fmt.Fprintf(&result, "      <VERS vnumber=\"%d\">%s", verse.Number,
+						escapeXML(usfm.PlainText(verse.Text)))
+
+					if options.IncludeFootnotes {
+						for _, footnote := range verse.Footnotes {
+							fmt.Fprintf(&result, "<NOTE type=\"x-studynote\">%s</NOTE>",
+								escapeXML(usfm.PlainText(footnote.Text)))
+						}
+					}
+
+					result.WriteString("</VERS>\n")
+				}
+			}
+
+			result.WriteString("    </CHAPTER>\n")
+			if err := flushOutput(w, &result); err != nil {
+				return err
+			}
+		}
+
+		result.WriteString("  </BIBLEBOOK>\n")
+	}
+
+	result.WriteString("</XMLBIBLE>\n")
+
+	return flushOutput(w, &result)
+}
@@ -0,0 +1,117 @@
+package formatter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/arenzana/usfmp/pkg/usfm"
+)
+
+// TestFormatZefania tests Zefania XML formatting
+func TestFormatZefania(t *testing.T) {
+	doc := createTestDocument()
+	frontMatter := &usfm.Document{ID: "FRT Preface"}
+	exodus := createTestDocument()
+	exodus.ID = "EXO"
+
+	// Books should be written in canonical order regardless of input order
+	documents := []*usfm.Document{exodus, frontMatter, doc}
+
+	result, err := FormatZefania(documents, ZefaniaOptions{BibleName: "Test Bible", IncludeFootnotes: true})
+	if err != nil {
+		t.Fatalf("FormatZefania failed: %v", err)
+	}
+
+	expected := []string{
+		`biblename="Test Bible"`,
+		`<BIBLEBOOK bnumber="1" bname="Genesis" bsname="Gen">`,
+		`<CHAPTER cnumber="1">`,
+		`<CAPTION vref="1">The Creation</CAPTION>`,
+		`<VERS vnumber="1">In the beginning God created the heavens and the earth.<NOTE type="x-studynote">Hebrew: Elohim</NOTE></VERS>`,
+		`<BIBLEBOOK bnumber="2" bname="Exodus" bsname="Exod">`,
+	}
+	for _, exp := range expected {
+		if !strings.Contains(result, exp) {
+			t.Errorf("Zefania output should contain '%s'", exp)
+		}
+	}
+
+	if strings.Index(result, `bnumber="1"`) > strings.Index(result, `bnumber="2"`) {
+		t.Error("Books should be written in canonical order")
+	}
+
+	if strings.Contains(result, "FRT") || strings.Contains(result, "Preface") {
+		t.Error("Non-canonical books should be skipped")
+	}
+
+	// Footnotes are optional
+	result, err = FormatZefania(documents, ZefaniaOptions{})
+	if err != nil {
+		t.Fatalf("FormatZefania failed: %v", err)
+	}
+	if strings.Contains(result, "<NOTE") {
+		t.Error("Footnotes should not be written when disabled")
+	}
+}
+
+// TestFormatOpenSong tests OpenSong XML formatting
+func TestFormatOpenSong(t *testing.T) {
+	doc := createTestDocument()
+	doc.Chapters[0].Sections[0].Verses[1].Text = `Now the \w earth|strong="H0776"\w* was formless.`
+
+	result, err := FormatOpenSong([]*usfm.Document{doc})
+	if err != nil {
+		t.Fatalf("FormatOpenSong failed: %v", err)
+	}
+
+	expected := []string{
+		"<bible>",
+		`<b n="Genesis">`,
+		`<c n="1">`,
+		`<v n="1">In the beginning God created the heavens and the earth.</v>`,
+		`<v n="2">Now the earth was formless.</v>`,
+	}
+	for _, exp := range expected {
+		if !strings.Contains(result, exp) {
+			t.Errorf("OpenSong output should contain '%s'", exp)
+		}
+	}
+
+	if strings.Contains(result, "Hebrew: Elohim") {
+		t.Error("OpenSong output should not contain footnotes")
+	}
+}
+
+// TestFormatZefaniaOpenSongBooks tests that a book given twice is written once and that a
+// verse bridge is written as its first verse
+func TestFormatZefaniaOpenSongBooks(t *testing.T) {
+	doc := createTestDocument()
+	doc.Chapters[0].Sections[0].Verses[1].EndNumber = 3
+	copied := createTestDocument()
+	copied.Chapters[0].Sections[0].Verses[0].Text = "A second copy."
+
+	documents := []*usfm.Document{doc, copied}
+	zefania, err := FormatZefania(documents, ZefaniaOptions{})
+	if err != nil {
+		t.Fatalf("FormatZefania failed: %v", err)
+	}
+	openSong, err := FormatOpenSong(documents)
+	if err != nil {
+		t.Fatalf("FormatOpenSong failed: %v", err)
+	}
+
+	testCases := []struct {
+		name, result, book, bridge string
+	}{
+		{"Zefania", zefania, `<BIBLEBOOK bnumber="1"`, `<VERS vnumber="2">`},
+		{"OpenSong", openSong, `<b n="Genesis">`, `<v n="2">`},
+	}
+	for _, tc := range testCases {
+		if strings.Count(tc.result, tc.book) != 1 || strings.Contains(tc.result, "A second copy.") {
+			t.Errorf("%s: expected only the first copy of Genesis, got:\n%s", tc.name, tc.result)
+		}
+		if strings.Count(tc.result, tc.bridge) != 1 || strings.Contains(tc.result, `n="3"`) || strings.Contains(tc.result, `vnumber="3"`) {
+			t.Errorf("%s: expected the bridge 2-3 as verse 2, got:\n%s", tc.name, tc.result)
+		}
+	}
+}
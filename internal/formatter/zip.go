@@ -0,0 +1,76 @@
+package formatter
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"time"
+)
+
+// zipFile is a file to be written into a zip-based output format such as EPUB or DOCX
+type zipFile struct {
+	name   string // Path within the archive
+	data   string // File contents
+	method uint16 // Compression method (zip.Store or zip.Deflate)
+}
+
+// zipWriter writes the files of a zip-based output format to a writer in order, all with
+// the same modification time. The time is written in MS-DOS form only: a FileHeader.Modified
+// time would add an extended timestamp extra field to every entry, and EPUB forbids extra
+// fields in the header of its mimetype file.
+type zipWriter struct {
+	archive *zip.Writer
+	date    uint16 // MS-DOS modification date
+	clock   uint16 // MS-DOS modification time
+}
+
+// newZipWriter starts a zip archive on w with the given modification time
+func newZipWriter(w io.Writer, modified time.Time) *zipWriter {
+	date, clock := msDosTime(modified)
+	return &zipWriter{archive: zip.NewWriter(w), date: date, clock: clock}
+}
+
+// create starts a file in the archive and returns a writer for its contents,
+// which can be used until the next file is started
+func (z *zipWriter) create(name string, method uint16) (io.Writer, error) {
+	header := &zip.FileHeader{Name: name, Method: method, ModifiedDate: z.date, ModifiedTime: z.clock}
+	writer, err := z.archive.CreateHeader(header)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add %s: %w", name, err)
+	}
+	return writer, nil
+}
+
+// add writes files into the archive in order
+func (z *zipWriter) add(files ...zipFile) error {
+	for _, file := range files {
+		writer, err := z.create(file.name, file.method)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(writer, file.data); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file.name, err)
+		}
+	}
+	return nil
+}
+
+// close finishes the archive by writing its central directory
+func (z *zipWriter) close() error {
+	if err := z.archive.Close(); err != nil {
+		return fmt.Errorf("failed to finish archive: %w", err)
+	}
+	return nil
+}
+
+// msDosTime returns the MS-DOS date and time of t in UTC, which zip readers read as UTC
+// when an entry has no extended timestamp
+func msDosTime(t time.Time) (uint16, uint16) {
+	t = t.UTC()
+	if t.Year() < 1980 {
+		t = time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	date := uint16((t.Year()-1980)<<9 | int(t.Month())<<5 | t.Day())
+	clock := uint16(t.Hour()<<11 | t.Minute()<<5 | t.Second()/2)
+	return date, clock
+}
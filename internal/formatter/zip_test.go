@@ -0,0 +1,47 @@
+package formatter
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"path"
+	"testing"
+)
+
+// readZip returns the files of a zip-based output in order with their contents,
+// failing on malformed XML files
+func readZip(t *testing.T, data []byte) ([]*zip.File, map[string]string) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("Output is not a zip archive: %v", err)
+	}
+
+	contents := make(map[string]string)
+	for _, file := range archive.File {
+		reader, err := file.Open()
+		if err != nil {
+			t.Fatalf("Failed to open %s: %v", file.Name, err)
+		}
+		content, err := io.ReadAll(reader)
+		reader.Close()
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", file.Name, err)
+		}
+		contents[file.Name] = string(content)
+
+		switch path.Ext(file.Name) {
+		case ".xml", ".xhtml", ".opf", ".rels":
+			decoder := xml.NewDecoder(bytes.NewReader(content))
+			for {
+				if _, err := decoder.Token(); err == io.EOF {
+					break
+				} else if err != nil {
+					t.Fatalf("%s is not well-formed XML: %v", file.Name, err)
+				}
+			}
+		}
+	}
+
+	return archive.File, contents
+}
@@ -0,0 +1,148 @@
+package pdf
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Font is a font that can be used to draw text on a page.
+// Implementations are the standard Helvetica fonts and embedded TrueType fonts.
+type Font interface {
+	// Name returns the PostScript name of the font.
+	Name() string
+
+	// Width returns the advance width of text set at the given size, in points.
+	Width(text string, size float64) float64
+
+	// encode converts text to a PDF string operand for the Tj operator.
+	encode(text string) string
+
+	// write writes the font objects and returns the object number of the font dictionary.
+	write(out *objectWriter) (int, error)
+}
+
+// standardFont is one of the 14 standard PDF fonts, which viewers provide without embedding.
+// Text is encoded with WinAnsiEncoding; characters outside it are drawn as '?'.
+type standardFont struct {
+	name   string
+	widths []int // Glyph widths in 1/1000 em for character codes 32 to 126
+}
+
+// Standard Helvetica fonts that need no embedding. They cover Latin text
+// (WinAnsiEncoding); use LoadTrueType for other scripts.
+var (
+	Helvetica        Font = &standardFont{name: "Helvetica", widths: helveticaWidths}
+	HelveticaBold    Font = &standardFont{name: "Helvetica-Bold", widths: helveticaBoldWidths}
+	HelveticaOblique Font = &standardFont{name: "Helvetica-Oblique", widths: helveticaWidths}
+)
+
+// helveticaWidths holds the Helvetica glyph widths for character codes 32 to 126
+var helveticaWidths = []int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278, // space - /
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556, // 0 - ?
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778, // @ - O
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556, // P - _
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556, // ` - o
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584, // p - ~
+}
+
+// helveticaBoldWidths holds the Helvetica-Bold glyph widths for character codes 32 to 126
+var helveticaBoldWidths = []int{
+	278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278, // space - /
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611, // 0 - ?
+	975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778, // @ - O
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556, // P - _
+	333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611, // ` - o
+	611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584, // p - ~
+}
+
+// extendedWidths holds approximate widths for WinAnsi punctuation above code 126
+var extendedWidths = map[byte]int{
+	0x80: 556, 0x82: 222, 0x84: 333, 0x85: 1000, 0x86: 556, 0x87: 556, 0x89: 1000,
+	0x91: 222, 0x92: 222, 0x93: 333, 0x94: 333, 0x95: 350, 0x96: 556, 0x97: 1000, 0x99: 1000,
+	0xA0: 278, 0xA1: 333, 0xA7: 556, 0xA9: 737, 0xAB: 556, 0xAD: 333, 0xAE: 737, 0xB0: 400,
+	0xB6: 537, 0xB7: 278, 0xBB: 556, 0xBF: 611, 0xC6: 1000, 0xD7: 584, 0xE6: 889, 0xF7: 584,
+}
+
+// latinBase maps the accented letters from 0xC0 to 0xFF to their unaccented ASCII letter,
+// which has the same width in Helvetica
+const latinBase = "AAAAAA CEEEEIIIIDNOOOOO OUUUUYPsaaaaaa ceeeeiiiidnooooo ouuuuypy"
+
+// winAnsiSpecials maps characters encoded in the 0x80-0x9F range of WinAnsiEncoding
+var winAnsiSpecials = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87, 'ˆ': 0x88,
+	'‰': 0x89, 'Š': 0x8A, '‹': 0x8B, 'Œ': 0x8C, 'Ž': 0x8E, '‘': 0x91, '’': 0x92, '“': 0x93,
+	'”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '˜': 0x98, '™': 0x99, 'š': 0x9A, '›': 0x9B,
+	'œ': 0x9C, 'ž': 0x9E, 'Ÿ': 0x9F,
+}
+
+// winAnsiByte returns the WinAnsiEncoding code for a character, or '?' if it has none
+func winAnsiByte(r rune) byte {
+	switch {
+	case r >= 32 && r <= 126:
+		return byte(r)
+	case r >= 0xA0 && r <= 0xFF:
+		return byte(r)
+	}
+	if code, ok := winAnsiSpecials[r]; ok {
+		return code
+	}
+	return '?'
+}
+
+// Name returns the PostScript name of the font.
+func (f *standardFont) Name() string {
+	return f.name
+}
+
+// Width returns the advance width of text set at the given size, in points.
+func (f *standardFont) Width(text string, size float64) float64 {
+	total := 0
+	for _, r := range text {
+		total += f.codeWidth(winAnsiByte(r))
+	}
+	return float64(total) * size / 1000
+}
+
+// codeWidth returns the width of a WinAnsi character code in 1/1000 em
+func (f *standardFont) codeWidth(code byte) int {
+	switch {
+	case code >= 32 && code <= 126:
+		return f.widths[code-32]
+	case code >= 0xC0:
+		if base := latinBase[code-0xC0]; base != ' ' {
+			return f.codeWidth(base)
+		}
+	}
+	if width, ok := extendedWidths[code]; ok {
+		return width
+	}
+	return 556
+}
+
+// encode converts text to a WinAnsi literal string operand
+func (f *standardFont) encode(text string) string {
+	var result strings.Builder
+	result.WriteByte('(')
+	for _, r := range text {
+		code := winAnsiByte(r)
+		switch {
+		case code == '(' || code == ')' || code == '\\':
+			result.WriteByte('\\')
+			result.WriteByte(code)
+		case code >= 0x80:
+			fmt.Fprintf(&result, "\\%03o", code)
+		default:
+			result.WriteByte(code)
+		}
+	}
+	result.WriteByte(')')
+	return result.String()
+}
+
+// write writes the font dictionary; standard fonts need no embedded data
+func (f *standardFont) write(out *objectWriter) (int, error) {
+	ref := out.reserve()
+	out.object(ref, fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", f.name))
+	return ref, nil
+}
@@ -0,0 +1,232 @@
+// Package pdf implements a small PDF writer used by the usfmp PDF formatter.
+//
+// It supports the standard Helvetica fonts (WinAnsi encoding, no embedding required)
+// and embedded TrueType fonts for scripts outside the Latin range. Only the drawing
+// primitives needed to lay out text pages are provided: positioned text, fill colors,
+// and straight lines. Complex script shaping (ligatures, right-to-left reordering)
+// is not performed; glyphs are placed in the order of the input text.
+//
+// Basic usage:
+//
+//	doc := pdf.New(595.28, 841.89) // A4 in points
+//	page := doc.AddPage()
+//	page.Text(72, 770, pdf.Helvetica, 12, "In the beginning")
+//	if err := doc.Write(file); err != nil {
+//		return err
+//	}
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// Document is a PDF document under construction.
+// All pages share the same page size. Fonts are registered automatically
+// the first time they are used on a page.
+type Document struct {
+	width  float64 // Page width in points
+	height float64 // Page height in points
+	title  string  // Document title written to the info dictionary
+
+	fonts     []Font          // Fonts used by any page, in registration order
+	fontNames map[Font]string // Resource names (F1, F2, ...) of registered fonts
+	pages     []*Page         // Pages in output order
+}
+
+// Page is a single page of a Document. Drawing operations are appended
+// to the page content stream in the order they are called.
+type Page struct {
+	doc     *Document
+	content bytes.Buffer
+}
+
+// New creates an empty document with the given page size in points (1/72 inch).
+func New(width, height float64) *Document {
+	return &Document{
+		width:     width,
+		height:    height,
+		fontNames: make(map[Font]string),
+	}
+}
+
+// SetTitle sets the document title shown by PDF viewers.
+func (d *Document) SetTitle(title string) {
+	d.title = title
+}
+
+// Width returns the page width in points.
+func (d *Document) Width() float64 {
+	return d.width
+}
+
+// Height returns the page height in points.
+func (d *Document) Height() float64 {
+	return d.height
+}
+
+// PageCount returns the number of pages added so far.
+func (d *Document) PageCount() int {
+	return len(d.pages)
+}
+
+// AddPage appends a new blank page to the document and returns it.
+func (d *Document) AddPage() *Page {
+	page := &Page{doc: d}
+	d.pages = append(d.pages, page)
+	return page
+}
+
+// fontName returns the resource name of a font, registering it on first use
+func (d *Document) fontName(font Font) string {
+	if name, ok := d.fontNames[font]; ok {
+		return name
+	}
+	name := fmt.Sprintf("F%d", len(d.fonts)+1)
+	d.fonts = append(d.fonts, font)
+	d.fontNames[font] = name
+	return name
+}
+
+// Text draws text with its baseline starting at (x, y), measured in points
+// from the bottom-left corner of the page.
+func (p *Page) Text(x, y float64, font Font, size float64, text string) {
+	if text == "" {
+		return
+	}
+	fmt.Fprintf(&p.content, "BT /%s %s Tf %s %s Td %s Tj ET\n",
+		p.doc.fontName(font), formatNumber(size), formatNumber(x), formatNumber(y), font.encode(text))
+}
+
+// SetFillColor sets the RGB color (components from 0 to 1) used for subsequent text.
+func (p *Page) SetFillColor(r, g, b float64) {
+	fmt.Fprintf(&p.content, "%s %s %s rg\n", formatNumber(r), formatNumber(g), formatNumber(b))
+}
+
+// Line draws a straight black line from (x1, y1) to (x2, y2) with the given width.
+func (p *Page) Line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(&p.content, "%s w %s %s m %s %s l S\n",
+		formatNumber(width), formatNumber(x1), formatNumber(y1), formatNumber(x2), formatNumber(y2))
+}
+
+// Write serializes the document as PDF to w.
+func (d *Document) Write(w io.Writer) error {
+	out := newObjectWriter()
+
+	catalogRef := out.reserve()
+	pagesRef := out.reserve()
+	infoRef := out.reserve()
+
+	// Fonts are written first so that their glyph usage is complete
+	var fontEntries strings.Builder
+	for _, font := range d.fonts {
+		ref, err := font.write(out)
+		if err != nil {
+			return fmt.Errorf("failed to write font %s: %w", font.Name(), err)
+		}
+		fmt.Fprintf(&fontEntries, " /%s %d 0 R", d.fontNames[font], ref)
+	}
+	resources := fmt.Sprintf("<< /Font <<%s >> >>", fontEntries.String())
+
+	var kids []string
+	for _, page := range d.pages {
+		contentRef := out.reserve()
+		if err := out.stream(contentRef, "", page.content.Bytes()); err != nil {
+			return fmt.Errorf("failed to write page content: %w", err)
+		}
+
+		pageRef := out.reserve()
+		out.object(pageRef, fmt.Sprintf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %s %s] /Resources %s /Contents %d 0 R >>",
+			pagesRef, formatNumber(d.width), formatNumber(d.height), resources, contentRef))
+		kids = append(kids, fmt.Sprintf("%d 0 R", pageRef))
+	}
+
+	out.object(pagesRef, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(kids)))
+	out.object(catalogRef, fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pagesRef))
+	out.object(infoRef, fmt.Sprintf("<< /Title %s /Producer (usfmp) >>", textString(d.title)))
+
+	out.finish(catalogRef, infoRef)
+
+	_, err := w.Write(out.buf.Bytes())
+	return err
+}
+
+// objectWriter assembles numbered PDF objects and the cross-reference table
+type objectWriter struct {
+	buf     bytes.Buffer
+	offsets []int // Byte offset of each object, indexed by object number - 1
+}
+
+// newObjectWriter creates an object writer with the PDF header already written
+func newObjectWriter() *objectWriter {
+	w := &objectWriter{}
+	// The binary comment marks the file as containing 8-bit data
+	w.buf.WriteString("%PDF-1.7\n%\xE2\xE3\xCF\xD3\n")
+	return w
+}
+
+// reserve allocates a new object number
+func (w *objectWriter) reserve() int {
+	w.offsets = append(w.offsets, 0)
+	return len(w.offsets)
+}
+
+// object writes a non-stream object with a previously reserved number
+func (w *objectWriter) object(ref int, body string) {
+	w.offsets[ref-1] = w.buf.Len()
+	fmt.Fprintf(&w.buf, "%d 0 obj\n%s\nendobj\n", ref, body)
+}
+
+// stream writes a Flate-compressed stream object. The extra string holds
+// additional dictionary entries such as "/Length1 1234".
+func (w *objectWriter) stream(ref int, extra string, data []byte) error {
+	var compressed bytes.Buffer
+	zw := zlib.NewWriter(&compressed)
+	if _, err := zw.Write(data); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+
+	w.offsets[ref-1] = w.buf.Len()
+	fmt.Fprintf(&w.buf, "%d 0 obj\n<< /Length %d /Filter /FlateDecode%s >>\nstream\n", ref, compressed.Len(), extra)
+	w.buf.Write(compressed.Bytes())
+	w.buf.WriteString("\nendstream\nendobj\n")
+	return nil
+}
+
+// finish writes the cross-reference table and trailer
+func (w *objectWriter) finish(rootRef, infoRef int) {
+	xrefOffset := w.buf.Len()
+	fmt.Fprintf(&w.buf, "xref\n0 %d\n", len(w.offsets)+1)
+	w.buf.WriteString("0000000000 65535 f \n")
+	for _, offset := range w.offsets {
+		fmt.Fprintf(&w.buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&w.buf, "trailer\n<< /Size %d /Root %d 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n",
+		len(w.offsets)+1, rootRef, infoRef, xrefOffset)
+}
+
+// formatNumber formats a number for PDF content with at most two decimals
+func formatNumber(value float64) string {
+	s := strconv.FormatFloat(value, 'f', 2, 64)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+// textString encodes a string for the document information dictionary as UTF-16BE
+func textString(text string) string {
+	var result strings.Builder
+	result.WriteString("<FEFF")
+	for _, unit := range utf16.Encode([]rune(text)) {
+		fmt.Fprintf(&result, "%04X", unit)
+	}
+	result.WriteString(">")
+	return result.String()
+}
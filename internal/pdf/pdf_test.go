@@ -0,0 +1,158 @@
+package pdf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// buildTestFont builds a minimal TrueType font that maps 'A' to 'C' to glyphs 1 to 3,
+// with 2048 units per em and every glyph 1024 units wide
+func buildTestFont() []byte {
+	u16 := func(values ...int) []byte {
+		var b []byte
+		for _, v := range values {
+			b = binary.BigEndian.AppendUint16(b, uint16(v))
+		}
+		return b
+	}
+
+	head := make([]byte, 54)
+	binary.BigEndian.PutUint16(head[18:], 2048)
+	copy(head[36:], u16(0, -400, 2000, 1800))
+
+	hhea := make([]byte, 36)
+	copy(hhea[4:], u16(1600, -400))
+	binary.BigEndian.PutUint16(hhea[34:], 4)
+
+	maxp := u16(0, 0x5000, 4)
+	hmtx := u16(1024, 0, 1024, 0, 1024, 0, 1024, 0)
+
+	// cmap with a single format 4 subtable: 'A'-'C' -> 1-3, plus the 0xFFFF terminator
+	subtable := u16(4, 32, 0, 4, 4, 1, 0)
+	subtable = append(subtable, u16('C', 0xFFFF)...) // endCode
+	subtable = append(subtable, u16(0)...)           // reservedPad
+	subtable = append(subtable, u16('A', 0xFFFF)...) // startCode
+	subtable = append(subtable, u16(1-'A', 1)...)    // idDelta
+	subtable = append(subtable, u16(0, 0)...)        // idRangeOffset
+	cmap := append(u16(0, 1, 3, 1), binary.BigEndian.AppendUint32(nil, 12)...)
+	cmap = append(cmap, subtable...)
+
+	tables := []struct {
+		tag  string
+		data []byte
+	}{
+		{"cmap", cmap}, {"glyf", []byte{0, 0, 0, 0}}, {"head", head},
+		{"hhea", hhea}, {"hmtx", hmtx}, {"maxp", maxp},
+	}
+
+	// sfnt version 1.0, table count, and unused binary search fields
+	font := u16(1, 0, len(tables), 0, 0, 0)
+	offset := 12 + 16*len(tables)
+	var data []byte
+	for _, table := range tables {
+		font = append(font, table.tag...)
+		font = binary.BigEndian.AppendUint32(font, 0)
+		font = binary.BigEndian.AppendUint32(font, uint32(offset+len(data)))
+		font = binary.BigEndian.AppendUint32(font, uint32(len(table.data)))
+		data = append(data, table.data...)
+	}
+	return append(font, data...)
+}
+
+// TestDocumentWrite tests that a document is written with a valid structure
+func TestDocumentWrite(t *testing.T) {
+	doc := New(612, 792)
+	doc.SetTitle("Genesis")
+	page := doc.AddPage()
+	page.Text(72, 720, Helvetica, 12, "In the beginning (God)")
+	page.Line(72, 700, 200, 700, 0.5)
+	doc.AddPage().Text(72, 720, HelveticaBold, 12, "Chapter 2")
+
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	output := buf.String()
+
+	if !strings.HasPrefix(output, "%PDF-1.7") {
+		t.Error("Output should start with the PDF header")
+	}
+	if !strings.HasSuffix(output, "%%EOF\n") {
+		t.Error("Output should end with the EOF marker")
+	}
+	if !strings.Contains(output, "/Count 2") {
+		t.Error("Page tree should contain 2 pages")
+	}
+	if !strings.Contains(output, "/BaseFont /Helvetica ") || !strings.Contains(output, "/BaseFont /Helvetica-Bold ") {
+		t.Error("Both used fonts should be written")
+	}
+
+	// Every cross-reference entry must point at its object
+	xref := strings.LastIndex(output, "\nxref\n") + 1
+	entries := strings.Split(output[xref:], "\n")[3:]
+	for i := 1; ; i++ {
+		entry := entries[i-1]
+		if !strings.HasSuffix(entry, " n ") {
+			break
+		}
+		offset, err := strconv.Atoi(entry[:10])
+		if err != nil {
+			t.Fatalf("Invalid cross-reference entry %q", entry)
+		}
+		if !strings.HasPrefix(output[offset:], fmt.Sprintf("%d 0 obj", i)) {
+			t.Errorf("Cross-reference entry %d does not point at its object", i)
+		}
+	}
+}
+
+// TestStandardFont tests WinAnsi encoding and width measurement of standard fonts
+func TestStandardFont(t *testing.T) {
+	if width := Helvetica.Width("AB", 10); width != 13.34 {
+		t.Errorf("Expected width 13.34, got %g", width)
+	}
+
+	encoded := Helvetica.encode(`a(b)\c “d” – ✓`)
+	expected := `(a\(b\)\\c \223d\224 \226 ?)`
+	if encoded != expected {
+		t.Errorf("Expected encoding %s, got %s", expected, encoded)
+	}
+}
+
+// TestLoadTrueType tests parsing and embedding a TrueType font
+func TestLoadTrueType(t *testing.T) {
+	font, err := LoadTrueType(buildTestFont())
+	if err != nil {
+		t.Fatalf("LoadTrueType failed: %v", err)
+	}
+
+	// Each glyph is half an em wide
+	if width := font.Width("ABC", 10); width != 15 {
+		t.Errorf("Expected width 15, got %g", width)
+	}
+
+	if encoded := font.encode("CAZ"); encoded != "<000300010000>" {
+		t.Errorf("Expected glyph IDs <000300010000>, got %s", encoded)
+	}
+
+	doc := New(612, 792)
+	doc.AddPage().Text(72, 720, font, 12, "ABC")
+	var buf bytes.Buffer
+	if err := doc.Write(&buf); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	output := buf.String()
+
+	for _, expected := range []string{"/Subtype /Type0", "/Encoding /Identity-H", "/CIDFontType2", "/FontFile2", "/ToUnicode", "/W [1 [500] 2 [500] 3 [500]]"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Embedded font output should contain '%s'", expected)
+		}
+	}
+
+	if _, err := LoadTrueType([]byte("not a font")); err == nil {
+		t.Error("Expected error for invalid font data")
+	}
+}
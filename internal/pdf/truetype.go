@@ -0,0 +1,413 @@
+package pdf
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"unicode/utf16"
+)
+
+// trueTypeFont is an embedded TrueType font. Text is encoded as two-byte glyph IDs
+// (Identity-H encoding), so any character covered by the font's cmap can be drawn.
+// The complete font file is embedded; glyph widths and a ToUnicode map are written
+// for the glyphs actually used so that text can be extracted from the PDF.
+type trueTypeFont struct {
+	name       string          // PostScript name of the font
+	data       []byte          // Raw font file
+	unitsPerEm float64         // Design units per em
+	ascent     int             // Typographic ascent in design units
+	descent    int             // Typographic descent in design units (negative)
+	bbox       [4]int          // Font bounding box in design units
+	advances   []uint16        // Advance width of each glyph in design units
+	cmap       map[rune]uint16 // Character to glyph ID mapping
+	used       map[uint16]rune // Glyphs drawn so far and the character they represent
+}
+
+// LoadTrueTypeFile reads and parses a TrueType (.ttf) font file for embedding.
+func LoadTrueTypeFile(path string) (Font, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read font file: %w", err)
+	}
+	return LoadTrueType(data)
+}
+
+// LoadTrueType parses TrueType font data for embedding.
+// Only fonts with TrueType outlines (a glyf table) are supported;
+// CFF-based OpenType fonts and font collections return an error.
+func LoadTrueType(data []byte) (Font, error) {
+	if len(data) < 12 {
+		return nil, fmt.Errorf("font data too short")
+	}
+	if string(data[:4]) == "ttcf" {
+		return nil, fmt.Errorf("font collections (.ttc) are not supported")
+	}
+
+	tables, err := readTableDirectory(data)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := tables["glyf"]; !ok {
+		return nil, fmt.Errorf("font has no TrueType outlines (glyf table); CFF fonts are not supported")
+	}
+	for _, tag := range []string{"head", "hhea", "maxp", "hmtx", "cmap"} {
+		if _, ok := tables[tag]; !ok {
+			return nil, fmt.Errorf("font is missing required %q table", tag)
+		}
+	}
+
+	font := &trueTypeFont{
+		name: "EmbeddedFont",
+		data: data,
+		used: make(map[uint16]rune),
+	}
+
+	head := tables["head"]
+	if len(head) < 54 {
+		return nil, fmt.Errorf("invalid head table")
+	}
+	font.unitsPerEm = float64(binary.BigEndian.Uint16(head[18:]))
+	if font.unitsPerEm == 0 {
+		return nil, fmt.Errorf("invalid unitsPerEm in head table")
+	}
+	for i := 0; i < 4; i++ {
+		font.bbox[i] = int(int16(binary.BigEndian.Uint16(head[36+2*i:])))
+	}
+
+	hhea := tables["hhea"]
+	if len(hhea) < 36 {
+		return nil, fmt.Errorf("invalid hhea table")
+	}
+	font.ascent = int(int16(binary.BigEndian.Uint16(hhea[4:])))
+	font.descent = int(int16(binary.BigEndian.Uint16(hhea[6:])))
+	numberOfHMetrics := int(binary.BigEndian.Uint16(hhea[34:]))
+
+	maxp := tables["maxp"]
+	if len(maxp) < 6 {
+		return nil, fmt.Errorf("invalid maxp table")
+	}
+	numGlyphs := int(binary.BigEndian.Uint16(maxp[4:]))
+
+	font.advances, err = readAdvances(tables["hmtx"], numberOfHMetrics, numGlyphs)
+	if err != nil {
+		return nil, err
+	}
+
+	font.cmap, err = readCmap(tables["cmap"])
+	if err != nil {
+		return nil, err
+	}
+
+	if name := readPostScriptName(tables["name"]); name != "" {
+		font.name = name
+	}
+
+	return font, nil
+}
+
+// readTableDirectory returns the font tables keyed by their four-character tag
+func readTableDirectory(data []byte) (map[string][]byte, error) {
+	numTables := int(binary.BigEndian.Uint16(data[4:]))
+	if len(data) < 12+16*numTables {
+		return nil, fmt.Errorf("truncated table directory")
+	}
+
+	tables := make(map[string][]byte, numTables)
+	for i := 0; i < numTables; i++ {
+		record := data[12+16*i:]
+		tag := string(record[:4])
+		offset := int(binary.BigEndian.Uint32(record[8:]))
+		length := int(binary.BigEndian.Uint32(record[12:]))
+		if offset < 0 || length < 0 || offset+length > len(data) {
+			return nil, fmt.Errorf("table %q extends beyond end of font data", tag)
+		}
+		tables[tag] = data[offset : offset+length]
+	}
+	return tables, nil
+}
+
+// readAdvances reads glyph advance widths from the hmtx table.
+// Glyphs beyond numberOfHMetrics share the last advance width.
+func readAdvances(hmtx []byte, numberOfHMetrics, numGlyphs int) ([]uint16, error) {
+	if numberOfHMetrics == 0 || len(hmtx) < 4*numberOfHMetrics {
+		return nil, fmt.Errorf("invalid hmtx table")
+	}
+
+	advances := make([]uint16, numGlyphs)
+	for i := range advances {
+		if i < numberOfHMetrics {
+			advances[i] = binary.BigEndian.Uint16(hmtx[4*i:])
+		} else {
+			advances[i] = advances[numberOfHMetrics-1]
+		}
+	}
+	return advances, nil
+}
+
+// readCmap reads the best available Unicode subtable of the cmap table.
+// Format 12 (full Unicode) is preferred over format 4 (Basic Multilingual Plane).
+func readCmap(cmap []byte) (map[rune]uint16, error) {
+	if len(cmap) < 4 {
+		return nil, fmt.Errorf("invalid cmap table")
+	}
+
+	var format4, format12 []byte
+	numTables := int(binary.BigEndian.Uint16(cmap[2:]))
+	for i := 0; i < numTables && 4+8*i+8 <= len(cmap); i++ {
+		record := cmap[4+8*i:]
+		platformID := binary.BigEndian.Uint16(record)
+		encodingID := binary.BigEndian.Uint16(record[2:])
+		offset := int(binary.BigEndian.Uint32(record[4:]))
+		if offset+2 > len(cmap) {
+			continue
+		}
+
+		// Only Unicode subtables: platform 0 (Unicode) and platform 3 (Windows) BMP or full
+		unicode := platformID == 0 || (platformID == 3 && (encodingID == 1 || encodingID == 10))
+		if !unicode {
+			continue
+		}
+
+		switch binary.BigEndian.Uint16(cmap[offset:]) {
+		case 4:
+			format4 = cmap[offset:]
+		case 12:
+			format12 = cmap[offset:]
+		}
+	}
+
+	switch {
+	case format12 != nil:
+		return readCmapFormat12(format12)
+	case format4 != nil:
+		return readCmapFormat4(format4)
+	default:
+		return nil, fmt.Errorf("font has no supported Unicode cmap subtable")
+	}
+}
+
+// readCmapFormat4 reads a segment mapping (format 4) cmap subtable
+func readCmapFormat4(table []byte) (map[rune]uint16, error) {
+	if len(table) < 14 {
+		return nil, fmt.Errorf("invalid cmap format 4 subtable")
+	}
+	segCount := int(binary.BigEndian.Uint16(table[6:])) / 2
+	endCodes := 14
+	startCodes := endCodes + 2*segCount + 2
+	idDeltas := startCodes + 2*segCount
+	idRangeOffsets := idDeltas + 2*segCount
+	if len(table) < idRangeOffsets+2*segCount {
+		return nil, fmt.Errorf("truncated cmap format 4 subtable")
+	}
+
+	result := make(map[rune]uint16)
+	for i := 0; i < segCount; i++ {
+		end := int(binary.BigEndian.Uint16(table[endCodes+2*i:]))
+		start := int(binary.BigEndian.Uint16(table[startCodes+2*i:]))
+		delta := binary.BigEndian.Uint16(table[idDeltas+2*i:])
+		rangeOffsetPos := idRangeOffsets + 2*i
+		rangeOffset := int(binary.BigEndian.Uint16(table[rangeOffsetPos:]))
+
+		for code := start; code <= end && code != 0xFFFF; code++ {
+			var glyph uint16
+			if rangeOffset == 0 {
+				glyph = uint16(code) + delta
+			} else {
+				pos := rangeOffsetPos + rangeOffset + 2*(code-start)
+				if pos+2 > len(table) {
+					continue
+				}
+				glyph = binary.BigEndian.Uint16(table[pos:])
+				if glyph != 0 {
+					glyph += delta
+				}
+			}
+			if glyph != 0 {
+				result[rune(code)] = glyph
+			}
+		}
+	}
+	return result, nil
+}
+
+// readCmapFormat12 reads a segmented coverage (format 12) cmap subtable
+func readCmapFormat12(table []byte) (map[rune]uint16, error) {
+	if len(table) < 16 {
+		return nil, fmt.Errorf("invalid cmap format 12 subtable")
+	}
+	numGroups := int(binary.BigEndian.Uint32(table[12:]))
+	if len(table) < 16+12*numGroups {
+		return nil, fmt.Errorf("truncated cmap format 12 subtable")
+	}
+
+	result := make(map[rune]uint16)
+	for i := 0; i < numGroups; i++ {
+		group := table[16+12*i:]
+		start := binary.BigEndian.Uint32(group)
+		end := binary.BigEndian.Uint32(group[4:])
+		glyph := binary.BigEndian.Uint32(group[8:])
+		for code := start; code <= end && code <= 0x10FFFF; code++ {
+			result[rune(code)] = uint16(glyph + code - start)
+		}
+	}
+	return result, nil
+}
+
+// readPostScriptName returns the PostScript name (name ID 6) from the name table,
+// stripped of characters that are not allowed in PDF names
+func readPostScriptName(table []byte) string {
+	if len(table) < 6 {
+		return ""
+	}
+	count := int(binary.BigEndian.Uint16(table[2:]))
+	storage := int(binary.BigEndian.Uint16(table[4:]))
+
+	for i := 0; i < count && 6+12*i+12 <= len(table); i++ {
+		record := table[6+12*i:]
+		platformID := binary.BigEndian.Uint16(record)
+		nameID := binary.BigEndian.Uint16(record[6:])
+		length := int(binary.BigEndian.Uint16(record[8:]))
+		offset := storage + int(binary.BigEndian.Uint16(record[10:]))
+		if nameID != 6 || offset+length > len(table) {
+			continue
+		}
+
+		raw := table[offset : offset+length]
+		var name string
+		if platformID == 0 || platformID == 3 {
+			units := make([]uint16, len(raw)/2)
+			for j := range units {
+				units[j] = binary.BigEndian.Uint16(raw[2*j:])
+			}
+			name = string(utf16.Decode(units))
+		} else {
+			name = string(raw)
+		}
+
+		name = strings.Map(func(r rune) rune {
+			if r <= 32 || r > 126 || strings.ContainsRune("()<>[]{}/%#", r) {
+				return -1
+			}
+			return r
+		}, name)
+		if name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// Name returns the PostScript name of the font.
+func (f *trueTypeFont) Name() string {
+	return f.name
+}
+
+// Width returns the advance width of text set at the given size, in points.
+func (f *trueTypeFont) Width(text string, size float64) float64 {
+	total := 0.0
+	for _, r := range text {
+		total += float64(f.advance(f.cmap[r]))
+	}
+	return total * size / f.unitsPerEm
+}
+
+// advance returns the advance width of a glyph in design units
+func (f *trueTypeFont) advance(glyph uint16) uint16 {
+	if int(glyph) < len(f.advances) {
+		return f.advances[glyph]
+	}
+	return 0
+}
+
+// scale converts design units to PDF glyph space (1/1000 em)
+func (f *trueTypeFont) scale(value int) int {
+	return int(float64(value) * 1000 / f.unitsPerEm)
+}
+
+// encode converts text to a hex string of two-byte glyph IDs and records glyph usage
+func (f *trueTypeFont) encode(text string) string {
+	var result strings.Builder
+	result.WriteByte('<')
+	for _, r := range text {
+		glyph := f.cmap[r]
+		if glyph != 0 {
+			f.used[glyph] = r
+		}
+		fmt.Fprintf(&result, "%04X", glyph)
+	}
+	result.WriteByte('>')
+	return result.String()
+}
+
+// write embeds the font file and writes the Type0 font with its descendant CIDFont
+func (f *trueTypeFont) write(out *objectWriter) (int, error) {
+	fileRef := out.reserve()
+	if err := out.stream(fileRef, fmt.Sprintf(" /Length1 %d", len(f.data)), f.data); err != nil {
+		return 0, err
+	}
+
+	descriptorRef := out.reserve()
+	out.object(descriptorRef, fmt.Sprintf("<< /Type /FontDescriptor /FontName /%s /Flags 32"+
+		" /FontBBox [%d %d %d %d] /ItalicAngle 0 /Ascent %d /Descent %d /CapHeight %d /StemV 80 /FontFile2 %d 0 R >>",
+		f.name, f.scale(f.bbox[0]), f.scale(f.bbox[1]), f.scale(f.bbox[2]), f.scale(f.bbox[3]),
+		f.scale(f.ascent), f.scale(f.descent), f.scale(f.ascent), fileRef))
+
+	glyphs := make([]int, 0, len(f.used))
+	for glyph := range f.used {
+		glyphs = append(glyphs, int(glyph))
+	}
+	sort.Ints(glyphs)
+
+	var widths strings.Builder
+	for _, glyph := range glyphs {
+		fmt.Fprintf(&widths, "%d [%d] ", glyph, f.scale(int(f.advance(uint16(glyph)))))
+	}
+
+	cidFontRef := out.reserve()
+	out.object(cidFontRef, fmt.Sprintf("<< /Type /Font /Subtype /CIDFontType2 /BaseFont /%s"+
+		" /CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >>"+
+		" /FontDescriptor %d 0 R /DW %d /W [%s] /CIDToGIDMap /Identity >>",
+		f.name, descriptorRef, f.scale(int(f.advance(0))), strings.TrimSpace(widths.String())))
+
+	toUnicodeRef := out.reserve()
+	if err := out.stream(toUnicodeRef, "", []byte(f.toUnicode(glyphs))); err != nil {
+		return 0, err
+	}
+
+	fontRef := out.reserve()
+	out.object(fontRef, fmt.Sprintf("<< /Type /Font /Subtype /Type0 /BaseFont /%s /Encoding /Identity-H"+
+		" /DescendantFonts [%d 0 R] /ToUnicode %d 0 R >>", f.name, cidFontRef, toUnicodeRef))
+
+	return fontRef, nil
+}
+
+// toUnicode builds the ToUnicode CMap that maps used glyph IDs back to Unicode text
+func (f *trueTypeFont) toUnicode(glyphs []int) string {
+	var result strings.Builder
+	result.WriteString("/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n" +
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n" +
+		"/CMapName /Adobe-Identity-UCS def\n/CMapType 2 def\n" +
+		"1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+
+	// bfchar blocks are limited to 100 entries each
+	for start := 0; start < len(glyphs); start += 100 {
+		end := start + 100
+		if end > len(glyphs) {
+			end = len(glyphs)
+		}
+		fmt.Fprintf(&result, "%d beginbfchar\n", end-start)
+		for _, glyph := range glyphs[start:end] {
+			fmt.Fprintf(&result, "<%04X> <", glyph)
+			for _, unit := range utf16.Encode([]rune{f.used[uint16(glyph)]}) {
+				fmt.Fprintf(&result, "%04X", unit)
+			}
+			result.WriteString(">\n")
+		}
+		result.WriteString("endbfchar\n")
+	}
+
+	result.WriteString("endcmap\nCMapName currentdict /CMap defineresource pop\nend\nend\n")
+	return result.String()
+}
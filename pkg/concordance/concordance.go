@@ -0,0 +1,234 @@
+// Package concordance indexes the words of a translation by the Strong's numbers they are
+// tagged with, such as the strong attribute of \w God|strong="H0430"\w*.
+//
+// A Concordance answers where a Strong's number occurs, which words render it and how
+// often, and which Strong's numbers a word renders.
+//
+// Example:
+//
+//	bible, err := usfm.LoadBible("samples/eng-kjv_usfm", usfm.DefaultParseOptions())
+//	if err != nil {
+//		return err
+//	}
+//	c := concordance.Build(bible)
+//	renderings, err := c.Renderings("H430")
+//	// God 2367, gods 216, God’s 7, judges 4, ...
+package concordance
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/arenzana/usfmp/pkg/ref"
+	"github.com/arenzana/usfmp/pkg/search"
+	"github.com/arenzana/usfmp/pkg/usfm"
+)
+
+// Occurrence is a word of verse text tagged with a Strong's number.
+type Occurrence struct {
+	Translation string    `json:"translation"` // Name of the translation (see usfm.Bible)
+	Range       ref.Range `json:"reference"`   // Verse, or first and last verse of a verse bridge
+	Word        string    `json:"word"`        // Word as written in the text (e.g., "God")
+	Strong      string    `json:"strong"`      // Strong's number in canonical form (e.g., "H0430")
+}
+
+// Rendering counts how often a word is tagged with a Strong's number.
+type Rendering struct {
+	Strong string `json:"strong"` // Strong's number in canonical form
+	Word   string `json:"word"`   // Most frequent spelling of the word
+	Count  int    `json:"count"`  // Number of occurrences
+}
+
+// Concordance indexes tagged words by Strong's number and by word.
+type Concordance struct {
+	occurrences []Occurrence
+	byStrong    map[string][]int // Occurrences by Strong's number
+	byWord      map[string][]int // Occurrences by normalized word (see wordKey)
+}
+
+// strongPattern matches a Strong's number such as "H430", "h0430", or "G2316a"
+var strongPattern = regexp.MustCompile(`^([HGhg])0*(\d{1,5})([a-zA-Z]?)$`)
+
+// ParseStrong returns the canonical form of a Strong's number: an uppercase H (Hebrew)
+// or G (Greek) followed by at least four digits and an optional lowercase letter,
+// so "h430", "H430", and "H0430" all become "H0430".
+func ParseStrong(number string) (string, error) {
+	match := strongPattern.FindStringSubmatch(strings.TrimSpace(number))
+	if match == nil {
+		return "", fmt.Errorf("invalid Strong's number %q (expected H or G followed by digits, e.g. H0430)", number)
+	}
+	n, _ := strconv.Atoi(match[2])
+	return fmt.Sprintf("%s%04d%s", strings.ToUpper(match[1]), n, strings.ToLower(match[3])), nil
+}
+
+// IsStrong reports whether text is a Strong's number.
+func IsStrong(text string) bool {
+	return strongPattern.MatchString(strings.TrimSpace(text))
+}
+
+// Build indexes the tagged words of translations.
+func Build(bibles ...*usfm.Bible) *Concordance {
+	c := New()
+	for _, bible := range bibles {
+		c.Add(bible.Name, bible.Documents...)
+	}
+	return c
+}
+
+// New returns an empty concordance.
+func New() *Concordance {
+	return &Concordance{
+		byStrong: make(map[string][]int),
+		byWord:   make(map[string][]int),
+	}
+}
+
+// Add indexes the tagged words of documents as part of the named translation.
+// A word tagged with several numbers (strong="H0853,H5921") is indexed under each, and
+// numbers that are not Strong's numbers are indexed as written.
+func (c *Concordance) Add(translation string, documents ...*usfm.Document) {
+	for _, doc := range documents {
+		book := doc.BookCode()
+		if book == "" {
+			continue
+		}
+
+		for _, chapter := range doc.Chapters {
+			for _, section := range chapter.Sections {
+				for _, verse := range section.Verses {
+					r := ref.Range{Start: ref.Reference{Book: book, Chapter: chapter.Number, Verse: verse.Number}}
+					if verse.EndNumber > 0 {
+						r.End = ref.Reference{Book: book, Chapter: chapter.Number, Verse: verse.EndNumber}
+					}
+
+					for _, span := range usfm.ParseInline(verse.Text) {
+						word := strings.Join(strings.Fields(span.Text), " ")
+						if span.Attributes["strong"] == "" || word == "" {
+							continue
+						}
+						for _, number := range strings.FieldsFunc(span.Attributes["strong"], isStrongSeparator) {
+							if strong, err := ParseStrong(number); err == nil {
+								number = strong
+							}
+							c.add(Occurrence{Translation: translation, Range: r, Word: word, Strong: number})
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// add indexes an occurrence
+func (c *Concordance) add(occurrence Occurrence) {
+	id := len(c.occurrences)
+	c.occurrences = append(c.occurrences, occurrence)
+	c.byStrong[occurrence.Strong] = append(c.byStrong[occurrence.Strong], id)
+	key := wordKey(occurrence.Word)
+	c.byWord[key] = append(c.byWord[key], id)
+}
+
+// isStrongSeparator reports whether a rune separates the numbers of a strong attribute
+func isStrongSeparator(r rune) bool {
+	return r == ',' || r == ' '
+}
+
+// wordKey normalizes a word for lookup, ignoring case, diacritics, and punctuation
+func wordKey(word string) string {
+	var terms []string
+	for _, token := range search.Tokenize(word) {
+		terms = append(terms, token.Term)
+	}
+	return strings.Join(terms, " ")
+}
+
+// Len returns the number of indexed occurrences.
+func (c *Concordance) Len() int {
+	return len(c.occurrences)
+}
+
+// Strong returns the occurrences of a Strong's number in the order they were indexed
+// (translation by translation, in canonical order).
+func (c *Concordance) Strong(number string) ([]Occurrence, error) {
+	strong, err := ParseStrong(number)
+	if err != nil {
+		return nil, err
+	}
+	return c.lookup(c.byStrong[strong]), nil
+}
+
+// Word returns the tagged occurrences of a word, ignoring case and diacritics.
+func (c *Concordance) Word(word string) []Occurrence {
+	return c.lookup(c.byWord[wordKey(word)])
+}
+
+// lookup returns the occurrences with the given ids
+func (c *Concordance) lookup(ids []int) []Occurrence {
+	occurrences := make([]Occurrence, len(ids))
+	for i, id := range ids {
+		occurrences[i] = c.occurrences[id]
+	}
+	return occurrences
+}
+
+// Renderings returns the words that render a Strong's number, most frequent first.
+// Spellings that differ only in case or diacritics ("God", "GOD") are counted together.
+func (c *Concordance) Renderings(number string) ([]Rendering, error) {
+	occurrences, err := c.Strong(number)
+	if err != nil {
+		return nil, err
+	}
+	return countRenderings(occurrences, func(o Occurrence) string { return wordKey(o.Word) }), nil
+}
+
+// Numbers returns the Strong's numbers that a word renders, most frequent first.
+func (c *Concordance) Numbers(word string) []Rendering {
+	return countRenderings(c.Word(word), func(o Occurrence) string { return o.Strong })
+}
+
+// countRenderings groups occurrences by key and counts them, naming each group after its
+// most frequent spelling and Strong's number
+func countRenderings(occurrences []Occurrence, key func(Occurrence) string) []Rendering {
+	type group struct {
+		count     int
+		spellings map[Rendering]int
+		first     int
+	}
+	groups := make(map[string]*group)
+
+	for i, occurrence := range occurrences {
+		k := key(occurrence)
+		g, ok := groups[k]
+		if !ok {
+			g = &group{spellings: make(map[Rendering]int), first: i}
+			groups[k] = g
+		}
+		g.count++
+		g.spellings[Rendering{Strong: occurrence.Strong, Word: occurrence.Word}]++
+	}
+
+	renderings := make([]Rendering, 0, len(groups))
+	firsts := make(map[Rendering]int, len(groups))
+	for _, g := range groups {
+		var best Rendering
+		bestCount := 0
+		for spelling, count := range g.spellings {
+			if count > bestCount || count == bestCount && spelling.Word < best.Word {
+				best, bestCount = spelling, count
+			}
+		}
+		best.Count = g.count
+		renderings = append(renderings, best)
+		firsts[best] = g.first
+	}
+
+	// Most frequent first; ties in the order they first occur
+	slices.SortFunc(renderings, func(a, b Rendering) int {
+		return cmp.Or(b.Count-a.Count, firsts[a]-firsts[b])
+	})
+	return renderings
+}
@@ -0,0 +1,113 @@
+package concordance
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/arenzana/usfmp/pkg/usfm"
+)
+
+// buildTestConcordance indexes a short tagged book
+func buildTestConcordance(t *testing.T) *Concordance {
+	t.Helper()
+
+	input := `\id GEN Genesis
+\c 1
+\v 1 In the \w beginning|strong="H7225"\w* \w God|strong="H0430"\w* \w created|strong="H1254"\w* the heaven.
+\v 2 And the \w Spirit|strong="H7307"\w* of \w God|strong="H430"\w* moved.
+\c 3
+\v 5 Ye shall be as \w gods|strong="h0430"\w*, \w knowing|strong="H3045"\w* good.
+\v 8 The \w LORD|strong="H3068"\w* \w God|strong="H3068,H0430"\w* walked; \w ÉLOHIM|strong="H0430"\w* saw.`
+
+	doc, err := usfm.NewParser(usfm.DefaultParseOptions()).Parse(strings.NewReader(input), "GEN.usfm")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	c := New()
+	c.Add("kjv", doc)
+	return c
+}
+
+// TestParseStrong tests normalizing Strong's numbers
+func TestParseStrong(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{"H0430", "H0430"},
+		{"h430", "H0430"},
+		{"G26", "G0026"},
+		{"G2316A", "G2316a"},
+		{"H00001", "H0001"},
+		{" H12345 ", "H12345"},
+	}
+
+	for _, tc := range testCases {
+		result, err := ParseStrong(tc.input)
+		if err != nil {
+			t.Errorf("ParseStrong(%q) failed: %v", tc.input, err)
+		} else if result != tc.expected {
+			t.Errorf("ParseStrong(%q): expected %s, got %s", tc.input, tc.expected, result)
+		}
+	}
+
+	for _, input := range []string{"", "God", "X0430", "H", "H12a3"} {
+		if _, err := ParseStrong(input); err == nil {
+			t.Errorf("ParseStrong(%q): expected an error", input)
+		}
+	}
+}
+
+// TestStrong tests listing the occurrences of a Strong's number
+func TestStrong(t *testing.T) {
+	c := buildTestConcordance(t)
+
+	occurrences, err := c.Strong("H430")
+	if err != nil {
+		t.Fatalf("Strong failed: %v", err)
+	}
+
+	var result []string
+	for _, o := range occurrences {
+		result = append(result, fmt.Sprintf("%s %s %s", o.Range, o.Word, o.Strong))
+	}
+	expected := "Genesis 1:1 God H0430; Genesis 1:2 God H0430; Genesis 3:5 gods H0430; " +
+		"Genesis 3:8 God H0430; Genesis 3:8 ÉLOHIM H0430"
+	if strings.Join(result, "; ") != expected {
+		t.Errorf("Expected %q, got %q", expected, strings.Join(result, "; "))
+	}
+	if occurrences[0].Translation != "kjv" {
+		t.Errorf("Expected translation kjv, got %q", occurrences[0].Translation)
+	}
+
+	if _, err := c.Strong("God"); err == nil {
+		t.Error("Expected an error for a word given as a Strong's number")
+	}
+	if occurrences, _ := c.Strong("G0026"); len(occurrences) != 0 {
+		t.Errorf("Expected no occurrences of G0026, got %d", len(occurrences))
+	}
+}
+
+// TestRenderings tests counting the words that render a number and the numbers a word renders
+func TestRenderings(t *testing.T) {
+	c := buildTestConcordance(t)
+
+	renderings, err := c.Renderings("H0430")
+	if err != nil {
+		t.Fatalf("Renderings failed: %v", err)
+	}
+	if result := fmt.Sprint(renderings); result != "[{H0430 God 3} {H0430 gods 1} {H0430 ÉLOHIM 1}]" {
+		t.Errorf("Unexpected renderings %s", result)
+	}
+
+	if result := fmt.Sprint(c.Numbers("god")); result != "[{H0430 God 3} {H3068 God 1}]" {
+		t.Errorf("Unexpected numbers %s", result)
+	}
+	if result := fmt.Sprint(c.Numbers("elohim")); result != "[{H0430 ÉLOHIM 1}]" {
+		t.Errorf("Expected words to match without diacritics, got %s", result)
+	}
+	if occurrences := c.Word("Lord"); len(occurrences) != 1 || occurrences[0].Strong != "H3068" {
+		t.Errorf("Unexpected occurrences of Lord %v", occurrences)
+	}
+}
@@ -0,0 +1,242 @@
+// Package diff compares two editions or translations of USFM books verse by verse.
+//
+// Books are aligned by book code, and verses, section headings, and footnotes by chapter
+// and verse number. Verse and footnote text is compared without markup, so a change of
+// \w attributes or character styles alone is not reported, and modified text comes with
+// a word-level diff.
+//
+// Example:
+//
+//	changes := diff.Compare(old.Documents, new.Documents)
+//	for _, change := range changes {
+//		fmt.Println(change) // ~ GEN 1:1 In the beginning [-God-]{+the LORD+} created the heavens and the earth.
+//	}
+package diff
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/arenzana/usfmp/pkg/ref"
+	"github.com/arenzana/usfmp/pkg/usfm"
+)
+
+// Kinds of changes
+const (
+	Added    = "added"    // Only the new edition has the item
+	Removed  = "removed"  // Only the old edition has the item
+	Modified = "modified" // The text of the item differs
+)
+
+// Items that are compared
+const (
+	Book     = "book"     // A whole book
+	Heading  = "heading"  // A section heading, located at the first verse of its section
+	Verse    = "verse"    // The text of a verse or verse bridge
+	Footnote = "footnote" // A footnote, located at the verse that holds it
+)
+
+// Change is an added, removed, or modified book, heading, verse, or footnote.
+type Change struct {
+	Kind      string    `json:"kind"`            // Added, Removed, or Modified
+	Item      string    `json:"item"`            // Book, Heading, Verse, or Footnote
+	Reference ref.Range `json:"reference"`       // Book, chapter, or verse of the item
+	Old       string    `json:"old,omitempty"`   // Text in the old edition, without markup
+	New       string    `json:"new,omitempty"`   // Text in the new edition, without markup
+	Words     []Edit    `json:"words,omitempty"` // Word-level diff of Old and New, for a modified item
+}
+
+// String formats the change as a line starting with "+" (added), "-" (removed), or "~" (modified),
+// followed by the reference, the item unless it is a verse, and the text, with removed words
+// marked as [-word-] and added words as {+word+}.
+func (c Change) String() string {
+	var result strings.Builder
+	switch c.Kind {
+	case Added:
+		result.WriteString("+ ")
+	case Removed:
+		result.WriteString("- ")
+	default:
+		result.WriteString("~ ")
+	}
+	result.WriteString(ref.Format([]ref.Range{c.Reference}, ref.USFM))
+	if c.Item != Verse {
+		result.WriteString(" " + c.Item)
+	}
+
+	text := c.New
+	switch {
+	case c.Kind == Removed:
+		text = c.Old
+	case len(c.Words) > 0:
+		text = Markup(c.Words)
+	}
+	if text != "" {
+		result.WriteString(" " + text)
+	}
+	return result.String()
+}
+
+// Compare returns the changes from the old documents to the new ones, in canonical order.
+// Documents without a book code are left out.
+func Compare(old, new []*usfm.Document) []Change {
+	oldBooks := booksByCode(old)
+	newBooks := booksByCode(new)
+
+	var codes []string
+	for code := range oldBooks {
+		codes = append(codes, code)
+	}
+	for code := range newBooks {
+		if _, ok := oldBooks[code]; !ok {
+			codes = append(codes, code)
+		}
+	}
+	slices.SortFunc(codes, func(a, b string) int {
+		return ref.Reference{Book: a}.Compare(ref.Reference{Book: b})
+	})
+
+	var changes []Change
+	for _, code := range codes {
+		oldDoc, inOld := oldBooks[code]
+		newDoc, inNew := newBooks[code]
+		book := ref.Range{Start: ref.Reference{Book: code}}
+		switch {
+		case !inNew:
+			changes = append(changes, Change{Kind: Removed, Item: Book, Reference: book, Old: bookTitle(oldDoc)})
+		case !inOld:
+			changes = append(changes, Change{Kind: Added, Item: Book, Reference: book, New: bookTitle(newDoc)})
+		default:
+			changes = append(changes, CompareBooks(oldDoc, newDoc)...)
+		}
+	}
+	return changes
+}
+
+// CompareBooks returns the changes from one edition of a book to another, in canonical order.
+func CompareBooks(old, new *usfm.Document) []Change {
+	book := new.BookCode()
+	oldItems := items(old)
+	newItems := items(new)
+
+	keys := make([]key, 0, len(newItems))
+	for k := range oldItems {
+		keys = append(keys, k)
+	}
+	for k := range newItems {
+		if _, ok := oldItems[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	slices.SortFunc(keys, func(a, b key) int {
+		return cmp.Or(
+			cmp.Compare(a.chapter, b.chapter),
+			cmp.Compare(a.verse, b.verse),
+			cmp.Compare(itemOrder[a.item], itemOrder[b.item]),
+			cmp.Compare(a.index, b.index),
+		)
+	})
+
+	var changes []Change
+	for _, k := range keys {
+		oldValue, inOld := oldItems[k]
+		newValue, inNew := newItems[k]
+		switch {
+		case !inNew:
+			changes = append(changes, Change{Kind: Removed, Item: k.item, Reference: k.reference(book, oldValue), Old: oldValue.text})
+		case !inOld:
+			changes = append(changes, Change{Kind: Added, Item: k.item, Reference: k.reference(book, newValue), New: newValue.text})
+		case oldValue.text != newValue.text:
+			changes = append(changes, Change{
+				Kind:      Modified,
+				Item:      k.item,
+				Reference: k.reference(book, newValue),
+				Old:       oldValue.text,
+				New:       newValue.text,
+				Words:     Words(oldValue.text, newValue.text),
+			})
+		}
+	}
+	return changes
+}
+
+// key locates a heading, verse, or footnote within a book
+type key struct {
+	item    string
+	chapter int
+	verse   int // First verse of a verse or bridge, or 0 for a heading before the first verse
+	index   int // Position among the headings or footnotes at the same verse
+}
+
+// value is the text of a heading, verse, or footnote
+type value struct {
+	text     string
+	endVerse int // Last verse of a verse bridge, or 0
+}
+
+// reference returns the chapter or verse of the item
+func (k key) reference(book string, v value) ref.Range {
+	r := ref.Range{Start: ref.Reference{Book: book, Chapter: k.chapter, Verse: k.verse}}
+	if v.endVerse > k.verse {
+		r.End = ref.Reference{Book: book, Chapter: k.chapter, Verse: v.endVerse}
+	}
+	return r
+}
+
+// items returns the headings, verses, and footnotes of a book by their location
+func items(doc *usfm.Document) map[key]value {
+	result := make(map[key]value)
+	for _, chapter := range doc.Chapters {
+		headings := make(map[int]int) // Number of headings at each verse
+		for _, section := range chapter.Sections {
+			anchor := 0
+			if len(section.Verses) > 0 {
+				anchor = section.Verses[0].Number
+			}
+			if title := strings.Join(strings.Fields(section.Title), " "); title != "" {
+				result[key{Heading, chapter.Number, anchor, headings[anchor]}] = value{text: title}
+				headings[anchor]++
+			}
+
+			for _, verse := range section.Verses {
+				verseKey := key{Verse, chapter.Number, verse.Number, 0}
+				if previous, ok := result[verseKey]; ok {
+					// A heading inside a verse splits it between two sections
+					result[verseKey] = value{text: previous.text + " " + usfm.PlainText(verse.Text), endVerse: previous.endVerse}
+				} else {
+					result[verseKey] = value{text: usfm.PlainText(verse.Text), endVerse: verse.EndNumber}
+				}
+
+				for i, footnote := range verse.Footnotes {
+					footnoteKey := key{Footnote, chapter.Number, verse.Number, i}
+					for _, ok := result[footnoteKey]; ok; _, ok = result[footnoteKey] {
+						// Footnotes of a verse split between two sections
+						footnoteKey.index++
+					}
+					result[footnoteKey] = value{text: usfm.PlainText(footnote.Text), endVerse: verse.EndNumber}
+				}
+			}
+		}
+	}
+	return result
+}
+
+// itemOrder orders the items at the same verse: a heading before the verse, and the verse before its footnotes
+var itemOrder = map[string]int{Heading: 0, Verse: 1, Footnote: 2}
+
+// booksByCode indexes documents by book code
+func booksByCode(documents []*usfm.Document) map[string]*usfm.Document {
+	books := make(map[string]*usfm.Document, len(documents))
+	for _, doc := range documents {
+		if code := doc.BookCode(); code != "" {
+			books[code] = doc
+		}
+	}
+	return books
+}
+
+// bookTitle returns the title of an added or removed book
+func bookTitle(doc *usfm.Document) string {
+	return cmp.Or(doc.MainTitle, doc.Header)
+}
@@ -0,0 +1,103 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/arenzana/usfmp/pkg/usfm"
+)
+
+// parse parses a USFM book for the tests
+func parse(t *testing.T, input string) *usfm.Document {
+	t.Helper()
+	doc, err := usfm.NewParser(usfm.DefaultParseOptions()).Parse(strings.NewReader(input), "test.usfm")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	return doc
+}
+
+// TestCompare tests the changes between two editions of a book
+func TestCompare(t *testing.T) {
+	old := parse(t, `\id RUT
+\mt1 Ruth
+\c 1
+\s1 Naomi Becomes a Widow
+\p
+\v 1 In the days when the judges ruled, there was a famine in the land.
+\v 2 The man's name was \w Elimelech|strong="H458"\w*.
+\s1 Ruth's Loyalty to Naomi
+\p
+\v 3 Then Naomi's husband died.\f + \fr 1:3 \ft Or died\f*
+\v 4 They took Moabite wives.
+\c 2
+\p
+\v 1-2 Now Naomi had a relative.`)
+
+	new := parse(t, `\id RUT
+\mt1 Ruth
+\c 1
+\s1 Naomi Becomes a Widow
+\p
+\v 1 In the days of the judges, there was a great famine in the land.
+\v 2 The man's name was \w Elimelech|strong="H0458"\w*.
+\s1 Ruth Stays with Naomi
+\p
+\v 3 Then Naomi's husband died.\f + \fr 1:3 \ft Hebrew died\f*
+\c 2
+\p
+\v 1-2 Now Naomi had a relative on her husband's side.
+\v 3 So Ruth went out.`)
+
+	obadiah := parse(t, `\id OBA
+\mt1 Obadiah
+\c 1
+\p
+\v 1 The vision of Obadiah.`)
+
+	expected := []string{
+		"~ RUT 1:1 In the days [-when-] {+of+} the [-judges ruled,-] {+judges,+} there was a {+great+} famine in the land.",
+		"~ RUT 1:3 heading [-Ruth's Loyalty to-] {+Ruth Stays with+} Naomi",
+		"~ RUT 1:3 footnote [-Or-] {+Hebrew+} died",
+		"- RUT 1:4 They took Moabite wives.",
+		"~ RUT 2:1-2 Now Naomi had a [-relative.-] {+relative on her husband's side.+}",
+		"+ RUT 2:3 So Ruth went out.",
+		"- OBA book Obadiah",
+	}
+
+	var result []string
+	for _, change := range Compare([]*usfm.Document{old, obadiah}, []*usfm.Document{new}) {
+		result = append(result, change.String())
+	}
+	if strings.Join(result, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected changes:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(result, "\n"))
+	}
+
+	if changes := Compare([]*usfm.Document{old}, []*usfm.Document{old}); len(changes) != 0 {
+		t.Errorf("Expected no changes between identical books, got %v", changes)
+	}
+}
+
+// TestWords tests word-level diffs and their markup
+func TestWords(t *testing.T) {
+	testCases := []struct {
+		old, new string
+		expected string
+		edits    int
+	}{
+		{"In the beginning", "In the beginning", "In the beginning", 1},
+		{"God created the heavens", "God made the heavens", "God [-created-] {+made+} the heavens", 4},
+		{"the earth", "the whole earth was", "the {+whole+} earth {+was+}", 4},
+		{"a b c", "", "[-a b c-]", 1},
+		{"", "a b", "{+a b+}", 1},
+		{"one two three", "four five", "[-one two three-] {+four five+}", 2},
+		{"", "", "", 0},
+	}
+
+	for _, tc := range testCases {
+		edits := Words(tc.old, tc.new)
+		if result := Markup(edits); result != tc.expected || len(edits) != tc.edits {
+			t.Errorf("%q -> %q: expected %q in %d edits, got %q in %d", tc.old, tc.new, tc.expected, tc.edits, result, len(edits))
+		}
+	}
+}
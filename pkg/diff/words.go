@@ -0,0 +1,93 @@
+package diff
+
+import (
+	"strings"
+)
+
+// Operations of a word-level diff
+const (
+	Equal  = "equal"  // Words in both texts
+	Delete = "delete" // Words only in the old text
+	Insert = "insert" // Words only in the new text
+)
+
+// Edit is a run of words that both texts share, or that only one of them has.
+type Edit struct {
+	Op   string `json:"op"`   // Equal, Delete, or Insert
+	Text string `json:"text"` // Words separated by single spaces
+}
+
+// Words returns a word-level diff of two texts split at white space, with the fewest
+// deleted and inserted words. Punctuation stays with its word, so "earth." and "earth;"
+// differ. Deleted words come before the words inserted in their place.
+func Words(old, new string) []Edit {
+	a, b := strings.Fields(old), strings.Fields(new)
+
+	// lengths[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lengths := make([][]int, len(a)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else {
+				lengths[i][j] = max(lengths[i+1][j], lengths[i][j+1])
+			}
+		}
+	}
+
+	var edits []Edit
+	add := func(op, word string) {
+		if n := len(edits); n > 0 && edits[n-1].Op == op {
+			edits[n-1].Text += " " + word
+			return
+		}
+		// Keep deletions before insertions when they alternate within one change
+		if n := len(edits); op == Delete && n > 1 && edits[n-1].Op == Insert && edits[n-2].Op == Delete {
+			edits[n-2].Text += " " + word
+			return
+		}
+		edits = append(edits, Edit{Op: op, Text: word})
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			add(Equal, a[i])
+			i, j = i+1, j+1
+		case lengths[i+1][j] >= lengths[i][j+1]:
+			add(Delete, a[i])
+			i++
+		default:
+			add(Insert, b[j])
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		add(Delete, a[i])
+	}
+	for ; j < len(b); j++ {
+		add(Insert, b[j])
+	}
+	return edits
+}
+
+// Markup writes a word-level diff as text with deleted words marked as [-word-] and
+// inserted words as {+word+}, as git diff --word-diff does.
+func Markup(edits []Edit) string {
+	parts := make([]string, 0, len(edits))
+	for _, edit := range edits {
+		switch edit.Op {
+		case Delete:
+			parts = append(parts, "[-"+edit.Text+"-]")
+		case Insert:
+			parts = append(parts, "{+"+edit.Text+"+}")
+		default:
+			parts = append(parts, edit.Text)
+		}
+	}
+	return strings.Join(parts, " ")
+}
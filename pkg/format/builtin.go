@@ -0,0 +1,304 @@
+package format
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/arenzana/usfmp/internal/formatter"
+	"github.com/arenzana/usfmp/pkg/usfm"
+)
+
+// The built-in formats, in the order they are listed in help text.
+// Format-specific settings use the names of the matching usfmp command line flags.
+func init() {
+	Register(Entry{Name: "json", Description: "JSON document, or array of documents", New: newJSON})
+	Register(Entry{Name: "txt", Description: "Human-readable plain text", New: newText})
+	Register(Entry{Name: "tsv", Description: "Tab-separated values, one row per verse (settings: columns, no-header)", New: newTSV})
+	Register(Entry{Name: "osis", Description: "OSIS 2.1 XML", New: newOSIS})
+	Register(Entry{Name: "zefania", Description: "Zefania XML for OpenLP", New: newZefania})
+	Register(Entry{Name: "opensong", Description: "OpenSong XML", New: newOpenSong})
+	Register(Entry{Name: "pdf", Description: "Print-ready PDF (settings: page-size, margins, font-size, font, bold-font)", New: newPDF})
+	Register(Entry{Name: "html", Description: "Standalone HTML page, or one page per book (settings: css)", New: newHTML})
+	Register(Entry{Name: "epub", Description: "EPUB 3 e-book (settings: identifier)", New: newEPUB})
+	Register(Entry{Name: "md", Description: "Markdown, or one file per chapter", New: newMarkdown})
+	Register(Entry{Name: "latex", Description: "LaTeX document", New: newLaTeX})
+	Register(Entry{Name: "docx", Description: "Word document", New: newDOCX})
+	Register(Entry{Name: "jsonl", Description: "JSON Lines, one record per line (settings: record)", New: newJSONL})
+	Register(Entry{Name: "csv", Description: "RFC 4180 CSV, or normalized tables (settings: columns, no-header)", New: newCSV})
+	Register(Entry{Name: "sql", Description: "SQL script with normalized tables for SQLite or PostgreSQL", New: newSQL})
+	Register(Entry{Name: "vpl", Description: "Verse-per-line plain text", New: newVPL})
+	Register(Entry{Name: "ssml", Description: "SSML for text-to-speech, one document per chapter (settings: read, section-pause, paragraph-pause)",
+		Directory: true, New: newSSML})
+	Register(Entry{Name: "template", Description: "User-supplied Go template (settings: template, template-html)", New: newTemplate})
+}
+
+// fileFormatter is a formatter that can also write its output as several files
+type fileFormatter struct {
+	Formatter
+	files func(documents []*usfm.Document) ([]File, error)
+}
+
+// FormatFiles writes the documents as several files
+func (f fileFormatter) FormatFiles(documents []*usfm.Document) ([]File, error) {
+	return f.files(documents)
+}
+
+// outputFiles converts the files written by a multi-file formatter to Files
+func outputFiles(files []formatter.OutputFile, err error) ([]File, error) {
+	if err != nil {
+		return nil, err
+	}
+	result := make([]File, len(files))
+	for i, file := range files {
+		result[i] = File(file)
+	}
+	return result, nil
+}
+
+func newJSON(options Options) (Formatter, error) {
+	return FormatterFunc(formatter.WriteJSON), nil
+}
+
+func newText(options Options) (Formatter, error) {
+	return FormatterFunc(formatter.WriteText), nil
+}
+
+func newTSV(options Options) (Formatter, error) {
+	tableOptions, err := tableSettings(options)
+	if err != nil {
+		return nil, err
+	}
+	return FormatterFunc(func(w io.Writer, documents []*usfm.Document) error {
+		return formatter.WriteTSV(w, documents, tableOptions)
+	}), nil
+}
+
+func newCSV(options Options) (Formatter, error) {
+	tableOptions, err := tableSettings(options)
+	if err != nil {
+		return nil, err
+	}
+	return fileFormatter{
+		Formatter: FormatterFunc(func(w io.Writer, documents []*usfm.Document) error {
+			return formatter.WriteCSV(w, documents, tableOptions)
+		}),
+		files: func(documents []*usfm.Document) ([]File, error) {
+			return outputFiles(formatter.FormatCSVTables(documents))
+		},
+	}, nil
+}
+
+// tableSettings reads the CSV and TSV settings: columns and no-header
+func tableSettings(options Options) (formatter.TableOptions, error) {
+	columns, err := formatter.ParseColumns(options.Setting("columns", ""))
+	if err != nil {
+		return formatter.TableOptions{}, err
+	}
+	noHeader, err := options.BoolSetting("no-header", false)
+	if err != nil {
+		return formatter.TableOptions{}, err
+	}
+	return formatter.TableOptions{Columns: columns, NoHeader: noHeader}, nil
+}
+
+func newOSIS(options Options) (Formatter, error) {
+	return FormatterFunc(formatter.WriteOSIS), nil
+}
+
+func newZefania(options Options) (Formatter, error) {
+	zefaniaOptions := formatter.ZefaniaOptions{
+		BibleName:        options.Title,
+		IncludeFootnotes: options.IncludeFootnotes,
+	}
+	return FormatterFunc(func(w io.Writer, documents []*usfm.Document) error {
+		return formatter.WriteZefania(w, documents, zefaniaOptions)
+	}), nil
+}
+
+func newOpenSong(options Options) (Formatter, error) {
+	return FormatterFunc(formatter.WriteOpenSong), nil
+}
+
+func newPDF(options Options) (Formatter, error) {
+	pdfOptions := formatter.DefaultPDFOptions()
+	pdfOptions.Title = options.Title
+
+	var err error
+	if value, ok := options.Settings["page-size"]; ok {
+		if pdfOptions.PageWidth, pdfOptions.PageHeight, err = formatter.ParsePageSize(value); err != nil {
+			return nil, err
+		}
+	}
+	if value, ok := options.Settings["margins"]; ok {
+		if pdfOptions.Margins, err = formatter.ParseMargins(value); err != nil {
+			return nil, err
+		}
+	}
+	if value, ok := options.Settings["font-size"]; ok {
+		if pdfOptions.FontSize, err = strconv.ParseFloat(value, 64); err != nil || pdfOptions.FontSize <= 0 {
+			return nil, fmt.Errorf("invalid font-size setting %q", value)
+		}
+	}
+	pdfOptions.FontFile = options.Setting("font", "")
+	pdfOptions.BoldFontFile = options.Setting("bold-font", "")
+
+	return FormatterFunc(func(w io.Writer, documents []*usfm.Document) error {
+		return formatter.WritePDF(w, documents, pdfOptions)
+	}), nil
+}
+
+func newHTML(options Options) (Formatter, error) {
+	css := options.Setting("css", "inline")
+	if css != "inline" && css != "classes" {
+		return nil, fmt.Errorf("invalid css setting: %s (valid: inline, classes)", css)
+	}
+	htmlOptions := formatter.HTMLOptions{
+		Title:            options.Title,
+		Language:         options.Language,
+		ClassOnly:        css == "classes",
+		IncludeFootnotes: options.IncludeFootnotes,
+	}
+
+	return fileFormatter{
+		Formatter: FormatterFunc(func(w io.Writer, documents []*usfm.Document) error {
+			return formatter.WriteHTML(w, documents, htmlOptions)
+		}),
+		files: func(documents []*usfm.Document) ([]File, error) {
+			return outputFiles(formatter.FormatHTMLBooks(documents, htmlOptions))
+		},
+	}, nil
+}
+
+func newEPUB(options Options) (Formatter, error) {
+	epubOptions := formatter.EPUBOptions{
+		Title:            options.Title,
+		Language:         options.Language,
+		Identifier:       options.Setting("identifier", ""),
+		IncludeFootnotes: options.IncludeFootnotes,
+	}
+	return FormatterFunc(func(w io.Writer, documents []*usfm.Document) error {
+		return formatter.WriteEPUB(w, documents, epubOptions)
+	}), nil
+}
+
+func newMarkdown(options Options) (Formatter, error) {
+	markdownOptions := formatter.MarkdownOptions{IncludeFootnotes: options.IncludeFootnotes}
+	return fileFormatter{
+		Formatter: FormatterFunc(func(w io.Writer, documents []*usfm.Document) error {
+			return formatter.WriteMarkdown(w, documents, markdownOptions)
+		}),
+		files: func(documents []*usfm.Document) ([]File, error) {
+			return outputFiles(formatter.FormatMarkdownChapters(documents, markdownOptions))
+		},
+	}, nil
+}
+
+func newLaTeX(options Options) (Formatter, error) {
+	latexOptions := formatter.LaTeXOptions{
+		Title:            options.Title,
+		IncludeFootnotes: options.IncludeFootnotes,
+	}
+	return FormatterFunc(func(w io.Writer, documents []*usfm.Document) error {
+		return formatter.WriteLaTeX(w, documents, latexOptions)
+	}), nil
+}
+
+func newDOCX(options Options) (Formatter, error) {
+	docxOptions := formatter.DOCXOptions{
+		Title:            options.Title,
+		IncludeFootnotes: options.IncludeFootnotes,
+	}
+	return FormatterFunc(func(w io.Writer, documents []*usfm.Document) error {
+		return formatter.WriteDOCX(w, documents, docxOptions)
+	}), nil
+}
+
+func newJSONL(options Options) (Formatter, error) {
+	record, err := formatter.ParseJSONLRecord(options.Setting("record", string(formatter.JSONLVerse)))
+	if err != nil {
+		return nil, err
+	}
+	return FormatterFunc(func(w io.Writer, documents []*usfm.Document) error {
+		return formatter.WriteJSONL(w, documents, formatter.JSONLOptions{Record: record})
+	}), nil
+}
+
+func newSQL(options Options) (Formatter, error) {
+	return FormatterFunc(formatter.WriteSQL), nil
+}
+
+func newVPL(options Options) (Formatter, error) {
+	return FormatterFunc(formatter.WriteVPL), nil
+}
+
+func newSSML(options Options) (Formatter, error) {
+	ssmlOptions := formatter.DefaultSSMLOptions()
+	if options.Language != "" {
+		ssmlOptions.Language = options.Language
+	}
+
+	for _, item := range strings.Split(options.Setting("read", ""), ",") {
+		switch strings.TrimSpace(item) {
+		case "":
+		case "headings":
+			ssmlOptions.ReadHeadings = true
+		case "verse-numbers":
+			ssmlOptions.ReadVerseNumbers = true
+		case "footnotes":
+			ssmlOptions.ReadFootnotes = true
+		default:
+			return nil, fmt.Errorf("invalid read setting: %s (valid: headings, verse-numbers, footnotes)", item)
+		}
+	}
+
+	var err error
+	if value, ok := options.Settings["section-pause"]; ok {
+		if ssmlOptions.SectionPause, err = time.ParseDuration(value); err != nil {
+			return nil, fmt.Errorf("invalid section-pause setting: %w", err)
+		}
+	}
+	if value, ok := options.Settings["paragraph-pause"]; ok {
+		if ssmlOptions.ParagraphPause, err = time.ParseDuration(value); err != nil {
+			return nil, fmt.Errorf("invalid paragraph-pause setting: %w", err)
+		}
+	}
+
+	return fileFormatter{
+		Formatter: FormatterFunc(func(w io.Writer, documents []*usfm.Document) error {
+			return fmt.Errorf("format ssml writes one file per chapter and cannot be written to a single stream")
+		}),
+		files: func(documents []*usfm.Document) ([]File, error) {
+			return outputFiles(formatter.FormatSSML(documents, ssmlOptions))
+		},
+	}, nil
+}
+
+func newTemplate(options Options) (Formatter, error) {
+	path := options.Setting("template", "")
+	if path == "" {
+		return nil, fmt.Errorf("format template requires a template file")
+	}
+	text, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading template: %w", err)
+	}
+	html, err := options.BoolSetting("template-html", false)
+	if err != nil {
+		return nil, err
+	}
+
+	templateOptions := formatter.TemplateOptions{
+		Name:  filepath.Base(path),
+		Text:  string(text),
+		HTML:  html,
+		Title: options.Title,
+	}
+	return FormatterFunc(func(w io.Writer, documents []*usfm.Document) error {
+		return formatter.WriteTemplate(w, documents, templateOptions)
+	}), nil
+}
@@ -0,0 +1,147 @@
+// Package format provides the output formats of usfmp behind a common Formatter interface,
+// and a registry that lists them by name.
+//
+// All built-in formats (json, txt, tsv, html, pdf, ...) are registered when the package is
+// loaded. Programs can register their own formats, which the usfmp command line accepts for
+// --format like the built-in ones.
+//
+// Example:
+//
+//	entry, ok := format.Lookup("json")
+//	if !ok {
+//		log.Fatal("unknown format")
+//	}
+//	formatter, err := entry.New(format.Options{IncludeFootnotes: true})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	err = formatter.Format(os.Stdout, documents)
+package format
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+
+	"github.com/arenzana/usfmp/pkg/usfm"
+)
+
+// Formatter writes USFM documents in an output format.
+type Formatter interface {
+	// Format writes the documents to w. Formatters write their output as it is produced
+	// where the format allows it, rather than building it in memory first.
+	Format(w io.Writer, documents []*usfm.Document) error
+}
+
+// FormatterFunc adapts an ordinary function to the Formatter interface.
+type FormatterFunc func(w io.Writer, documents []*usfm.Document) error
+
+// Format calls f(w, documents).
+func (f FormatterFunc) Format(w io.Writer, documents []*usfm.Document) error {
+	return f(w, documents)
+}
+
+// File is one file of a multi-file output.
+type File struct {
+	Name string // File name relative to the output directory, with "/" separators (e.g., "01-GEN/Genesis 1.md")
+	Data []byte // File contents
+}
+
+// FileFormatter is implemented by formatters that can write their output as several files,
+// such as one HTML page per book or one Markdown file per chapter.
+type FileFormatter interface {
+	FormatFiles(documents []*usfm.Document) ([]File, error)
+}
+
+// Options are the settings a formatter is created with.
+type Options struct {
+	Title            string            // Title for formats that carry publication metadata
+	Language         string            // Language code of the text (e.g., "en")
+	IncludeFootnotes bool              // Whether to include footnotes in formats where they are optional
+	Settings         map[string]string // Format-specific settings by name (e.g., "columns" or "page-size")
+}
+
+// Setting returns a format-specific setting, or fallback if it is not set.
+func (o Options) Setting(name, fallback string) string {
+	if value, ok := o.Settings[name]; ok {
+		return value
+	}
+	return fallback
+}
+
+// BoolSetting returns a format-specific setting parsed as a boolean, or fallback if it is not set.
+func (o Options) BoolSetting(name string, fallback bool) (bool, error) {
+	value, ok := o.Settings[name]
+	if !ok {
+		return fallback, nil
+	}
+	result, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s setting %q: %w", name, value, err)
+	}
+	return result, nil
+}
+
+// Entry describes a registered output format.
+type Entry struct {
+	Name        string                                   // Name used to select the format (e.g., "json")
+	Description string                                   // Short description for help text
+	Directory   bool                                     // Whether the format is always written as several files into a directory
+	New         func(options Options) (Formatter, error) // Creates a formatter; returns an error for invalid settings
+}
+
+// registry holds the registered formats in registration order
+var registry struct {
+	sync.RWMutex
+	entries []Entry
+}
+
+// Register adds an output format to the registry.
+// It panics if the name is empty or already registered, or if New is nil,
+// so that conflicting registrations are caught when the program starts.
+func Register(entry Entry) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	if entry.Name == "" || entry.New == nil {
+		panic("format: Register requires a name and a New function")
+	}
+	for _, existing := range registry.entries {
+		if existing.Name == entry.Name {
+			panic(fmt.Sprintf("format: Register called twice for format %s", entry.Name))
+		}
+	}
+	registry.entries = append(registry.entries, entry)
+}
+
+// Lookup returns the registered format with the given name.
+func Lookup(name string) (Entry, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	for _, entry := range registry.entries {
+		if entry.Name == name {
+			return entry, true
+		}
+	}
+	return Entry{}, false
+}
+
+// Entries returns all registered formats in registration order, starting with the built-in formats.
+func Entries() []Entry {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	return append([]Entry(nil), registry.entries...)
+}
+
+// Names returns the names of all registered formats in registration order.
+func Names() []string {
+	entries := Entries()
+	names := make([]string, len(entries))
+	for i, entry := range entries {
+		names[i] = entry.Name
+	}
+	return names
+}
@@ -0,0 +1,373 @@
+package format
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/arenzana/usfmp/internal/formatter"
+	"github.com/arenzana/usfmp/pkg/usfm"
+)
+
+// parseTestDocuments parses a short USFM book for formatting
+func parseTestDocuments(t *testing.T) []*usfm.Document {
+	t.Helper()
+
+	input := `\id GEN - Test Bible
+\h Genesis
+\toc1 Genesis
+\mt1 Genesis
+\c 1
+\s1 The Creation
+\p
+\v 1 In the beginning God created the heavens and the earth.\f + \fr 1:1 \ft Hebrew: Elohim\f*
+\v 2 Now the earth was formless and void.`
+
+	doc, err := usfm.NewParser(usfm.DefaultParseOptions()).Parse(strings.NewReader(input), "test.sfm")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	return []*usfm.Document{doc}
+}
+
+// TestBuiltinFormats tests that every built-in format is registered and produces output
+func TestBuiltinFormats(t *testing.T) {
+	expected := []string{"json", "txt", "tsv", "osis", "zefania", "opensong", "pdf", "html", "epub",
+		"md", "latex", "docx", "jsonl", "csv", "sql", "vpl", "ssml", "template"}
+
+	names := Names()
+	if len(names) < len(expected) {
+		t.Fatalf("Expected at least %d formats, got %v", len(expected), names)
+	}
+	for i, name := range expected {
+		if names[i] != name {
+			t.Errorf("Expected format %d to be %s, got %s", i, name, names[i])
+		}
+	}
+
+	documents := parseTestDocuments(t)
+	for _, entry := range Entries() {
+		if entry.Description == "" {
+			t.Errorf("Format %s has no description", entry.Name)
+		}
+		if entry.Name == "template" || entry.Directory {
+			continue
+		}
+
+		output, err := entry.New(Options{Title: "Test Bible", Language: "en", IncludeFootnotes: true})
+		if err != nil {
+			t.Errorf("Format %s: New failed: %v", entry.Name, err)
+			continue
+		}
+		var result bytes.Buffer
+		if err := output.Format(&result, documents); err != nil {
+			t.Errorf("Format %s: Format failed: %v", entry.Name, err)
+			continue
+		}
+		if result.Len() == 0 {
+			t.Errorf("Format %s: expected output, got none", entry.Name)
+		}
+	}
+}
+
+// TestFormatMatchesFormatter tests that streamed output matches the string formatters
+func TestFormatMatchesFormatter(t *testing.T) {
+	documents := parseTestDocuments(t)
+
+	expectedJSON, err := formatter.FormatJSON(documents)
+	if err != nil {
+		t.Fatalf("FormatJSON failed: %v", err)
+	}
+	expectedTSV, err := formatter.FormatTSV(documents, formatter.TableOptions{Columns: formatter.DefaultColumns})
+	if err != nil {
+		t.Fatalf("FormatTSV failed: %v", err)
+	}
+	expectedText, err := formatter.FormatText(documents)
+	if err != nil {
+		t.Fatalf("FormatText failed: %v", err)
+	}
+	expectedOSIS, _ := formatter.FormatOSIS(documents)
+	expectedZefania, _ := formatter.FormatZefania(documents, formatter.ZefaniaOptions{})
+	expectedOpenSong, _ := formatter.FormatOpenSong(documents)
+	expectedHTML, _ := formatter.FormatHTML(documents, formatter.HTMLOptions{})
+	expectedMarkdown, _ := formatter.FormatMarkdown(documents, formatter.MarkdownOptions{})
+	expectedLaTeX, _ := formatter.FormatLaTeX(documents, formatter.LaTeXOptions{})
+	expectedSQL, _ := formatter.FormatSQL(documents)
+	expectedVPL, _ := formatter.FormatVPL(documents)
+
+	testCases := []struct {
+		name     string
+		expected string
+	}{
+		{"json", expectedJSON + "\n"},
+		{"tsv", expectedTSV},
+		{"txt", expectedText},
+		{"osis", expectedOSIS},
+		{"zefania", expectedZefania},
+		{"opensong", expectedOpenSong},
+		{"html", expectedHTML},
+		{"md", expectedMarkdown},
+		{"latex", expectedLaTeX},
+		{"sql", expectedSQL},
+		{"vpl", expectedVPL},
+	}
+
+	for _, tc := range testCases {
+		entry, ok := Lookup(tc.name)
+		if !ok {
+			t.Fatalf("Format %s not registered", tc.name)
+		}
+		output, err := entry.New(Options{})
+		if err != nil {
+			t.Fatalf("Format %s: New failed: %v", tc.name, err)
+		}
+		var result strings.Builder
+		if err := output.Format(&result, documents); err != nil {
+			t.Fatalf("Format %s: Format failed: %v", tc.name, err)
+		}
+		if result.String() != tc.expected {
+			t.Errorf("Format %s: expected:\n%s\ngot:\n%s", tc.name, tc.expected, result.String())
+		}
+	}
+}
+
+// countingWriter counts the writes made to it
+type countingWriter struct {
+	writes int
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	return len(p), nil
+}
+
+// TestFormatStreams tests that the text formats write two books in several parts
+// instead of building the whole output first
+func TestFormatStreams(t *testing.T) {
+	documents := append(parseTestDocuments(t), parseTestDocuments(t)...)
+
+	for _, name := range []string{"json", "txt", "tsv", "osis", "zefania", "opensong", "html", "md", "latex", "jsonl", "sql", "vpl"} {
+		entry, _ := Lookup(name)
+		output, err := entry.New(Options{})
+		if err != nil {
+			t.Fatalf("Format %s: New failed: %v", name, err)
+		}
+		var w countingWriter
+		if err := output.Format(&w, documents); err != nil {
+			t.Fatalf("Format %s: Format failed: %v", name, err)
+		}
+		if w.writes < 2 {
+			t.Errorf("Format %s: expected the output in several writes, got %d", name, w.writes)
+		}
+	}
+}
+
+// TestFormatSettings tests format-specific settings and their validation
+func TestFormatSettings(t *testing.T) {
+	testCases := []struct {
+		format   string
+		settings map[string]string
+		expected string // Expected error, or "" if the settings are valid
+	}{
+		{"csv", map[string]string{"columns": "book,chapter,verse", "no-header": "true"}, ""},
+		{"csv", map[string]string{"columns": "book,unknown"}, "unknown"},
+		{"tsv", map[string]string{"no-header": "maybe"}, "invalid no-header setting"},
+		{"html", map[string]string{"css": "classes"}, ""},
+		{"html", map[string]string{"css": "external"}, "invalid css setting"},
+		{"jsonl", map[string]string{"record": "section"}, ""},
+		{"jsonl", map[string]string{"record": "word"}, "word"},
+		{"pdf", map[string]string{"page-size": "A5", "margins": "36", "font-size": "10"}, ""},
+		{"pdf", map[string]string{"page-size": "huge"}, "huge"},
+		{"pdf", map[string]string{"font-size": "-1"}, "invalid font-size setting"},
+		{"ssml", map[string]string{"read": "headings,footnotes", "section-pause": "2s"}, ""},
+		{"ssml", map[string]string{"read": "titles"}, "invalid read setting"},
+		{"ssml", map[string]string{"paragraph-pause": "soon"}, "invalid paragraph-pause setting"},
+		{"template", nil, "requires a template file"},
+		{"template", map[string]string{"template": filepath.Join(t.TempDir(), "missing.tmpl")}, "error reading template"},
+	}
+
+	for _, tc := range testCases {
+		entry, ok := Lookup(tc.format)
+		if !ok {
+			t.Fatalf("Format %s not registered", tc.format)
+		}
+		_, err := entry.New(Options{Settings: tc.settings})
+		if tc.expected == "" {
+			if err != nil {
+				t.Errorf("Format %s with %v: unexpected error: %v", tc.format, tc.settings, err)
+			}
+		} else if err == nil || !strings.Contains(err.Error(), tc.expected) {
+			t.Errorf("Format %s with %v: expected error containing %q, got %v", tc.format, tc.settings, tc.expected, err)
+		}
+	}
+}
+
+// TestTemplateFormat tests the template format reading its template from a file
+func TestTemplateFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "verses.tmpl")
+	text := `{{range .Documents}}{{$book := .BookCode}}{{range .Chapters}}{{$chapter := .Number}}{{range verses .}}{{ref $book $chapter .}}
+{{end}}{{end}}{{end}}`
+	if err := os.WriteFile(path, []byte(text), 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	entry, _ := Lookup("template")
+	output, err := entry.New(Options{Settings: map[string]string{"template": path}})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	var result strings.Builder
+	if err := output.Format(&result, parseTestDocuments(t)); err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+
+	expected := "Genesis 1:1\nGenesis 1:2\n"
+	if result.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, result.String())
+	}
+}
+
+// TestFileFormatters tests the formats that can write several files
+func TestFileFormatters(t *testing.T) {
+	documents := parseTestDocuments(t)
+
+	testCases := []struct {
+		format   string
+		expected string // Name of the first file
+	}{
+		{"html", "01-GEN.html"},
+		{"md", "01-GEN/Genesis 1.md"},
+		{"csv", "books.csv"},
+		{"ssml", "01-GEN/01-GEN-001.ssml"},
+	}
+
+	for _, tc := range testCases {
+		entry, _ := Lookup(tc.format)
+		output, err := entry.New(Options{})
+		if err != nil {
+			t.Fatalf("Format %s: New failed: %v", tc.format, err)
+		}
+		files, ok := output.(FileFormatter)
+		if !ok {
+			t.Fatalf("Format %s: expected a FileFormatter", tc.format)
+		}
+		result, err := files.FormatFiles(documents)
+		if err != nil {
+			t.Fatalf("Format %s: FormatFiles failed: %v", tc.format, err)
+		}
+		if len(result) == 0 {
+			t.Errorf("Format %s: expected files, got none", tc.format)
+		} else if result[0].Name != tc.expected {
+			t.Errorf("Format %s: expected first file %s, got %s", tc.format, tc.expected, result[0].Name)
+		}
+	}
+
+	entry, _ := Lookup("ssml")
+	if !entry.Directory {
+		t.Errorf("Expected format ssml to be written to a directory")
+	}
+	output, _ := entry.New(Options{})
+	if err := output.Format(io.Discard, documents); err == nil {
+		t.Errorf("Expected format ssml to fail writing to a single stream")
+	}
+}
+
+// TestRegister tests registering a custom format
+func TestRegister(t *testing.T) {
+	Register(Entry{
+		Name:        "test-count",
+		Description: "Number of documents",
+		New: func(options Options) (Formatter, error) {
+			prefix := options.Setting("prefix", "documents: ")
+			return FormatterFunc(func(w io.Writer, documents []*usfm.Document) error {
+				_, err := io.WriteString(w, prefix+string(rune('0'+len(documents))))
+				return err
+			}), nil
+		},
+	})
+
+	names := Names()
+	if names[len(names)-1] != "test-count" {
+		t.Errorf("Expected test-count to be listed last, got %v", names)
+	}
+
+	entry, ok := Lookup("test-count")
+	if !ok {
+		t.Fatalf("Expected test-count to be registered")
+	}
+	output, err := entry.New(Options{Settings: map[string]string{"prefix": "n="}})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	var result strings.Builder
+	if err := output.Format(&result, parseTestDocuments(t)); err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+	if result.String() != "n=1" {
+		t.Errorf("Expected n=1, got %q", result.String())
+	}
+
+	if _, ok := Lookup("missing"); ok {
+		t.Errorf("Expected unknown format not to be found")
+	}
+}
+
+// TestRegisterInvalid tests that invalid and duplicate registrations panic
+func TestRegisterInvalid(t *testing.T) {
+	newFormatter := func(options Options) (Formatter, error) { return nil, errors.New("unused") }
+
+	testCases := []struct {
+		name  string
+		entry Entry
+	}{
+		{"duplicate", Entry{Name: "json", New: newFormatter}},
+		{"empty name", Entry{New: newFormatter}},
+		{"nil New", Entry{Name: "test-nil"}},
+	}
+
+	for _, tc := range testCases {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: expected Register to panic", tc.name)
+				}
+			}()
+			Register(tc.entry)
+		}()
+	}
+}
+
+// TestBoolSetting tests reading boolean settings
+func TestBoolSetting(t *testing.T) {
+	options := Options{Settings: map[string]string{"on": "true", "off": "0", "bad": "yes please"}}
+
+	testCases := []struct {
+		name     string
+		fallback bool
+		expected bool
+		err      bool
+	}{
+		{"on", false, true, false},
+		{"off", true, false, false},
+		{"missing", true, true, false},
+		{"bad", false, false, true},
+	}
+
+	for _, tc := range testCases {
+		result, err := options.BoolSetting(tc.name, tc.fallback)
+		if (err != nil) != tc.err {
+			t.Errorf("Setting %s: expected error %v, got %v", tc.name, tc.err, err)
+		}
+		if result != tc.expected {
+			t.Errorf("Setting %s: expected %v, got %v", tc.name, tc.expected, result)
+		}
+	}
+
+	if value := options.Setting("missing", "fallback"); value != "fallback" {
+		t.Errorf("Expected fallback, got %s", value)
+	}
+}
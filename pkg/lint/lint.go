@@ -0,0 +1,489 @@
+// Package lint checks USFM source for structural problems in the manner of the Paratext
+// basic checks: chapter and verse numbering, empty verses and sections, footnote locations,
+// missing book metadata, and unclosed character and note markers. Given a versification,
+// it also checks that every book has the chapters and verses of the scheme.
+//
+// The linter reads the markers of the source (see usfm.Tokenize) instead of a parsed
+// Document, so that every finding carries the line it was found on and files the parser
+// rejects can still be checked.
+// Each finding names a stable rule ID (see Rules).
+//
+// Example:
+//
+//	findings, err := lint.CheckFile("samples/bsb_usfm/01GENBSB.SFM", lint.Options{})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	for _, finding := range findings {
+//		fmt.Println(finding) // samples/bsb_usfm/01GENBSB.SFM:1: missing-metadata: missing \toc3 (book abbreviation)
+//	}
+package lint
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/arenzana/usfmp/pkg/ref"
+	"github.com/arenzana/usfmp/pkg/usfm"
+	"github.com/arenzana/usfmp/pkg/versification"
+)
+
+// Finding is a problem found in a USFM file.
+type Finding struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// String formats the finding as "file:line: rule: message".
+func (f Finding) String() string {
+	return fmt.Sprintf("%s:%d: %s: %s", f.File, f.Line, f.Rule, f.Message)
+}
+
+// Options configures the checks of the linter.
+type Options struct {
+	Versification *versification.Scheme // Scheme to check chapters and verses against, or nil to skip the check
+}
+
+// CheckFile checks a USFM file.
+func CheckFile(path string, options Options) ([]Finding, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening file %s: %w", path, err)
+	}
+	defer file.Close()
+
+	return Check(file, path, options)
+}
+
+// Check checks USFM source read from r, naming file in the findings. The findings are
+// sorted by line; an error is returned only if the source cannot be read.
+func Check(r io.Reader, file string, options Options) ([]Finding, error) {
+	c := &checker{file: file, scheme: options.Versification}
+	c.resetBook()
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		for _, t := range usfm.Tokenize(scanner.Text(), lineNumber) {
+			c.token(t)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", file, err)
+	}
+
+	c.finishBook()
+	if !c.hasID {
+		c.report(1, RuleMissingMetadata, `missing \id (book identification)`)
+	}
+
+	sort.SliceStable(c.findings, func(i, j int) bool {
+		return c.findings[i].Line < c.findings[j].Line
+	})
+	return c.findings, nil
+}
+
+// verseState is the verse being read
+type verseState struct {
+	chapter, number, last int  // Chapter, first verse, and last verse of a bridge
+	line                  int  // Line of the \v marker
+	text                  bool // Whether the verse has text
+}
+
+// heading is a section heading that no text has followed yet
+type heading struct {
+	tag   string
+	level int
+	line  int
+}
+
+// openMarker is a character marker or note that has not been closed yet
+type openMarker struct {
+	tag  string
+	line int
+}
+
+// checker holds the state of the linter while it reads a file
+type checker struct {
+	file     string
+	scheme   *versification.Scheme // Versification to check against, or nil
+	findings []Finding
+	hasID    bool // Whether the file has an \id marker
+
+	book     string          // Book code from \id
+	bookLine int             // Line of \id
+	metadata map[string]bool // Book metadata markers found (\h, \toc1, \mt1, ...)
+
+	chapter     int         // Current chapter, or 0 before the first \c
+	chapterLine int         // Line of the current \c, or 0 if its number is invalid
+	chapters    map[int]int // Lines of the chapters of the book
+	lastVerse   int         // Highest verse number of the chapter
+	verses      map[int]int // Lines of the verses of the chapter
+	verse       *verseState // Current verse, or nil outside verses
+
+	inHeading bool         // Whether text belongs to a heading or title instead of a verse
+	headings  []heading    // Section headings without verses
+	open      []openMarker // Unclosed character markers and notes, innermost last
+}
+
+// report adds a finding
+func (c *checker) report(line int, rule, format string, args ...interface{}) {
+	c.findings = append(c.findings, Finding{File: c.file, Line: line, Rule: rule, Message: fmt.Sprintf(format, args...)})
+}
+
+// token processes a marker and its text
+func (c *checker) token(t usfm.Token) {
+	switch {
+	case t.Milestone:
+		// Milestones such as \qt-s |who="Jesus"\* carry attributes, not text
+		return
+	case t.Tag == "":
+		c.addText(t.Content)
+		return
+	case t.Closing:
+		c.close(t)
+		c.addText(t.Content)
+		return
+	}
+
+	switch kind := markerKind(t.Tag); kind {
+	case kindID:
+		c.finishBook()
+		c.resetBook()
+		c.hasID = true
+		c.book = strings.ToUpper(firstField(t.Content))
+		c.bookLine = t.Line
+		c.inHeading = true
+	case kindChapter:
+		c.closeAll()
+		c.finishVerse()
+		c.finishHeadings(0)
+		c.startChapter(t)
+		c.inHeading = true
+	case kindVerse:
+		c.closeNotes()
+		c.finishVerse()
+		c.startVerse(t)
+	case kindNote:
+		c.closeNotes()
+		c.open = append(c.open, openMarker{tag: t.Tag, line: t.Line})
+	case kindNoteContent:
+		if t.Tag == "fr" {
+			c.checkFootnoteLocation(t)
+		}
+	case kindCharacter:
+		c.open = append(c.open, openMarker{tag: t.Tag, line: t.Line})
+		c.addText(t.Content)
+	case kindParagraph:
+		c.closeAll()
+		c.inHeading = false
+		c.addText(t.Content)
+	case kindHeading, kindSection:
+		c.closeAll()
+		c.inHeading = true
+		c.metadata[metadataTag(t.Tag)] = true
+		if kind == kindSection {
+			level := sectionLevel(t.Tag)
+			c.finishHeadings(level)
+			c.headings = append(c.headings, heading{tag: t.Tag, level: level, line: t.Line})
+		}
+	default:
+		// Unknown markers are left to strict parsing; their text continues the current verse
+		c.addText(t.Content)
+	}
+}
+
+// addText records text outside of notes and headings: it gives the current verse text,
+// and the section headings before it content, even when a heading splits a verse
+func (c *checker) addText(text string) {
+	if c.inHeading || c.inNote() || strings.TrimSpace(text) == "" {
+		return
+	}
+	c.headings = nil
+	if c.verse != nil {
+		c.verse.text = true
+	}
+}
+
+// resetBook starts a new book
+func (c *checker) resetBook() {
+	c.book, c.bookLine = "", 1
+	c.metadata = make(map[string]bool)
+	c.chapter, c.chapterLine, c.chapters = 0, 0, make(map[int]int)
+	c.lastVerse, c.verses, c.verse = 0, make(map[int]int), nil
+	c.inHeading, c.headings, c.open = true, nil, nil
+}
+
+// requiredMetadata are the metadata markers every book of the canon needs
+var requiredMetadata = []struct{ tag, description string }{
+	{"h", "running header"},
+	{"toc1", "long book name"},
+	{"toc2", "short book name"},
+	{"toc3", "book abbreviation"},
+	{"mt1", "main title"},
+}
+
+// finishBook reports what is still open or missing at the end of a book
+func (c *checker) finishBook() {
+	c.closeAll()
+	c.finishVerse()
+	c.finishHeadings(0)
+	c.finishChapter()
+	c.reportGaps(c.chapters, RuleChapterGap, "chapter", "")
+
+	// Peripheral books such as FRT and GLO need not have the metadata of the canon
+	if _, ok := usfm.LookupBook(c.book); !ok {
+		return
+	}
+	for _, required := range requiredMetadata {
+		if !c.metadata[required.tag] {
+			c.report(c.bookLine, RuleMissingMetadata, `missing \%s (%s)`, required.tag, required.description)
+		}
+	}
+
+	if c.scheme != nil {
+		chapters := make([]int, 0, len(c.chapters))
+		for chapter := range c.chapters {
+			chapters = append(chapters, chapter)
+		}
+		for _, difference := range c.scheme.CheckBook(c.book, chapters) {
+			c.report(c.bookLine, RuleVersification, "%s", difference.Message)
+		}
+	}
+}
+
+// finishChapter reports the verses missing from the current chapter and checks its verses
+// against the versification
+func (c *checker) finishChapter() {
+	if c.chapterLine == 0 {
+		return
+	}
+	c.reportGaps(c.verses, RuleVerseGap, "verse", fmt.Sprintf("%d:", c.chapter))
+
+	if c.scheme == nil || c.chapter > c.scheme.Chapters(c.book) {
+		// Chapters and books the scheme does not have are reported for the whole book
+		return
+	}
+	verses := make([]int, 0, len(c.verses))
+	for verse := range c.verses {
+		verses = append(verses, verse)
+	}
+	for _, difference := range c.scheme.CheckChapter(c.book, c.chapter, verses) {
+		c.report(c.chapterLine, RuleVersification, "%s", difference.Message)
+	}
+}
+
+// startChapter checks the number of a chapter against the chapters before it
+func (c *checker) startChapter(t usfm.Token) {
+	c.finishChapter()
+	c.chapterLine, c.lastVerse, c.verses = 0, 0, make(map[int]int)
+
+	value := firstField(t.Content)
+	number, err := strconv.Atoi(value)
+	if err != nil || number < 1 {
+		c.report(t.Line, RuleInvalidNumber, `invalid chapter number %q`, value)
+		return
+	}
+
+	switch {
+	case c.chapters[number] > 0:
+		c.report(t.Line, RuleChapterDuplicate, "chapter %d occurs twice", number)
+	case number <= c.chapter:
+		c.report(t.Line, RuleChapterOrder, "chapter %d follows chapter %d", number, c.chapter)
+	}
+	if c.chapters[number] == 0 {
+		c.chapters[number] = t.Line
+	}
+	c.chapter, c.chapterLine = number, t.Line
+}
+
+// startVerse checks the number of a verse against the verses before it in the chapter
+func (c *checker) startVerse(t usfm.Token) {
+	value, text, _ := strings.Cut(strings.TrimLeft(t.Content, " "), " ")
+	number, last, err := usfm.ParseVerseNumber(value)
+	if err != nil || number < 1 {
+		c.report(t.Line, RuleInvalidNumber, `invalid verse number %q`, value)
+		return
+	}
+	last = max(last, number)
+	c.headings = nil
+
+	duplicate := 0
+	for n := number; n <= last; n++ {
+		if c.verses[n] > 0 {
+			duplicate = n
+			break
+		}
+	}
+	switch {
+	case c.chapter == 0:
+		c.report(t.Line, RuleVerseOrder, `verse %s comes before the first \c`, value)
+	case duplicate > 0:
+		c.report(t.Line, RuleVerseDuplicate, "verse %d:%d occurs twice", c.chapter, duplicate)
+	case number <= c.lastVerse:
+		c.report(t.Line, RuleVerseOrder, "verse %d:%s follows verse %d:%d", c.chapter, value, c.chapter, c.lastVerse)
+	}
+
+	for n := number; n <= last; n++ {
+		if c.verses[n] == 0 {
+			c.verses[n] = t.Line
+		}
+	}
+	c.lastVerse = max(c.lastVerse, last)
+	c.verse = &verseState{chapter: c.chapter, number: number, last: last, line: t.Line}
+	c.inHeading = false
+	c.addText(text)
+}
+
+// finishVerse reports the current verse if it has no text
+func (c *checker) finishVerse() {
+	if c.verse != nil && !c.verse.text {
+		name := strconv.Itoa(c.verse.number)
+		if c.verse.last > c.verse.number {
+			name += "-" + strconv.Itoa(c.verse.last)
+		}
+		c.report(c.verse.line, RuleEmptyVerse, "verse %d:%s is empty", c.verse.chapter, name)
+	}
+	c.verse = nil
+}
+
+// finishHeadings reports the section headings of the given level or lower (or all of them
+// for level 0) that no text has followed
+func (c *checker) finishHeadings(level int) {
+	kept := c.headings[:0]
+	for _, h := range c.headings {
+		if level == 0 || h.level >= level {
+			c.report(h.line, RuleEmptySection, `section heading \%s has no text`, h.tag)
+		} else {
+			kept = append(kept, h)
+		}
+	}
+	c.headings = kept
+}
+
+// checkFootnoteLocation checks that the \fr location of a footnote is the verse that holds it
+func (c *checker) checkFootnoteLocation(t usfm.Token) {
+	if c.verse == nil || c.inHeading || c.book == "" {
+		return
+	}
+	location := strings.TrimSpace(t.Content)
+	if location == "" {
+		return
+	}
+
+	ranges, err := ref.ParseRelative(location, ref.Reference{Book: c.book, Chapter: c.verse.chapter})
+	if err == nil {
+		verse := ref.Range{
+			Start: ref.Reference{Book: c.book, Chapter: c.verse.chapter, Verse: c.verse.number},
+			End:   ref.Reference{Book: c.book, Chapter: c.verse.chapter, Verse: c.verse.last},
+		}
+		for _, r := range ranges {
+			if verse.Contains(r.Start) || r.Contains(verse.Start) {
+				return
+			}
+		}
+	}
+
+	name := fmt.Sprintf("%d:%d", c.verse.chapter, c.verse.number)
+	if c.verse.last > c.verse.number {
+		name += fmt.Sprintf("-%d", c.verse.last)
+	}
+	c.report(t.Line, RuleFootnoteLocation, `footnote location \fr %s is not verse %s`, location, name)
+}
+
+// close closes the innermost open marker with the tag of a closing marker,
+// reporting the markers inside it that were left open
+func (c *checker) close(t usfm.Token) {
+	if markerKind(t.Tag) == kindNoteContent {
+		// Closing \fq*, \xt*, and similar markers inside notes are optional
+		return
+	}
+	for i := len(c.open) - 1; i >= 0; i-- {
+		if c.open[i].tag == t.Tag {
+			c.reportUnclosed(c.open[i+1:])
+			c.open = c.open[:i]
+			return
+		}
+	}
+	c.report(t.Line, RuleUnmatchedMarker, `\%s* has no opening \%s`, t.Tag, t.Tag)
+}
+
+// closeNotes reports an open note and the markers inside it, which must be closed before a
+// verse or another note begins
+func (c *checker) closeNotes() {
+	for i, m := range c.open {
+		if markerKind(m.tag) == kindNote {
+			c.reportUnclosed(c.open[i:])
+			c.open = c.open[:i]
+			return
+		}
+	}
+}
+
+// closeAll reports every open marker at the end of a paragraph
+func (c *checker) closeAll() {
+	c.reportUnclosed(c.open)
+	c.open = nil
+}
+
+// reportUnclosed reports open markers as unclosed
+func (c *checker) reportUnclosed(markers []openMarker) {
+	for _, m := range markers {
+		c.report(m.line, RuleUnclosedMarker, `\%s is not closed with \%s*`, m.tag, m.tag)
+	}
+}
+
+// inNote reports whether text is inside a footnote or cross-reference note
+func (c *checker) inNote() bool {
+	for _, m := range c.open {
+		if markerKind(m.tag) == kindNote {
+			return true
+		}
+	}
+	return false
+}
+
+// reportGaps reports the numbers missing below the highest number seen, given the lines
+// the numbers were seen on; each run of missing numbers is reported on the line of the
+// number after it, so numbers that are out of order are not also reported as missing
+func (c *checker) reportGaps(lines map[int]int, rule, noun, prefix string) {
+	highest := 0
+	for n := range lines {
+		highest = max(highest, n)
+	}
+	for n := 1; n < highest; n++ {
+		if lines[n] > 0 {
+			continue
+		}
+		first := n
+		for lines[n+1] == 0 {
+			n++
+		}
+		c.report(lines[n+1], rule, "%s missing", numberRange(noun, prefix, first, n))
+	}
+}
+
+// numberRange describes missing numbers, e.g. "chapter 3 is" or "verses 3:4-6 are"
+func numberRange(noun, prefix string, first, last int) string {
+	if first == last {
+		return fmt.Sprintf("%s %s%d is", noun, prefix, first)
+	}
+	return fmt.Sprintf("%ss %s%d-%d are", noun, prefix, first, last)
+}
+
+// firstField returns the first whitespace-separated field of text
+func firstField(text string) string {
+	if fields := strings.Fields(text); len(fields) > 0 {
+		return fields[0]
+	}
+	return ""
+}
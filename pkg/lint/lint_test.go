@@ -0,0 +1,237 @@
+package lint
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/arenzana/usfmp/pkg/versification"
+)
+
+// TestCheck tests that every rule reports its finding on the right line
+func TestCheck(t *testing.T) {
+	input := `\id RUT Ruth
+\h Ruth
+\toc1 The Book of Ruth
+\toc2 Ruth
+\mt1 Ruth
+\c 1
+\s1 Naomi Loses Her Husband
+\p
+\v 1 In the days when the judges ruled.\f + \fr 1:1 \ft Or \fq ruled*\f*
+\v 2 The man's name was \add Elimelech.
+\v 2 His wife was Naomi.
+\v 5 Then both sons died.\f + \fr 1:4 \ft Hebrew\f*
+\s1 Empty Heading
+\s1 Ruth's Loyalty
+\v 4 \f + \fr 1:4 \ft Verse omitted\f*
+\v 6-7 She heard\wj* in Moab\f + \fr 1:7 \ft Bridge\f*
+\c 3
+\p
+\v 1 Naomi said,\f + \fr 3:1 \ft Note
+\v 2 \nd Lord\nd* bless you.
+\c 2
+\v x Boaz.`
+
+	expected := []string{
+		"1 missing-metadata missing \\toc3 (book abbreviation)",
+		"10 unclosed-marker \\add is not closed with \\add*",
+		"11 verse-duplicate verse 1:2 occurs twice",
+		"12 footnote-location footnote location \\fr 1:4 is not verse 1:5",
+		"13 empty-section section heading \\s1 has no text",
+		"15 verse-order verse 1:4 follows verse 1:5",
+		"15 empty-verse verse 1:4 is empty",
+		"15 verse-gap verse 1:3 is missing",
+		"16 unmatched-marker \\wj* has no opening \\wj",
+		"19 unclosed-marker \\f is not closed with \\f*",
+		"21 chapter-order chapter 2 follows chapter 3",
+		"22 invalid-number invalid verse number \"x\"",
+	}
+
+	findings, err := Check(strings.NewReader(input), "RUT.usfm", Options{})
+	if err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+
+	var result []string
+	for _, f := range findings {
+		if f.File != "RUT.usfm" {
+			t.Errorf("Expected file RUT.usfm, got %q", f.File)
+		}
+		result = append(result, fmt.Sprintf("%d %s %s", f.Line, f.Rule, f.Message))
+	}
+	if strings.Join(result, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected findings:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(result, "\n"))
+	}
+}
+
+// TestCheckGaps tests that missing numbers are reported once the chapter or book is finished,
+// and that numbers out of order are not also reported as missing
+func TestCheckGaps(t *testing.T) {
+	input := `\id JHN
+\c 1
+\p
+\v 1 In the beginning was the Word.
+\v 3 Through Him all things were made.
+\v 2 He was with God in the beginning.
+\c 3
+\p
+\v 2 He came to Jesus at night.
+\v 5 Jesus answered.
+\c 2
+\p
+\v 1 On the third day.
+\c 6
+\p
+\v 1 After this, Jesus crossed the Sea of Galilee.`
+
+	expected := []string{
+		"6 verse-order verse 1:2 follows verse 1:3",
+		"9 verse-gap verse 3:1 is missing",
+		"10 verse-gap verses 3:3-4 are missing",
+		"11 chapter-order chapter 2 follows chapter 3",
+		"14 chapter-gap chapters 4-5 are missing",
+	}
+
+	findings, err := Check(strings.NewReader(input), "JHN.usfm", Options{})
+	if err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+	var result []string
+	for _, f := range findings {
+		if f.Rule != RuleMissingMetadata {
+			result = append(result, fmt.Sprintf("%d %s %s", f.Line, f.Rule, f.Message))
+		}
+	}
+	if strings.Join(result, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected findings:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(result, "\n"))
+	}
+}
+
+// TestCheckClean tests that well-formed books have no findings
+func TestCheckClean(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input string
+	}{
+		{"poetry and nested markers", `\id PSA Psalms
+\h Psalms
+\toc1 The Psalms
+\toc2 Psalms
+\toc3 Ps
+\mt Psalms
+\c 1
+\s1 The Two Ways
+\q1
+\v 1 Blessed is the man\f + \fr 1.1 \ft Or \fq man\fq* \+w who|strong="H834"\+w*\f*
+\q2 who does not \w walk|strong="H1980"\w* in the counsel of the \wj wicked,\wj*
+\q1 \wj or stand in the way of sinners,\wj*
+\v 2 But his delight is in the \nd Law\nd* of the Lord.
+\s2 A heading inside verse 2
+\q1 On His law he meditates day and night.`},
+		{"peripheral book without metadata", `\id FRT Preface
+\is1 To the Reader
+\ip Zeal to promote the common good.`},
+		{"several books in one file", `\id OBA
+\h Obadiah
+\toc1 Obadiah
+\toc2 Obadiah
+\toc3 Obad
+\mt1 Obadiah
+\c 1
+\p
+\v 1 The vision of Obadiah.
+\id JON
+\h Jonah
+\toc1 Jonah
+\toc2 Jonah
+\toc3 Jonah
+\mt1 Jonah
+\c 1
+\p
+\v 1 Now the word of the Lord came to Jonah.\x - \xo 1:1 \xt 2 Kings 14:25\x*`},
+	}
+
+	for _, tc := range testCases {
+		findings, err := Check(strings.NewReader(tc.input), "test.usfm", Options{})
+		if err != nil {
+			t.Fatalf("%s: Check failed: %v", tc.name, err)
+		}
+		for _, f := range findings {
+			t.Errorf("%s: unexpected finding %s", tc.name, f)
+		}
+	}
+
+	findings, _ := Check(strings.NewReader(`\c 1`+"\n"+`\v 1 Text`), "test.usfm", Options{})
+	if len(findings) != 1 || findings[0].String() != `test.usfm:1: missing-metadata: missing \id (book identification)` {
+		t.Errorf("Expected a missing \\id finding, got %v", findings)
+	}
+}
+
+// TestCheckVersification tests that chapters and verses are checked against a versification
+func TestCheckVersification(t *testing.T) {
+	scheme, err := versification.Load("Test", strings.NewReader("OBA 1:3\nJON 1:2"))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	input := `\id OBA
+\c 1
+\p
+\v 1 The vision of Obadiah.
+\v 3 The pride of your heart has deceived you.
+\v 4 Though you soar like the eagle.
+\c 2
+\p
+\v 1 An extra chapter.
+\id JON
+\c 1
+\p
+\v 1 Now the word of the Lord came to Jonah.
+\id TOB
+\c 1
+\p
+\v 1 The book of the words of Tobit.`
+
+	expected := []string{
+		"1 Obadiah 2 is not in the Test versification",
+		"2 Obadiah 1:2 is missing",
+		"2 Obadiah 1:4 is not in the Test versification",
+		"11 Jonah 1:2 is missing",
+		"14 book TOB is not in the Test versification",
+	}
+
+	findings, err := Check(strings.NewReader(input), "test.usfm", Options{Versification: scheme})
+	if err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+	var result []string
+	for _, f := range findings {
+		if f.Rule == RuleVersification {
+			result = append(result, fmt.Sprintf("%d %s", f.Line, f.Message))
+		}
+	}
+	if strings.Join(result, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected findings:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(result, "\n"))
+	}
+}
+
+// TestRules tests that every rule ID is described
+func TestRules(t *testing.T) {
+	ids := []string{
+		RuleMissingMetadata, RuleInvalidNumber, RuleChapterOrder, RuleChapterGap, RuleChapterDuplicate,
+		RuleVerseOrder, RuleVerseGap, RuleVerseDuplicate, RuleEmptyVerse, RuleEmptySection,
+		RuleFootnoteLocation, RuleUnclosedMarker, RuleUnmatchedMarker, RuleVersification,
+	}
+	if len(Rules) != len(ids) {
+		t.Errorf("Expected %d rules, got %d", len(ids), len(Rules))
+	}
+	for _, id := range ids {
+		if rule, ok := LookupRule(id); !ok || rule.Description == "" {
+			t.Errorf("Rule %s is not described", id)
+		}
+	}
+	if _, ok := LookupRule("unknown"); ok {
+		t.Error("Expected no rule named unknown")
+	}
+}
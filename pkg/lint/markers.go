@@ -0,0 +1,101 @@
+package lint
+
+import (
+	"strconv"
+	"strings"
+)
+
+// kind is the role of a marker in the structure of a book
+type kind int
+
+const (
+	kindUnknown     kind = iota
+	kindID               // \id
+	kindChapter          // \c
+	kindVerse            // \v
+	kindParagraph        // Paragraph, poetry, list, and table markers that hold verse text
+	kindHeading          // Titles, headers, and headings whose text is not verse text
+	kindSection          // Section headings (\s, \s1, ... \s4), which are followed by verses
+	kindNote             // Footnotes and cross-reference notes, closed with \f* or \x*
+	kindNoteContent      // Markers inside notes (\fr, \ft, \xo, ...), whose closing is optional
+	kindCharacter        // Character markers, closed with \w*, \add*, ...
+)
+
+// paragraphMarkers are the paragraph markers without their level number (\q for \q1, \q2, ...)
+var paragraphMarkers = map[string]bool{
+	"p": true, "m": true, "po": true, "pr": true, "cls": true, "pmo": true, "pm": true, "pmc": true,
+	"pmr": true, "pi": true, "mi": true, "nb": true, "pc": true, "ph": true, "b": true, "pb": true,
+	"q": true, "qr": true, "qc": true, "qa": true, "qm": true, "qd": true,
+	"li": true, "lh": true, "lf": true, "lim": true, "tr": true, "th": true, "thr": true, "tc": true, "tcr": true,
+}
+
+// headingMarkers are the title, header, and heading markers without their level number
+var headingMarkers = map[string]bool{
+	"ide": true, "h": true, "toc": true, "toca": true, "rem": true, "sts": true, "usfm": true, "restore": true,
+	"mt": true, "mte": true, "ms": true, "mr": true, "sr": true, "r": true, "d": true, "sp": true, "sd": true,
+	"cl": true, "cd": true, "cp": true, "lit": true,
+}
+
+// noteMarkers open footnotes, endnotes, and cross-references
+var noteMarkers = map[string]bool{"f": true, "fe": true, "ef": true, "x": true, "ex": true}
+
+// noteContentMarkers are the markers inside notes
+var noteContentMarkers = map[string]bool{
+	"fr": true, "ft": true, "fk": true, "fq": true, "fqa": true, "fl": true, "fw": true, "fp": true,
+	"fv": true, "fdc": true, "fm": true,
+	"xo": true, "xop": true, "xk": true, "xq": true, "xt": true, "xta": true, "xot": true, "xnt": true, "xdc": true,
+}
+
+// characterMarkers are the character markers that need a closing marker
+var characterMarkers = map[string]bool{
+	"add": true, "bk": true, "dc": true, "k": true, "nd": true, "ord": true, "pn": true, "png": true,
+	"addpn": true, "qt": true, "sig": true, "sls": true, "tl": true, "wj": true, "em": true, "bd": true,
+	"it": true, "bdit": true, "no": true, "sc": true, "sup": true, "w": true, "wg": true, "wh": true,
+	"wa": true, "rb": true, "pro": true, "fig": true, "jmp": true, "rq": true, "qs": true, "qac": true,
+	"ior": true, "iqt": true, "ca": true, "va": true, "vp": true, "ndx": true, "lik": true, "liv": true,
+	"litl": true,
+}
+
+// markerKind returns the role of a marker
+func markerKind(tag string) kind {
+	base := strings.TrimRight(tag, "0123456789")
+	switch {
+	case tag == "id":
+		return kindID
+	case tag == "c":
+		return kindChapter
+	case tag == "v":
+		return kindVerse
+	case noteMarkers[tag]:
+		return kindNote
+	case noteContentMarkers[tag]:
+		return kindNoteContent
+	case characterMarkers[tag]:
+		return kindCharacter
+	case base == "s":
+		return kindSection
+	case paragraphMarkers[base]:
+		return kindParagraph
+	case headingMarkers[base], strings.HasPrefix(tag, "i"):
+		// Introduction markers (\imt, \is, \ip, ...) hold no verse text either
+		return kindHeading
+	}
+	return kindUnknown
+}
+
+// metadataTag returns the metadata marker a heading marker stands for: \mt counts as \mt1
+func metadataTag(tag string) string {
+	if tag == "mt" {
+		return "mt1"
+	}
+	return tag
+}
+
+// sectionLevel returns the level of a section heading: 1 for \s and \s1, 2 for \s2, ...
+func sectionLevel(tag string) int {
+	level, err := strconv.Atoi(strings.TrimPrefix(tag, "s"))
+	if err != nil {
+		return 1
+	}
+	return level
+}
@@ -0,0 +1,54 @@
+package lint
+
+// Rule IDs name the checks of the linter. They are stable, so that findings can be
+// filtered, counted, and suppressed across releases.
+const (
+	RuleMissingMetadata  = "missing-metadata"  // \id, \h, \toc1, \toc2, \toc3, or \mt1 is missing
+	RuleInvalidNumber    = "invalid-number"    // \c or \v is not followed by a number (or a verse bridge)
+	RuleChapterOrder     = "chapter-order"     // A chapter number is lower than the one before it
+	RuleChapterGap       = "chapter-gap"       // Chapter numbers skip one or more chapters
+	RuleChapterDuplicate = "chapter-duplicate" // A chapter number occurs twice in a book
+	RuleVerseOrder       = "verse-order"       // A verse number is lower than the one before it, or comes before \c
+	RuleVerseGap         = "verse-gap"         // Verse numbers skip one or more verses
+	RuleVerseDuplicate   = "verse-duplicate"   // A verse number occurs twice in a chapter
+	RuleEmptyVerse       = "empty-verse"       // A verse has no text (footnotes do not count)
+	RuleEmptySection     = "empty-section"     // A section heading is followed by no text
+	RuleFootnoteLocation = "footnote-location" // The \fr of a footnote is not the verse that holds the footnote
+	RuleUnclosedMarker   = "unclosed-marker"   // A character marker or note is not closed before the paragraph ends
+	RuleUnmatchedMarker  = "unmatched-marker"  // A closing marker has no opening marker
+	RuleVersification    = "versification"     // Chapters or verses differ from the versification (see Options)
+)
+
+// Rule describes a check of the linter.
+type Rule struct {
+	ID          string `json:"id"`
+	Description string `json:"description"`
+}
+
+// Rules lists the checks of the linter in the order they are described in the documentation.
+var Rules = []Rule{
+	{RuleMissingMetadata, `\id, \h, \toc1, \toc2, \toc3, or \mt1 is missing`},
+	{RuleInvalidNumber, `\c or \v is not followed by a chapter or verse number`},
+	{RuleChapterOrder, "A chapter number is lower than the one before it"},
+	{RuleChapterGap, "Chapter numbers skip one or more chapters"},
+	{RuleChapterDuplicate, "A chapter number occurs twice in a book"},
+	{RuleVerseOrder, `A verse number is lower than the one before it, or comes before the first \c`},
+	{RuleVerseGap, "Verse numbers skip one or more verses"},
+	{RuleVerseDuplicate, "A verse number occurs twice in a chapter"},
+	{RuleEmptyVerse, "A verse has no text apart from footnotes"},
+	{RuleEmptySection, "A section heading is followed by no text before the next heading or chapter"},
+	{RuleFootnoteLocation, `The \fr location of a footnote is not the verse that holds it`},
+	{RuleUnclosedMarker, `A character marker or note (\w, \add, \f, ...) is not closed before the paragraph ends`},
+	{RuleUnmatchedMarker, `A closing marker (\w*, \f*, ...) has no opening marker`},
+	{RuleVersification, "Chapters or verses are missing from or not in the chosen versification"},
+}
+
+// LookupRule returns the rule with the given ID.
+func LookupRule(id string) (Rule, bool) {
+	for _, rule := range Rules {
+		if rule.ID == id {
+			return rule, true
+		}
+	}
+	return Rule{}, false
+}
@@ -0,0 +1,156 @@
+// Package parallel aligns several translations verse by verse for reading or comparing them
+// side by side.
+//
+// Each translation may be numbered in its own versification; its verses are mapped to the
+// versification of the alignment, so that English Psalm 51:1 lines up with Psalm 50:3 of a
+// translation numbered like the Vulgate.
+//
+// Example:
+//
+//	rows := parallel.Align([]parallel.Translation{
+//		{Name: "BSB", Documents: bsb.Documents},
+//		{Name: "KJV", Documents: kjv.Documents},
+//	}, versification.English)
+//	for _, row := range rows {
+//		fmt.Println(row.Reference, row.Verses[0].Text, row.Verses[1].Text)
+//	}
+package parallel
+
+import (
+	"slices"
+
+	"github.com/arenzana/usfmp/pkg/ref"
+	"github.com/arenzana/usfmp/pkg/usfm"
+	"github.com/arenzana/usfmp/pkg/versification"
+)
+
+// Translation is a translation to align.
+type Translation struct {
+	Name          string                // Name of the translation (e.g., "BSB"), written in headers and records
+	Documents     []*usfm.Document      // Books of the translation
+	Versification *versification.Scheme // Scheme the translation is numbered in, or nil if it is numbered like the alignment
+}
+
+// Row holds the text of every translation at a verse.
+type Row struct {
+	Reference ref.Reference `json:"reference"` // Verse in the versification of the alignment
+	Verses    []Verse       `json:"verses"`    // Verse of each translation, in the order of the translations
+}
+
+// Verse is the text of a translation at a row.
+type Verse struct {
+	Translation string    `json:"translation"`        // Name of the translation
+	Reference   ref.Range `json:"reference,omitzero"` // Verse or verse bridge in the translation's own numbering, or zero if it has none
+	Text        string    `json:"text"`               // Verse text without markup; empty for a missing verse and for the later verses of a verse bridge
+}
+
+// Align returns one row per verse of the versification, in canonical order, for the books that
+// any of the translations has. Verses of a translation that map to no verse of the versification
+// are left out, and verses that map to the same verse are joined. With a nil versification, the
+// translations are aligned by their own numbers, with one row for each verse any of them has.
+func Align(translations []Translation, scheme *versification.Scheme) []Row {
+	cells := make(map[ref.Reference][]Verse)
+	books := make(map[string]bool)
+	for i, translation := range translations {
+		own := translation.Versification
+		if own == nil {
+			own = scheme
+		}
+		for _, doc := range translation.Documents {
+			book := doc.BookCode()
+			if book == "" {
+				continue
+			}
+			books[book] = true
+			for _, chapter := range doc.Chapters {
+				for _, section := range chapter.Sections {
+					for _, verse := range section.Verses {
+						addVerse(cells, len(translations), i, book, chapter.Number, verse, own, scheme)
+					}
+				}
+			}
+		}
+	}
+
+	references := make([]ref.Reference, 0, len(cells))
+	for reference := range cells {
+		references = append(references, reference)
+	}
+	if scheme != nil {
+		for _, book := range scheme.Books() {
+			if !books[book] {
+				continue
+			}
+			for chapter := 1; chapter <= scheme.Chapters(book); chapter++ {
+				for verse := 1; verse <= scheme.Verses(book, chapter); verse++ {
+					reference := ref.Reference{Book: book, Chapter: chapter, Verse: verse}
+					if _, ok := cells[reference]; !ok {
+						references = append(references, reference)
+					}
+				}
+			}
+		}
+	}
+	slices.SortFunc(references, ref.Reference.Compare)
+
+	rows := make([]Row, 0, len(references))
+	for _, reference := range references {
+		verses := cells[reference]
+		if verses == nil {
+			verses = make([]Verse, len(translations))
+		}
+		for i, translation := range translations {
+			verses[i].Translation = translation.Name
+		}
+		rows = append(rows, Row{Reference: reference, Verses: verses})
+	}
+	return rows
+}
+
+// addVerse adds each verse of a verse or verse bridge of translation i to the row it maps to
+func addVerse(cells map[ref.Reference][]Verse, count, i int, book string, chapter int, verse usfm.Verse, own, scheme *versification.Scheme) {
+	bridge := ref.Range{Start: ref.Reference{Book: book, Chapter: chapter, Verse: verse.Number}}
+	if verse.EndNumber > verse.Number {
+		bridge.End = ref.Reference{Book: book, Chapter: chapter, Verse: verse.EndNumber}
+	}
+
+	for number := verse.Number; number <= max(verse.Number, verse.EndNumber); number++ {
+		reference := ref.Reference{Book: book, Chapter: chapter, Verse: number}
+		if scheme != nil {
+			mapped, ok := versification.Map(reference, own, scheme)
+			if !ok {
+				continue
+			}
+			reference = mapped
+		}
+
+		if cells[reference] == nil {
+			cells[reference] = make([]Verse, count)
+		}
+		cell := &cells[reference][i]
+		text := ""
+		if number == verse.Number {
+			text = usfm.PlainText(verse.Text)
+		}
+
+		if cell.Reference.Start.IsZero() {
+			cell.Reference, cell.Text = bridge, text
+			continue
+		}
+		// Several verses of the translation map to this verse, or a heading splits the verse
+		if cell.Reference != bridge {
+			cell.Reference.End = bridge.Last()
+		}
+		if text != "" {
+			cell.Text = joinText(cell.Text, text)
+		}
+	}
+}
+
+// joinText joins the texts of verses that map to the same verse
+func joinText(a, b string) string {
+	if a == "" {
+		return b
+	}
+	return a + " " + b
+}
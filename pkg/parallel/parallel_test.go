@@ -0,0 +1,102 @@
+package parallel
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/arenzana/usfmp/pkg/ref"
+	"github.com/arenzana/usfmp/pkg/usfm"
+	"github.com/arenzana/usfmp/pkg/versification"
+)
+
+// parse parses a USFM book for the tests
+func parse(t *testing.T, input string) *usfm.Document {
+	t.Helper()
+	doc, err := usfm.NewParser(usfm.DefaultParseOptions()).Parse(strings.NewReader(input), "test.usfm")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	return doc
+}
+
+// TestAlign tests aligning translations numbered in different versifications
+func TestAlign(t *testing.T) {
+	english := parse(t, `\id PSA
+\c 3
+\q1
+\v 1 O LORD, how my foes have increased!
+\v 2-3 Many say of me, "God will not deliver him."`)
+
+	// The Hebrew numbering counts the title as verse 1
+	hebrew := parse(t, `\id PSA
+\c 3
+\d
+\v 1 A Psalm of David, when he fled from his son Absalom.
+\q1
+\v 2 LORD, how many are my foes!
+\v 3 Many are saying of my soul.`)
+
+	obadiah := parse(t, `\id OBA
+\c 1
+\p
+\v 1 The vision of Obadiah.`)
+
+	translations := []Translation{
+		{Name: "ENG", Documents: []*usfm.Document{english}},
+		{Name: "HEB", Documents: []*usfm.Document{hebrew}, Versification: versification.Original},
+		{Name: "OBA", Documents: []*usfm.Document{obadiah}},
+	}
+	rows := Align(translations, versification.English)
+
+	expectedRows := versification.English.Verses("OBA", 1)
+	for chapter := 1; chapter <= versification.English.Chapters("PSA"); chapter++ {
+		expectedRows += versification.English.Verses("PSA", chapter)
+	}
+	if len(rows) != expectedRows {
+		t.Errorf("Expected %d rows, got %d", expectedRows, len(rows))
+	}
+
+	byReference := make(map[string][]Verse)
+	for _, row := range rows {
+		if len(row.Verses) != 3 || row.Verses[0].Translation != "ENG" || row.Verses[2].Translation != "OBA" {
+			t.Fatalf("Unexpected verses at %s: %+v", row.Reference, row.Verses)
+		}
+		byReference[ref.Format([]ref.Range{{Start: row.Reference}}, ref.USFM)] = row.Verses
+	}
+
+	testCases := []struct {
+		reference   string
+		translation int
+		expected    string // Own reference and text
+	}{
+		{"PSA 3:1", 0, "PSA 3:1 O LORD, how my foes have increased!"},
+		{"PSA 3:1", 1, "PSA 3:2 LORD, how many are my foes!"},
+		{"PSA 3:1", 2, " "},
+		{"PSA 3:2", 0, `PSA 3:2-3 Many say of me, "God will not deliver him."`},
+		{"PSA 3:2", 1, "PSA 3:3 Many are saying of my soul."},
+		{"PSA 3:3", 0, "PSA 3:2-3 "},
+		{"PSA 3:3", 1, " "},
+		{"OBA 1:1", 2, "OBA 1:1 The vision of Obadiah."},
+		{"OBA 1:2", 2, " "},
+	}
+	for _, tc := range testCases {
+		verse := byReference[tc.reference][tc.translation]
+		result := ""
+		if !verse.Reference.Start.IsZero() {
+			result = ref.Format([]ref.Range{verse.Reference}, ref.USFM)
+		}
+		if result += " " + verse.Text; result != tc.expected {
+			t.Errorf("%s %s: expected %q, got %q", tc.reference, verse.Translation, tc.expected, result)
+		}
+	}
+
+	// Without a versification, the rows are the verses the translations have
+	rows = Align(translations[:1], nil)
+	var references []string
+	for _, row := range rows {
+		references = append(references, ref.Format([]ref.Range{{Start: row.Reference}}, ref.USFM))
+	}
+	if strings.Join(references, ", ") != "PSA 3:1, PSA 3:2, PSA 3:3" {
+		t.Errorf("Expected rows PSA 3:1-3, got %s", strings.Join(references, ", "))
+	}
+}
@@ -0,0 +1,136 @@
+package ref
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/arenzana/usfmp/internal/canon"
+)
+
+// bookAliases lists common book names and abbreviations that are neither a book's code,
+// name, or OSIS identifier nor an unambiguous prefix of its name
+var bookAliases = map[string]string{
+	"psalm":          "PSA",
+	"pss":            "PSA",
+	"songofsolomon":  "SNG",
+	"canticles":      "SNG",
+	"qoheleth":       "ECC",
+	"mt":             "MAT",
+	"mk":             "MRK",
+	"lk":             "LUK",
+	"jn":             "JHN",
+	"apocalypse":     "REV",
+	"revelations":    "REV",
+	"ecclesiasticus": "SIR",
+	"wisdom":         "WIS",
+}
+
+// singleChapterBooks lists the books with only one chapter, whose references
+// usually give the verse alone ("Jude 3")
+var singleChapterBooks = map[string]bool{
+	"OBA": true,
+	"PHM": true,
+	"2JN": true,
+	"3JN": true,
+	"JUD": true,
+	"MAN": true,
+}
+
+// bookNames maps normalized book codes, names, OSIS identifiers, and aliases to book codes
+var bookNames = func() map[string]string {
+	names := make(map[string]string)
+	for alias, code := range bookAliases {
+		names[alias] = code
+	}
+	for _, book := range canon.Books() {
+		names[normalizeBookName(book.Name)] = book.Code
+		names[normalizeBookName(book.OSIS)] = book.Code
+	}
+	// Codes take precedence, so that "JUD" is Jude as in USFM
+	for _, book := range canon.Books() {
+		names[normalizeBookName(book.Code)] = book.Code
+	}
+	return names
+}()
+
+// ResolveBook returns the USFM code of a book given by its code ("JHN"), English name ("John"),
+// OSIS identifier, or a common abbreviation ("Gen.", "Matt", "1 Cor", "I Kings").
+// Names are matched case-insensitively, ignoring spaces and punctuation, and a name may be
+// shortened to any prefix that identifies a single book.
+func ResolveBook(name string) (string, error) {
+	key := normalizeBookName(name)
+	if key == "" {
+		return "", fmt.Errorf("empty book name")
+	}
+	if code, ok := bookNames[key]; ok {
+		return code, nil
+	}
+
+	var matches []canon.Book
+	for _, book := range canon.Books() {
+		if strings.HasPrefix(normalizeBookName(book.Name), key) {
+			matches = append(matches, book)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("unknown book %q", name)
+	case 1:
+		return matches[0].Code, nil
+	default:
+		candidates := make([]string, len(matches))
+		for i, book := range matches {
+			candidates[i] = book.Name
+		}
+		return "", fmt.Errorf("ambiguous book %q (could be %s)", name, strings.Join(candidates, ", "))
+	}
+}
+
+// normalizeBookName lower-cases a book name, turns a leading ordinal such as "I" or "First"
+// into a digit, and removes everything but letters and digits
+func normalizeBookName(name string) string {
+	fields := strings.Fields(strings.ToLower(name))
+	if len(fields) > 1 {
+		switch strings.TrimSuffix(fields[0], ".") {
+		case "i", "first", "1st":
+			fields[0] = "1"
+		case "ii", "second", "2nd":
+			fields[0] = "2"
+		case "iii", "third", "3rd":
+			fields[0] = "3"
+		case "iv", "fourth", "4th":
+			fields[0] = "4"
+		}
+	}
+
+	var result strings.Builder
+	for _, r := range strings.Join(fields, "") {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			result.WriteRune(r)
+		}
+	}
+	return result.String()
+}
+
+// bookName returns the display name of a book code in the given style
+func bookName(code string, style BookStyle) string {
+	book, ok := canon.Lookup(code)
+	if !ok {
+		return code
+	}
+	switch style {
+	case BookCode:
+		return book.Code
+	case BookOSIS:
+		return book.OSIS
+	default:
+		return book.Name
+	}
+}
+
+// bookNumber returns the canonical position of a book code, or 0 if the book is unknown
+func bookNumber(code string) int {
+	book, _ := canon.Lookup(code)
+	return book.Number
+}
@@ -0,0 +1,163 @@
+package ref
+
+import (
+	"fmt"
+	"strings"
+)
+
+// BookStyle selects how book names are written.
+type BookStyle int
+
+const (
+	BookName BookStyle = iota // English book name ("1 Corinthians")
+	BookCode                  // USFM book code ("1CO")
+	BookOSIS                  // OSIS book identifier ("1Cor")
+)
+
+// Style configures how references are formatted.
+type Style struct {
+	Book          BookStyle // How book names are written
+	ChapterVerse  string    // Separator between chapter and verse (":")
+	Range         string    // Separator between the start and end of a range ("–")
+	List          string    // Separator between references to different chapters or books ("; ")
+	VerseList     string    // Separator between references to verses of the same chapter (", ")
+	Compact       bool      // Whether to leave out a book or chapter that repeats the previous reference
+	SingleChapter bool      // Whether to leave out the chapter of books with a single chapter ("Jude 3")
+}
+
+var (
+	// Default is the style of printed Bibles: "John 1:1–5, 9; Hebrews 11:1–3".
+	Default = Style{
+		Book:          BookName,
+		ChapterVerse:  ":",
+		Range:         "–",
+		List:          "; ",
+		VerseList:     ", ",
+		Compact:       true,
+		SingleChapter: true,
+	}
+
+	// USFM writes each reference in full with USFM book codes, as in the link targets
+	// of USFM cross-references: "JHN 1:1-5; JHN 1:9; HEB 11:1-3".
+	USFM = Style{
+		Book:         BookCode,
+		ChapterVerse: ":",
+		Range:        "-",
+		List:         "; ",
+		VerseList:    "; ",
+	}
+
+	// Short uses OSIS book abbreviations and ASCII punctuation: "John 1:1-5,9; Heb 11:1-3".
+	Short = Style{
+		Book:          BookOSIS,
+		ChapterVerse:  ":",
+		Range:         "-",
+		List:          "; ",
+		VerseList:     ",",
+		Compact:       true,
+		SingleChapter: true,
+	}
+)
+
+// level is how much of a reference is written
+type level int
+
+const (
+	levelBook    level = iota // Book, chapter, and verse
+	levelChapter              // Chapter and verse
+	levelVerse                // Verse only
+)
+
+// Format formats references in the given style.
+func Format(ranges []Range, style Style) string {
+	var result strings.Builder
+	var previous Reference
+
+	for i, r := range ranges {
+		start := levelBook
+		if i > 0 {
+			start = style.continuation(previous, r.Start)
+			if start == levelVerse {
+				result.WriteString(style.VerseList)
+			} else {
+				result.WriteString(style.List)
+			}
+		}
+		style.write(&result, r.Start, start)
+
+		if !r.End.IsZero() {
+			result.WriteString(style.Range)
+			end := levelBook
+			if r.End.Book == r.Start.Book {
+				end = levelChapter
+				if r.End.Chapter == r.Start.Chapter && r.Start.Verse > 0 && r.End.Verse > 0 {
+					end = levelVerse
+				}
+			}
+			style.write(&result, r.End, end)
+		}
+		previous = r.Last()
+	}
+
+	return result.String()
+}
+
+// OSIS formats references as an OSIS reference list: "John.1.1-John.1.5 Heb.11".
+// Segments are written as grains ("Matt.5.3!a"), and unknown books keep their code.
+func OSIS(ranges []Range) string {
+	items := make([]string, len(ranges))
+	for i, r := range ranges {
+		items[i] = osisReference(r.Start)
+		if !r.End.IsZero() {
+			items[i] += "-" + osisReference(r.End)
+		}
+	}
+	return strings.Join(items, " ")
+}
+
+// osisReference formats a single OSIS reference
+func osisReference(r Reference) string {
+	result := bookName(r.Book, BookOSIS)
+	if r.Chapter > 0 {
+		result += fmt.Sprintf(".%d", r.Chapter)
+	}
+	if r.Verse > 0 {
+		result += fmt.Sprintf(".%d", r.Verse)
+	}
+	if r.Segment != "" {
+		result += "!" + r.Segment
+	}
+	return result
+}
+
+// continuation returns how much of a reference needs to be written after the previous one
+func (s Style) continuation(previous, next Reference) level {
+	if !s.Compact || next.Book != previous.Book || next.Chapter == 0 {
+		return levelBook
+	}
+	if next.Chapter == previous.Chapter && next.Verse > 0 && previous.Verse > 0 {
+		return levelVerse
+	}
+	return levelChapter
+}
+
+// write writes a reference from the given level down
+func (s Style) write(result *strings.Builder, r Reference, from level) {
+	if from == levelBook && r.Book != "" {
+		result.WriteString(bookName(r.Book, s.Book))
+		if r.Chapter == 0 {
+			return
+		}
+		result.WriteString(" ")
+	}
+
+	omitChapter := s.SingleChapter && singleChapterBooks[r.Book] && r.Chapter == 1 && r.Verse > 0
+	if from != levelVerse && !omitChapter {
+		fmt.Fprintf(result, "%d", r.Chapter)
+		if r.Verse == 0 {
+			return
+		}
+		result.WriteString(s.ChapterVerse)
+	}
+	fmt.Fprintf(result, "%d%s", r.Verse, r.Segment)
+}
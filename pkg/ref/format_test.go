@@ -0,0 +1,63 @@
+package ref
+
+import "testing"
+
+// TestFormat tests formatting references in the built-in styles
+func TestFormat(t *testing.T) {
+	testCases := []struct {
+		input   string
+		style   Style
+		expects string
+	}{
+		{"Jn 1:1-5, 9; Heb 11", Default, "John 1:1–5, 9; Hebrews 11"},
+		{"Jn 1:1-5, 9; Heb 11", USFM, "JHN 1:1-5; JHN 1:9; HEB 11"},
+		{"Jn 1:1-5, 9; Heb 11", Short, "John 1:1-5,9; Heb 11"},
+		{"Psalms 14:1–7; Psalms 53:1–6", Default, "Psalms 14:1–7; 53:1–6"},
+		{"Genesis 1:1–2:3", Default, "Genesis 1:1–2:3"},
+		{"Gen 50:26-Exod 1:4", Short, "Gen 50:26-Exod 1:4"},
+		{"John 3-4", Default, "John 3–4"},
+		{"Genesis-Exodus", USFM, "GEN-EXO"},
+		{"Matt 5:3a-5b", Default, "Matthew 5:3a–5b"},
+		{"Jude 1:3", Default, "Jude 3"},
+		{"Jude 3", USFM, "JUD 1:3"},
+		{"1 Cor 13:4", Style{Book: BookOSIS, ChapterVerse: "."}, "1Cor 13.4"},
+	}
+
+	for _, tc := range testCases {
+		ranges, err := Parse(tc.input)
+		if err != nil {
+			t.Fatalf("Parse(%q) failed: %v", tc.input, err)
+		}
+		if result := Format(ranges, tc.style); result != tc.expects {
+			t.Errorf("Format(%q): expected %q, got %q", tc.input, tc.expects, result)
+		}
+	}
+}
+
+// TestOSIS tests formatting references as OSIS reference lists
+func TestOSIS(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{"John 1:1–5; Hebrews 11", "John.1.1-John.1.5 Heb.11"},
+		{"Matt 5:3a", "Matt.5.3!a"},
+		{"Jude 3", "Jude.1.3"},
+		{"Genesis-Exodus", "Gen-Exod"},
+	}
+
+	for _, tc := range testCases {
+		ranges, err := Parse(tc.input)
+		if err != nil {
+			t.Fatalf("Parse(%q) failed: %v", tc.input, err)
+		}
+		if result := OSIS(ranges); result != tc.expected {
+			t.Errorf("OSIS(%q): expected %q, got %q", tc.input, tc.expected, result)
+		}
+	}
+
+	reference := Reference{Book: "XYZ", Chapter: 2, Verse: 5}
+	if result := reference.String(); result != "XYZ 2:5" {
+		t.Errorf("Expected unknown books to keep their code, got %q", result)
+	}
+}
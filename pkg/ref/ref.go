@@ -0,0 +1,315 @@
+// Package ref parses and formats Scripture references such as "John 3:16",
+// "(John 1:1–5; Hebrews 11:1–3)", or the "1:3" of a footnote.
+//
+// References are parsed into ranges of book, chapter, verse, and verse segment, with book
+// names resolved through the canonical book registry, and can be formatted back in
+// several styles.
+//
+// Example:
+//
+//	ranges, err := ref.Parse("Jn 1:1-5, 9; Heb 11")
+//	if err != nil {
+//		return err
+//	}
+//	fmt.Println(ref.Format(ranges, ref.Default)) // John 1:1–5, 9; Hebrews 11
+//	fmt.Println(ref.Format(ranges, ref.USFM))    // JHN 1:1-5; JHN 1:9; HEB 11
+package ref
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Reference identifies a book, chapter, verse, or part of a verse.
+// Fields that are not given are left at their zero value, so "John 3" has no verse.
+type Reference struct {
+	Book    string `json:"book,omitempty"`    // USFM book code (e.g., "JHN"), or "" if the reference gives no book
+	Chapter int    `json:"chapter,omitempty"` // Chapter number, or 0 for a whole book
+	Verse   int    `json:"verse,omitempty"`   // Verse number, or 0 for a whole chapter
+	Segment string `json:"segment,omitempty"` // Part of the verse such as "a" or "b", or "" for the whole verse
+}
+
+// Range is a passage from Start to End, such as "John 1:1–5" or "Genesis 1:1–2:3".
+// A single reference has a zero End.
+type Range struct {
+	Start Reference `json:"start"`        // First reference of the passage
+	End   Reference `json:"end,omitzero"` // Last reference of the passage, or zero for a single reference
+}
+
+// IsZero reports whether the reference is empty.
+func (r Reference) IsZero() bool {
+	return r == Reference{}
+}
+
+// Compare orders references by canonical book order, chapter, verse, and segment.
+// It returns -1 if r comes before other, +1 if it comes after, and 0 if they are equal.
+// A reference without a verse sorts before the verses of its chapter.
+func (r Reference) Compare(other Reference) int {
+	if r.Book != other.Book {
+		if result := cmp.Compare(bookNumber(r.Book), bookNumber(other.Book)); result != 0 {
+			return result
+		}
+		return cmp.Compare(r.Book, other.Book)
+	}
+	return cmp.Or(
+		cmp.Compare(r.Chapter, other.Chapter),
+		cmp.Compare(r.Verse, other.Verse),
+		cmp.Compare(r.Segment, other.Segment),
+	)
+}
+
+// String formats the reference in the Default style.
+func (r Reference) String() string {
+	return Format([]Range{{Start: r}}, Default)
+}
+
+// Last returns the last reference of the range: End, or Start for a single reference.
+func (r Range) Last() Reference {
+	if r.End.IsZero() {
+		return r.Start
+	}
+	return r.End
+}
+
+// Contains reports whether the range includes a verse or chapter reference.
+// A range that ends with a whole chapter or book includes all of its verses,
+// and verse segments are ignored.
+func (r Range) Contains(reference Reference) bool {
+	if compareStart(reference, r.Start) < 0 {
+		return false
+	}
+	return compareEnd(reference, r.Last()) <= 0
+}
+
+// compareStart compares a reference to the start of a range, where missing chapters and verses
+// stand for the first chapter or verse
+func compareStart(reference, start Reference) int {
+	reference.Segment = ""
+	start.Segment = ""
+	if start.Chapter == 0 {
+		reference.Chapter = 0
+	}
+	if start.Verse == 0 {
+		reference.Verse = 0
+	}
+	return reference.Compare(start)
+}
+
+// compareEnd compares a reference to the end of a range, where missing chapters and verses
+// stand for the last chapter or verse
+func compareEnd(reference, end Reference) int {
+	reference.Segment = ""
+	end.Segment = ""
+	if end.Chapter == 0 {
+		reference.Chapter = 0
+	}
+	if end.Verse == 0 {
+		reference.Verse = 0
+	}
+	return reference.Compare(end)
+}
+
+// String formats the range in the Default style.
+func (r Range) String() string {
+	return Format([]Range{r}, Default)
+}
+
+var (
+	// bookPattern splits a reference into its book name and the chapter and verse that follow
+	bookPattern = regexp.MustCompile(`^((?:[1-4]\s*|[IV]{1,3}\s+)?\p{L}{2}[\p{L}\s.']*?)\s*(\d.*)?$`)
+
+	// numberPattern matches a chapter, chapter and verse, or verse with an optional segment,
+	// such as "3", "3:16", "3.16b", or "5a"
+	numberPattern = regexp.MustCompile(`^(\d+)([a-z])?(?:\s*[:.]\s*(\d+)([a-z])?)?$`)
+
+	// versePrefix matches a "v." or "vv." that marks the numbers after it as verses
+	versePrefix = regexp.MustCompile(`^vv?\.?\s*(\d.*)$`)
+)
+
+// rangeDashes are the characters that separate the start and end of a range
+const rangeDashes = "-–—"
+
+// Parse parses a list of references such as "John 1:1–5; Hebrews 11:1–3".
+// See ParseRelative for the accepted syntax.
+func Parse(text string) ([]Range, error) {
+	return ParseRelative(text, Reference{})
+}
+
+// ParseRelative parses a list of references that may leave out the book or chapter,
+// such as the "1:3" of a footnote, taking them from context.
+//
+// The text may be enclosed in parentheses or brackets. References are separated by ";" or ",",
+// and each is a book, chapter, or verse with an optional verse segment ("5a"), or a range of
+// them joined by a hyphen, en dash, or em dash. Chapter and verse are separated by ":" or ".".
+// A reference that leaves out the book continues the previous book; a bare number after ","
+// continues the verses of the previous chapter, while after ";" it is a new chapter.
+// Books with a single chapter take bare numbers as verses ("Jude 3").
+//
+// Examples of accepted references:
+//
+//	John 3:16
+//	(John 1:1–5; Hebrews 11:1–3)
+//	Genesis 1:1–2:3
+//	Ps 23; 24, 27
+//	1 Cor 13:4-7, 13
+//	Matt 5:3a
+//	1.4             (with a context book and chapter)
+func ParseRelative(text string, context Reference) ([]Range, error) {
+	text = strings.TrimSpace(text)
+	text = strings.TrimSuffix(text, ".")
+	if len(text) >= 2 && (text[0] == '(' && text[len(text)-1] == ')' || text[0] == '[' && text[len(text)-1] == ']') {
+		text = strings.TrimSpace(text[1 : len(text)-1])
+	}
+
+	p := referenceParser{book: context.Book, chapter: context.Chapter}
+	var ranges []Range
+	for _, group := range strings.Split(text, ";") {
+		p.verses = singleChapterBooks[p.book]
+		for _, item := range strings.Split(group, ",") {
+			item = strings.TrimSpace(item)
+			if item == "" {
+				continue
+			}
+			r, err := p.parseRange(item)
+			if err != nil {
+				return nil, fmt.Errorf("invalid reference %q: %w", item, err)
+			}
+			ranges = append(ranges, r)
+		}
+	}
+
+	if len(ranges) == 0 {
+		return nil, fmt.Errorf("no reference found in %q", text)
+	}
+	return ranges, nil
+}
+
+// referenceParser holds the book and chapter that later references in a list continue
+type referenceParser struct {
+	book    string // Current book code
+	chapter int    // Current chapter
+	verses  bool   // Whether a bare number is a verse of the current chapter rather than a chapter
+}
+
+// parseRange parses a single reference or range
+func (p *referenceParser) parseRange(item string) (Range, error) {
+	if match := versePrefix.FindStringSubmatch(item); match != nil {
+		item = match[1]
+		p.verses = true
+	}
+
+	startText, endText, isRange := item, "", false
+	if i := strings.IndexAny(item, rangeDashes); i >= 0 {
+		startText, endText, isRange = item[:i], strings.TrimLeft(item[i:], rangeDashes), true
+	}
+
+	start, err := p.parseReference(strings.TrimSpace(startText))
+	if err != nil {
+		return Range{}, err
+	}
+	if !isRange {
+		return Range{Start: start}, nil
+	}
+
+	end, err := p.parseEnd(strings.TrimSpace(endText), start)
+	if err != nil {
+		return Range{}, err
+	}
+	if end.Compare(start) <= 0 {
+		return Range{}, fmt.Errorf("range ends before it starts")
+	}
+	return Range{Start: start, End: end}, nil
+}
+
+// parseReference parses a reference that starts a range, updating the current book and chapter
+func (p *referenceParser) parseReference(text string) (Reference, error) {
+	if text == "" {
+		return Reference{}, fmt.Errorf("missing reference")
+	}
+
+	numbers := text
+	if match := bookPattern.FindStringSubmatch(text); match != nil {
+		code, err := ResolveBook(match[1])
+		if err != nil {
+			return Reference{}, err
+		}
+		p.book = code
+		p.chapter = 0
+		p.verses = singleChapterBooks[code]
+		if singleChapterBooks[code] {
+			p.chapter = 1
+		}
+		numbers = match[2]
+		if numbers == "" {
+			return Reference{Book: code}, nil
+		}
+	}
+
+	chapter, verse, segment, err := p.parseNumbers(numbers)
+	if err != nil {
+		return Reference{}, err
+	}
+	p.chapter = chapter
+	p.verses = verse > 0
+	return Reference{Book: p.book, Chapter: chapter, Verse: verse, Segment: segment}, nil
+}
+
+// parseEnd parses the end of a range, which continues the book and chapter of its start
+func (p *referenceParser) parseEnd(text string, start Reference) (Reference, error) {
+	if text == "" {
+		return Reference{}, fmt.Errorf("missing end of range")
+	}
+	if bookPattern.MatchString(text) {
+		return p.parseReference(text)
+	}
+	if start.Chapter == 0 {
+		return Reference{}, fmt.Errorf("range from a whole book must end with a book")
+	}
+
+	p.verses = start.Verse > 0
+	chapter, verse, segment, err := p.parseNumbers(text)
+	if err != nil {
+		return Reference{}, err
+	}
+	p.chapter = chapter
+	p.verses = verse > 0
+	return Reference{Book: start.Book, Chapter: chapter, Verse: verse, Segment: segment}, nil
+}
+
+// parseNumbers parses the chapter and verse part of a reference in the current context
+func (p *referenceParser) parseNumbers(text string) (int, int, string, error) {
+	match := numberPattern.FindStringSubmatch(text)
+	if match == nil {
+		return 0, 0, "", fmt.Errorf("expected a chapter or verse number, got %q", text)
+	}
+	first, _ := strconv.Atoi(match[1])
+	if first == 0 {
+		return 0, 0, "", fmt.Errorf("chapter and verse numbers start at 1")
+	}
+
+	// Chapter and verse
+	if match[3] != "" {
+		verse, _ := strconv.Atoi(match[3])
+		if match[2] != "" || verse == 0 {
+			return 0, 0, "", fmt.Errorf("invalid chapter and verse %q", text)
+		}
+		return first, verse, match[4], nil
+	}
+
+	// A verse of the current chapter
+	if p.verses {
+		if p.chapter == 0 {
+			return 0, 0, "", fmt.Errorf("verse %s has no chapter", text)
+		}
+		return p.chapter, first, match[2], nil
+	}
+
+	// A whole chapter
+	if match[2] != "" {
+		return 0, 0, "", fmt.Errorf("chapter %s cannot have a verse segment", text)
+	}
+	return first, 0, "", nil
+}
@@ -0,0 +1,182 @@
+package ref
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// verse builds a verse reference
+func verse(book string, chapter, number int) Reference {
+	return Reference{Book: book, Chapter: chapter, Verse: number}
+}
+
+// TestParse tests parsing reference lists
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected []Range
+	}{
+		{"John 3:16", []Range{{Start: verse("JHN", 3, 16)}}},
+		{"(John 1:1–5; Hebrews 11:1–3)", []Range{
+			{Start: verse("JHN", 1, 1), End: verse("JHN", 1, 5)},
+			{Start: verse("HEB", 11, 1), End: verse("HEB", 11, 3)},
+		}},
+		{"Genesis 1:1—2:3", []Range{{Start: verse("GEN", 1, 1), End: verse("GEN", 2, 3)}}},
+		{"Gen 50:26 - Exod 1:4", []Range{{Start: verse("GEN", 50, 26), End: verse("EXO", 1, 4)}}},
+		{"John 3", []Range{{Start: Reference{Book: "JHN", Chapter: 3}}}},
+		{"John 3-4", []Range{{Start: Reference{Book: "JHN", Chapter: 3}, End: Reference{Book: "JHN", Chapter: 4}}}},
+		{"Genesis–Exodus", []Range{{Start: Reference{Book: "GEN"}, End: Reference{Book: "EXO"}}}},
+		{"1 Cor 13:4-7, 13", []Range{
+			{Start: verse("1CO", 13, 4), End: verse("1CO", 13, 7)},
+			{Start: verse("1CO", 13, 13)},
+		}},
+		{"Ps 23; 24:1, 3", []Range{
+			{Start: Reference{Book: "PSA", Chapter: 23}},
+			{Start: verse("PSA", 24, 1)},
+			{Start: verse("PSA", 24, 3)},
+		}},
+		{"Matt 5:3a-5b", []Range{{
+			Start: Reference{Book: "MAT", Chapter: 5, Verse: 3, Segment: "a"},
+			End:   Reference{Book: "MAT", Chapter: 5, Verse: 5, Segment: "b"},
+		}}},
+		{"Jude 3", []Range{{Start: verse("JUD", 1, 3)}}},
+		{"Jude 1:3-5", []Range{{Start: verse("JUD", 1, 3), End: verse("JUD", 1, 5)}}},
+		{"II Kings 2.11", []Range{{Start: verse("2KI", 2, 11)}}},
+		{"JHN 1:1; ROM 8:28.", []Range{{Start: verse("JHN", 1, 1)}, {Start: verse("ROM", 8, 28)}}},
+	}
+
+	for _, tc := range testCases {
+		result, err := Parse(tc.input)
+		if err != nil {
+			t.Errorf("Parse(%q) failed: %v", tc.input, err)
+			continue
+		}
+		if !reflect.DeepEqual(result, tc.expected) {
+			t.Errorf("Parse(%q): expected %+v, got %+v", tc.input, tc.expected, result)
+		}
+	}
+}
+
+// TestParseRelative tests references that take their book and chapter from context
+func TestParseRelative(t *testing.T) {
+	context := Reference{Book: "GEN", Chapter: 1}
+
+	testCases := []struct {
+		input    string
+		expected []Range
+	}{
+		{"1:3", []Range{{Start: verse("GEN", 1, 3)}}},
+		{"1.4", []Range{{Start: verse("GEN", 1, 4)}}},
+		{"v. 5", []Range{{Start: verse("GEN", 1, 5)}}},
+		{"vv. 5–7", []Range{{Start: verse("GEN", 1, 5), End: verse("GEN", 1, 7)}}},
+		{"2:1, 4", []Range{{Start: verse("GEN", 2, 1)}, {Start: verse("GEN", 2, 4)}}},
+		{"John 3:16", []Range{{Start: verse("JHN", 3, 16)}}},
+	}
+
+	for _, tc := range testCases {
+		result, err := ParseRelative(tc.input, context)
+		if err != nil {
+			t.Errorf("ParseRelative(%q) failed: %v", tc.input, err)
+			continue
+		}
+		if !reflect.DeepEqual(result, tc.expected) {
+			t.Errorf("ParseRelative(%q): expected %+v, got %+v", tc.input, tc.expected, result)
+		}
+	}
+}
+
+// TestParseErrors tests that malformed references are rejected
+func TestParseErrors(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{"", "no reference found"},
+		{"Hezekiah 1:1", "unknown book"},
+		{"Jo 3:16", "ambiguous book"},
+		{"John 3:16-14", "range ends before it starts"},
+		{"John 3:16-", "missing end of range"},
+		{"John 3:0", "invalid chapter and verse"},
+		{"John 3:x", "expected a chapter or verse number"},
+		{"v. 5", "has no chapter"},
+		{"John 3a", "cannot have a verse segment"},
+	}
+
+	for _, tc := range testCases {
+		_, err := Parse(tc.input)
+		if err == nil || !strings.Contains(err.Error(), tc.expected) {
+			t.Errorf("Parse(%q): expected error containing %q, got %v", tc.input, tc.expected, err)
+		}
+	}
+}
+
+// TestResolveBook tests resolving book names and abbreviations
+func TestResolveBook(t *testing.T) {
+	testCases := []struct {
+		name     string
+		expected string
+	}{
+		{"Genesis", "GEN"},
+		{"gen", "GEN"},
+		{"Gen.", "GEN"},
+		{"1 Samuel", "1SA"},
+		{"1Sam", "1SA"},
+		{"I Samuel", "1SA"},
+		{"First Samuel", "1SA"},
+		{"Psalm", "PSA"},
+		{"Song of Solomon", "SNG"},
+		{"Phil", "PHP"},
+		{"Philem", "PHM"},
+		{"Matt", "MAT"},
+		{"Mk", "MRK"},
+		{"Rev", "REV"},
+		{"JUD", "JUD"},
+		{"Esther (Greek)", "ESG"},
+	}
+
+	for _, tc := range testCases {
+		code, err := ResolveBook(tc.name)
+		if err != nil {
+			t.Errorf("ResolveBook(%q) failed: %v", tc.name, err)
+		} else if code != tc.expected {
+			t.Errorf("ResolveBook(%q): expected %s, got %s", tc.name, tc.expected, code)
+		}
+	}
+}
+
+// TestCompareAndContains tests ordering references and matching them against ranges
+func TestCompareAndContains(t *testing.T) {
+	if verse("GEN", 50, 26).Compare(verse("EXO", 1, 1)) >= 0 {
+		t.Error("Expected Genesis to come before Exodus")
+	}
+	if verse("JHN", 3, 16).Compare(Reference{Book: "JHN", Chapter: 3}) <= 0 {
+		t.Error("Expected a verse to come after its chapter")
+	}
+
+	testCases := []struct {
+		input     string
+		reference Reference
+		expected  bool
+	}{
+		{"John 3:16-18", verse("JHN", 3, 17), true},
+		{"John 3:16-18", verse("JHN", 3, 19), false},
+		{"John 3:16", Reference{Book: "JHN", Chapter: 3, Verse: 16, Segment: "b"}, true},
+		{"John 3", verse("JHN", 3, 36), true},
+		{"John 3-4", verse("JHN", 4, 54), true},
+		{"John 3-4", verse("JHN", 5, 1), false},
+		{"Genesis 50:20-Exodus 1:4", verse("EXO", 1, 1), true},
+		{"Genesis", verse("GEN", 12, 1), true},
+		{"Genesis", verse("EXO", 1, 1), false},
+	}
+
+	for _, tc := range testCases {
+		ranges, err := Parse(tc.input)
+		if err != nil {
+			t.Fatalf("Parse(%q) failed: %v", tc.input, err)
+		}
+		if result := ranges[0].Contains(tc.reference); result != tc.expected {
+			t.Errorf("%s contains %+v: expected %v, got %v", tc.input, tc.reference, tc.expected, result)
+		}
+	}
+}
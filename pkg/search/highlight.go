@@ -0,0 +1,136 @@
+package search
+
+import (
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// highlights finds the words of a verse text that match the words and phrases a query
+// looks for. Excluded words are not highlighted.
+func highlights(text string, query *node) []Span {
+	tokens := Tokenize(text)
+	var spans []Span
+
+	var visit func(n *node)
+	visit = func(n *node) {
+		switch n.kind {
+		case nodeTerm:
+			for _, token := range tokens {
+				if token.Term == n.terms[0] || n.prefix && strings.HasPrefix(token.Term, n.terms[0]) {
+					spans = append(spans, Span{Start: token.Start, End: token.End})
+				}
+			}
+		case nodePhrase:
+			for i := 0; i+len(n.terms) <= len(tokens); i++ {
+				if phraseAt(tokens, i, n.terms) {
+					spans = append(spans, Span{Start: tokens[i].Start, End: tokens[i+len(n.terms)-1].End})
+				}
+			}
+		case nodeAnd, nodeOr:
+			for _, child := range n.children {
+				visit(child)
+			}
+		}
+	}
+	visit(query)
+
+	// Merge overlapping spans, such as a word that is also part of a phrase
+	slices.SortFunc(spans, func(a, b Span) int { return a.Start - b.Start })
+	var merged []Span
+	for _, span := range spans {
+		if n := len(merged); n > 0 && span.Start <= merged[n-1].End {
+			merged[n-1].End = max(merged[n-1].End, span.End)
+		} else {
+			merged = append(merged, span)
+		}
+	}
+	return merged
+}
+
+// phraseAt reports whether the terms of a phrase start at token i
+func phraseAt(tokens []Token, i int, terms []string) bool {
+	for j, term := range terms {
+		if tokens[i+j].Term != term {
+			return false
+		}
+	}
+	return true
+}
+
+// Snippet returns the verse text with the matched words between before and after
+// (e.g., "**" and "**", or ANSI color codes). Texts longer than width bytes are cut
+// at word boundaries around the first match, or between characters in text without
+// spaces, with "…" marking what was left out; the first match is never cut, even if it
+// is longer than width. A width of 0 keeps the whole text.
+//
+// Example:
+//
+//	result.Snippet(80, "[", "]") // …you would have asked Him, and He would have given you [living water].
+func (r Result) Snippet(width int, before, after string) string {
+	text := r.Text
+	start, end := 0, len(text)
+
+	if width > 0 && len(text) > width {
+		focus := 0
+		if len(r.Highlights) > 0 {
+			focus = r.Highlights[0].Start
+		}
+
+		// Start a third of the width before the first match, then fill the width
+		start = max(0, focus-width/3)
+		end = min(len(text), start+width)
+		if end == len(text) {
+			start = max(0, end-width)
+		}
+		if start > 0 {
+			if space := strings.IndexByte(text[start:], ' '); space >= 0 && start+space < focus {
+				start += space + 1
+			}
+		}
+		if end < len(text) {
+			if space := strings.LastIndexByte(text[start:end], ' '); space > 0 {
+				end = start + space
+			}
+		}
+
+		// Without spaces to cut at, cut between characters, and never inside a match:
+		// the first match is kept whole and a later one is left out if it does not fit
+		for start > 0 && !utf8.RuneStart(text[start]) {
+			start++
+		}
+		for end < len(text) && !utf8.RuneStart(text[end]) {
+			end--
+		}
+		for i, span := range r.Highlights {
+			if span.Start < end && end < span.End {
+				if i == 0 {
+					end = span.End
+				} else {
+					end = span.Start
+				}
+			}
+		}
+	}
+
+	var result strings.Builder
+	if start > 0 {
+		result.WriteString("…")
+	}
+	position := start
+	for _, span := range r.Highlights {
+		if span.End <= start || span.Start >= end {
+			continue
+		}
+		spanStart, spanEnd := max(span.Start, start), min(span.End, end)
+		result.WriteString(text[position:spanStart])
+		result.WriteString(before + text[spanStart:spanEnd] + after)
+		position = spanEnd
+	}
+	result.WriteString(text[position:end])
+	if end < len(text) {
+		result.WriteString("…")
+	}
+
+	return result.String()
+}
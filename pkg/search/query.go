@@ -0,0 +1,237 @@
+package search
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// nodeKind is the kind of a query node
+type nodeKind int
+
+const (
+	nodeTerm   nodeKind = iota // A word, or a word prefix ending in "*"
+	nodePhrase                 // Words that must appear next to each other in order
+	nodeAnd                    // All children must match
+	nodeOr                     // Any child must match
+	nodeNot                    // The child must not match
+)
+
+// node is a parsed query
+type node struct {
+	kind     nodeKind
+	terms    []string // Normalized words of a term or phrase
+	prefix   bool     // Whether a term matches any word that starts with it
+	children []*node
+}
+
+// queryItem is a lexical item of a query: a word, a quoted phrase, or a parenthesis
+type queryItem struct {
+	text   string
+	phrase bool
+}
+
+// parseQuery parses a search query.
+//
+// Words separated by spaces must all appear in a verse. Quoted words ("living water") must
+// appear together in order, and a word ending in "*" matches any word that starts with it.
+// OR, AND, and NOT (in capitals) combine words and phrases, "-" before a word or phrase
+// excludes it, and parentheses group; AND binds more tightly than OR.
+func parseQuery(text string) (*node, error) {
+	items, err := lexQuery(text)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("empty query")
+	}
+
+	parser := &queryParser{items: items}
+	query, err := parser.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if parser.pos < len(parser.items) {
+		return nil, fmt.Errorf("unexpected %q", parser.items[parser.pos].text)
+	}
+	return query, nil
+}
+
+// lexQuery splits a query into words, quoted phrases, and parentheses,
+// with "-" before a word or phrase written as NOT
+func lexQuery(text string) ([]queryItem, error) {
+	var items []queryItem
+	runes := []rune(text)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(' || r == ')':
+			items = append(items, queryItem{text: string(r)})
+			i++
+		case r == '"' || r == '“' || r == '”':
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' && runes[end] != '”' && runes[end] != '“' {
+				end++
+			}
+			if end == len(runes) {
+				return nil, fmt.Errorf("unterminated phrase")
+			}
+			items = append(items, queryItem{text: string(runes[i+1 : end]), phrase: true})
+			i = end + 1
+		case r == '-' && i+1 < len(runes) && !unicode.IsSpace(runes[i+1]):
+			items = append(items, queryItem{text: "NOT"})
+			i++
+		default:
+			end := i
+			for end < len(runes) && !unicode.IsSpace(runes[end]) && !strings.ContainsRune("()\"“”", runes[end]) {
+				end++
+			}
+			items = append(items, queryItem{text: string(runes[i:end])})
+			i = end
+		}
+	}
+
+	return items, nil
+}
+
+// queryParser parses query items by recursive descent
+type queryParser struct {
+	items []queryItem
+	pos   int
+}
+
+// peek returns the operator or parenthesis at the current position, or ""
+func (p *queryParser) peek() string {
+	if p.pos >= len(p.items) || p.items[p.pos].phrase {
+		return ""
+	}
+	switch text := p.items[p.pos].text; text {
+	case "AND", "OR", "NOT", "(", ")":
+		return text
+	}
+	return ""
+}
+
+// parseOr parses alternatives separated by OR
+func (p *queryParser) parseOr() (*node, error) {
+	var children []*node
+	for {
+		child, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, child)
+		if p.peek() != "OR" {
+			break
+		}
+		p.pos++
+	}
+
+	if len(children) == 1 {
+		return children[0], nil
+	}
+	return &node{kind: nodeOr, children: children}, nil
+}
+
+// parseAnd parses words, phrases, and groups that must all match
+func (p *queryParser) parseAnd() (*node, error) {
+	var children []*node
+	for p.pos < len(p.items) {
+		switch p.peek() {
+		case "OR", ")":
+			if len(children) == 0 {
+				return nil, fmt.Errorf("expected a word or phrase before %q", p.items[p.pos].text)
+			}
+			return andNode(children), nil
+		case "AND":
+			if len(children) == 0 {
+				return nil, fmt.Errorf("expected a word or phrase before \"AND\"")
+			}
+			p.pos++
+			if p.pos == len(p.items) {
+				return nil, fmt.Errorf("expected a word or phrase after \"AND\"")
+			}
+		}
+
+		child, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, child)
+	}
+
+	if len(children) == 0 {
+		return nil, fmt.Errorf("expected a word or phrase at the end of the query")
+	}
+	return andNode(children), nil
+}
+
+// andNode combines the children of an AND, leaving a single child as it is
+func andNode(children []*node) *node {
+	if len(children) == 1 {
+		return children[0]
+	}
+	return &node{kind: nodeAnd, children: children}
+}
+
+// parseUnary parses a word, phrase, group, or an excluded one
+func (p *queryParser) parseUnary() (*node, error) {
+	switch p.peek() {
+	case "NOT":
+		p.pos++
+		if p.pos == len(p.items) {
+			return nil, fmt.Errorf("expected a word or phrase after NOT")
+		}
+		child, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &node{kind: nodeNot, children: []*node{child}}, nil
+
+	case "(":
+		p.pos++
+		child, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		p.pos++
+		return child, nil
+	}
+
+	item := p.items[p.pos]
+	p.pos++
+	return wordNode(item)
+}
+
+// wordNode builds the node of a word or phrase. A word that is made up of several
+// words, such as "well-watered", is searched for as a phrase.
+func wordNode(item queryItem) (*node, error) {
+	text := item.text
+	prefix := !item.phrase && strings.HasSuffix(text, "*")
+	if prefix {
+		text = strings.TrimSuffix(text, "*")
+	}
+
+	tokens := Tokenize(text)
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("no words to search for in %q", item.text)
+	}
+
+	terms := make([]string, len(tokens))
+	for i, token := range tokens {
+		terms[i] = token.Term
+	}
+	if len(terms) == 1 {
+		return &node{kind: nodeTerm, terms: terms, prefix: prefix}, nil
+	}
+	if prefix {
+		return nil, fmt.Errorf("a prefix search (%q) must be a single word", item.text)
+	}
+	return &node{kind: nodePhrase, terms: terms}, nil
+}
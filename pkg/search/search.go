@@ -0,0 +1,376 @@
+// Package search finds verses by the words of their text.
+//
+// An Index is built from parsed USFM documents, after character markup and footnotes
+// have been removed, so searches never match inside markup. Words are matched without
+// regard to case or diacritics, queries can combine words, phrases, and prefixes with
+// AND, OR, and NOT, and results can be limited to books or passages. An index can be
+// saved to disk and loaded again instead of parsing the USFM files for every search.
+//
+// Example:
+//
+//	index := search.Build(bible)
+//	results, err := index.Search(`"living water" OR "springs of water"`, search.Options{})
+//	if err != nil {
+//		return err
+//	}
+//	for _, result := range results {
+//		fmt.Println(result.Range, result.Snippet(120, "*", "*"))
+//	}
+package search
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/arenzana/usfmp/pkg/ref"
+	"github.com/arenzana/usfmp/pkg/usfm"
+)
+
+// Verse is a verse, or a verse bridge, of an indexed translation.
+type Verse struct {
+	Translation string    `json:"translation"` // Name of the translation (see usfm.Bible)
+	Range       ref.Range `json:"reference"`   // Verse, or first and last verse of a verse bridge
+	Text        string    `json:"text"`        // Verse text without markup or footnotes
+}
+
+// posting lists the positions of a term within a verse
+type posting struct {
+	Verse     int   // Index of the verse in Index.verses
+	Positions []int // Word positions of the term in the verse
+}
+
+// Index is an inverted index from the words of verse text to the verses that contain them.
+// Searches may run concurrently, but not while verses are being added.
+type Index struct {
+	verses []Verse
+	terms  map[string][]posting
+	sorted []string // Terms in lexical order, for prefix searches
+}
+
+// Options limits the verses a search returns.
+type Options struct {
+	Ranges       []ref.Range // Only return verses within these books or passages; all verses if empty
+	Translations []string    // Only return verses of these translations; all translations if empty
+}
+
+// Span is the position of a matched word in a verse text.
+type Span struct {
+	Start int `json:"start"` // Byte offset of the first matched word
+	End   int `json:"end"`   // Byte offset just past the last matched word
+}
+
+// Result is a verse that matches a query.
+type Result struct {
+	Verse
+	Highlights []Span `json:"highlights"` // Words of the verse that match the query, in order
+}
+
+// Build indexes the verses of translations.
+func Build(bibles ...*usfm.Bible) *Index {
+	index := NewIndex()
+	for _, bible := range bibles {
+		index.Add(bible.Name, bible.Documents...)
+	}
+	return index
+}
+
+// NewIndex returns an empty index.
+func NewIndex() *Index {
+	return &Index{terms: make(map[string][]posting)}
+}
+
+// Add indexes the verses of documents as part of the named translation.
+// Documents without an \id book code are skipped.
+func (ix *Index) Add(translation string, documents ...*usfm.Document) {
+	for _, doc := range documents {
+		book := doc.BookCode()
+		if book == "" {
+			continue
+		}
+
+		for _, chapter := range doc.Chapters {
+			for _, section := range chapter.Sections {
+				for _, verse := range section.Verses {
+					r := ref.Range{Start: ref.Reference{Book: book, Chapter: chapter.Number, Verse: verse.Number}}
+					if verse.EndNumber > 0 {
+						r.End = ref.Reference{Book: book, Chapter: chapter.Number, Verse: verse.EndNumber}
+					}
+					ix.addVerse(Verse{Translation: translation, Range: r, Text: usfm.PlainText(verse.Text)})
+				}
+			}
+		}
+	}
+	ix.sortTerms()
+}
+
+// addVerse adds a verse and the positions of its words
+func (ix *Index) addVerse(verse Verse) {
+	id := len(ix.verses)
+	ix.verses = append(ix.verses, verse)
+
+	for position, token := range Tokenize(verse.Text) {
+		postings := ix.terms[token.Term]
+		if n := len(postings); n > 0 && postings[n-1].Verse == id {
+			postings[n-1].Positions = append(postings[n-1].Positions, position)
+		} else {
+			postings = append(postings, posting{Verse: id, Positions: []int{position}})
+		}
+		ix.terms[token.Term] = postings
+	}
+}
+
+// Len returns the number of indexed verses.
+func (ix *Index) Len() int {
+	return len(ix.verses)
+}
+
+// Translations returns the names of the indexed translations, in the order they were added.
+func (ix *Index) Translations() []string {
+	var names []string
+	for _, verse := range ix.verses {
+		if !slices.Contains(names, verse.Translation) {
+			names = append(names, verse.Translation)
+		}
+	}
+	return names
+}
+
+// Search returns the verses that match a query, in the order they were indexed
+// (translation by translation, in canonical order).
+//
+// Words separated by spaces must all appear in a verse. Quoted words must appear together
+// in order, and a word ending in "*" matches any word that starts with it. OR, AND, and NOT
+// (in capitals) combine words and phrases, "-" before a word or phrase excludes it, and
+// parentheses group; AND binds more tightly than OR:
+//
+//	living water          both words, anywhere in the verse
+//	"living water"        the phrase
+//	shepherd*             shepherd, shepherds, shepherded, ...
+//	water -"living water" water, but not the phrase
+//	(lamb OR sheep) AND NOT goat
+func (ix *Index) Search(query string, options Options) ([]Result, error) {
+	root, err := parseQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []Result
+	for _, id := range ix.match(root) {
+		verse := ix.verses[id]
+		if !options.includes(verse) {
+			continue
+		}
+		results = append(results, Result{Verse: verse, Highlights: highlights(verse.Text, root)})
+	}
+	return results, nil
+}
+
+// includes reports whether the options allow a verse
+func (o Options) includes(verse Verse) bool {
+	if len(o.Translations) > 0 && !slices.Contains(o.Translations, verse.Translation) {
+		return false
+	}
+	if len(o.Ranges) == 0 {
+		return true
+	}
+	for _, r := range o.Ranges {
+		for number := verse.Range.Start.Verse; number <= verse.Range.Last().Verse; number++ {
+			reference := verse.Range.Start
+			reference.Verse = number
+			if r.Contains(reference) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// match returns the sorted ids of the verses that match a query node
+func (ix *Index) match(n *node) []int {
+	switch n.kind {
+	case nodeTerm:
+		if n.prefix {
+			var ids []int
+			for _, term := range ix.prefixTerms(n.terms[0]) {
+				ids = union(ids, verseIDs(ix.terms[term]))
+			}
+			return ids
+		}
+		return verseIDs(ix.terms[n.terms[0]])
+
+	case nodePhrase:
+		return ix.matchPhrase(n.terms)
+
+	case nodeAnd:
+		// Excluded children are removed from the verses matched by the others
+		var ids []int
+		var excluded [][]int
+		first := true
+		for _, child := range n.children {
+			if child.kind == nodeNot {
+				excluded = append(excluded, ix.match(child.children[0]))
+				continue
+			}
+			if first {
+				ids, first = ix.match(child), false
+			} else {
+				ids = intersect(ids, ix.match(child))
+			}
+		}
+		if first {
+			ids = ix.all()
+		}
+		for _, ex := range excluded {
+			ids = difference(ids, ex)
+		}
+		return ids
+
+	case nodeOr:
+		var ids []int
+		for _, child := range n.children {
+			ids = union(ids, ix.match(child))
+		}
+		return ids
+
+	case nodeNot:
+		return difference(ix.all(), ix.match(n.children[0]))
+	}
+	return nil
+}
+
+// matchPhrase returns the verses in which the terms appear one after another
+func (ix *Index) matchPhrase(terms []string) []int {
+	lists := make([][]posting, len(terms))
+	for i, term := range terms {
+		lists[i] = ix.terms[term]
+		if len(lists[i]) == 0 {
+			return nil
+		}
+	}
+
+	var ids []int
+	for _, first := range lists[0] {
+		// Positions of the phrase's first word that the following words continue
+		starts := first.Positions
+		for i := 1; i < len(terms) && len(starts) > 0; i++ {
+			p, ok := findPosting(lists[i], first.Verse)
+			if !ok {
+				starts = nil
+				break
+			}
+			var next []int
+			for _, start := range starts {
+				if _, found := slices.BinarySearch(p.Positions, start+i); found {
+					next = append(next, start)
+				}
+			}
+			starts = next
+		}
+		if len(starts) > 0 {
+			ids = append(ids, first.Verse)
+		}
+	}
+	return ids
+}
+
+// findPosting finds the posting of a verse in a list sorted by verse
+func findPosting(postings []posting, verse int) (posting, bool) {
+	i := sort.Search(len(postings), func(i int) bool { return postings[i].Verse >= verse })
+	if i < len(postings) && postings[i].Verse == verse {
+		return postings[i], true
+	}
+	return posting{}, false
+}
+
+// sortTerms lists the indexed terms in lexical order
+func (ix *Index) sortTerms() {
+	ix.sorted = make([]string, 0, len(ix.terms))
+	for term := range ix.terms {
+		ix.sorted = append(ix.sorted, term)
+	}
+	slices.Sort(ix.sorted)
+}
+
+// prefixTerms returns the indexed terms that start with a prefix
+func (ix *Index) prefixTerms(prefix string) []string {
+	start, _ := slices.BinarySearch(ix.sorted, prefix)
+	end := start
+	for end < len(ix.sorted) && strings.HasPrefix(ix.sorted[end], prefix) {
+		end++
+	}
+	return ix.sorted[start:end]
+}
+
+// all returns the ids of every verse
+func (ix *Index) all() []int {
+	ids := make([]int, len(ix.verses))
+	for i := range ids {
+		ids[i] = i
+	}
+	return ids
+}
+
+// verseIDs returns the verses of a posting list
+func verseIDs(postings []posting) []int {
+	ids := make([]int, len(postings))
+	for i, p := range postings {
+		ids[i] = p.Verse
+	}
+	return ids
+}
+
+// intersect returns the ids in both sorted lists
+func intersect(a, b []int) []int {
+	var result []int
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case a[i] > b[j]:
+			j++
+		default:
+			result = append(result, a[i])
+			i++
+			j++
+		}
+	}
+	return result
+}
+
+// union returns the ids in either sorted list
+func union(a, b []int) []int {
+	result := make([]int, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] < b[j]:
+			result = append(result, a[i])
+			i++
+		case a[i] > b[j]:
+			result = append(result, b[j])
+			j++
+		default:
+			result = append(result, a[i])
+			i++
+			j++
+		}
+	}
+	result = append(result, a[i:]...)
+	return append(result, b[j:]...)
+}
+
+// difference returns the ids of the first sorted list that are not in the second
+func difference(a, b []int) []int {
+	var result []int
+	j := 0
+	for _, id := range a {
+		for j < len(b) && b[j] < id {
+			j++
+		}
+		if j == len(b) || b[j] != id {
+			result = append(result, id)
+		}
+	}
+	return result
+}
@@ -0,0 +1,249 @@
+package search
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/arenzana/usfmp/pkg/ref"
+	"github.com/arenzana/usfmp/pkg/usfm"
+)
+
+// buildTestIndex indexes two short books
+func buildTestIndex(t *testing.T) *Index {
+	t.Helper()
+
+	books := []string{`\id JHN - Test Bible
+\c 4
+\v 10 Jesus answered, “If you knew the gift of God, He would have given you \add living\add* water.”
+\v 11 “Sir,” the woman replied, “where then will You get this living water?”
+\v 12 Are You greater than our father Jacob, who gave us the well?\f + \fr 4:12 \ft A note about water.\f*`,
+		`\id REV - Test Bible
+\c 7
+\v 17 For the Lamb will shepherd them; He will lead them to springs of living water.
+\c 22
+\v 1-2 Then the angel showed me the river of the water of life, bright as crystal. Élie’s shepherds.`,
+	}
+
+	var documents []*usfm.Document
+	for _, book := range books {
+		doc, err := usfm.NewParser(usfm.DefaultParseOptions()).Parse(strings.NewReader(book), "test.sfm")
+		if err != nil {
+			t.Fatalf("Parse failed: %v", err)
+		}
+		documents = append(documents, doc)
+	}
+
+	index := NewIndex()
+	index.Add("test", documents...)
+	return index
+}
+
+// resultReferences lists the references of search results
+func resultReferences(results []Result) string {
+	references := make([]string, len(results))
+	for i, result := range results {
+		references[i] = result.Range.String()
+	}
+	return strings.Join(references, "; ")
+}
+
+// TestTokenize tests splitting and normalizing words
+func TestTokenize(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{"God's well-watered garden.", "god s well watered garden"},
+		{"“Sir,” the woman replied", "sir the woman replied"},
+		{"ÉLIE Naïve CAFÉ œuvre Straße", "elie naive cafe oeuvre strasse"},
+		{"Ἐν ἀρχῇ ἦν ὁ λόγος", "εν αρχη ην ο λογοσ"},
+		{"בְּרֵאשִׁית", "בראשית"},
+		{"'quoted' 1:2", "quoted 1 2"},
+	}
+
+	for _, tc := range testCases {
+		var terms []string
+		for _, token := range Tokenize(tc.input) {
+			terms = append(terms, token.Term)
+		}
+		if result := strings.Join(terms, " "); result != tc.expected {
+			t.Errorf("Tokenize(%q): expected %q, got %q", tc.input, tc.expected, result)
+		}
+	}
+
+	tokens := Tokenize("the woman’s well")
+	if len(tokens) != 4 || tokens[1].Start != 4 || tokens[1].End != 9 || tokens[2].Start != 12 {
+		t.Errorf("Unexpected token offsets %+v", tokens)
+	}
+}
+
+// TestSearch tests word, phrase, prefix, and boolean queries
+func TestSearch(t *testing.T) {
+	index := buildTestIndex(t)
+
+	testCases := []struct {
+		query    string
+		expected string
+	}{
+		{"living water", "John 4:10; John 4:11; Revelation 7:17"},
+		{"LIVING WATER", "John 4:10; John 4:11; Revelation 7:17"},
+		{`"living water"`, "John 4:10; John 4:11; Revelation 7:17"},
+		{`"water living"`, ""},
+		{"water", "John 4:10; John 4:11; Revelation 7:17; Revelation 22:1–2"},
+		{"shepherd*", "Revelation 7:17; Revelation 22:1–2"},
+		{"elie", "Revelation 22:1–2"},
+		{`"Élie's shepherds"`, "Revelation 22:1–2"},
+		{"woman OR lamb", "John 4:11; Revelation 7:17"},
+		{"water -living", "Revelation 22:1–2"},
+		{"water AND NOT (woman OR lamb)", "John 4:10; Revelation 22:1–2"},
+		{`NOT "living water"`, "John 4:12; Revelation 22:1–2"},
+		{"Jacob", "John 4:12"},
+		{"note", ""},
+		{"add", ""},
+	}
+
+	for _, tc := range testCases {
+		results, err := index.Search(tc.query, Options{})
+		if err != nil {
+			t.Errorf("Search(%q) failed: %v", tc.query, err)
+			continue
+		}
+		if result := resultReferences(results); result != tc.expected {
+			t.Errorf("Search(%q): expected %q, got %q", tc.query, tc.expected, result)
+		}
+	}
+}
+
+// TestSearchOptions tests limiting searches to passages and translations
+func TestSearchOptions(t *testing.T) {
+	index := buildTestIndex(t)
+
+	ranges, err := ref.Parse("Rev 22:2; John 4:11")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	results, err := index.Search("water", Options{Ranges: ranges})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if result := resultReferences(results); result != "John 4:11; Revelation 22:1–2" {
+		t.Errorf("Expected verses within the ranges, got %q", result)
+	}
+
+	results, err = index.Search("water", Options{Translations: []string{"other"}})
+	if err != nil || len(results) != 0 {
+		t.Errorf("Expected no verses of another translation, got %d (%v)", len(results), err)
+	}
+}
+
+// TestSearchErrors tests that malformed queries are rejected
+func TestSearchErrors(t *testing.T) {
+	index := buildTestIndex(t)
+
+	testCases := []struct {
+		query    string
+		expected string
+	}{
+		{"", "empty query"},
+		{`"living water`, "unterminated phrase"},
+		{"(living OR water", "missing closing parenthesis"},
+		{"living)", "unexpected"},
+		{"water OR", "expected a word or phrase"},
+		{"AND water", "expected a word or phrase"},
+		{"water NOT", "expected a word or phrase after NOT"},
+		{"—", "no words to search for"},
+	}
+
+	for _, tc := range testCases {
+		_, err := index.Search(tc.query, Options{})
+		if err == nil || !strings.Contains(err.Error(), tc.expected) {
+			t.Errorf("Search(%q): expected error containing %q, got %v", tc.query, tc.expected, err)
+		}
+	}
+}
+
+// TestSnippet tests highlighting matches and shortening long verses
+func TestSnippet(t *testing.T) {
+	index := buildTestIndex(t)
+	verse := []ref.Range{{Start: ref.Reference{Book: "JHN", Chapter: 4, Verse: 11}}}
+
+	testCases := []struct {
+		query    string
+		width    int
+		expected string
+	}{
+		{`"living water"`, 0, "“Sir,” the woman replied, “where then will You get this [living water]?”"},
+		{"woman -lamb", 0, "“Sir,” the [woman] replied, “where then will You get this living water?”"},
+		{"replied OR will", 0, "“Sir,” the woman [replied], “where then [will] You get this living water?”"},
+		{"get", 40, "…then will You [get] this living water?”"},
+		{"replied", 40, "…the woman [replied], “where then…"},
+	}
+
+	for _, tc := range testCases {
+		results, err := index.Search(tc.query, Options{Ranges: verse})
+		if err != nil || len(results) != 1 {
+			t.Fatalf("Search(%q) failed: %v", tc.query, err)
+		}
+		if result := results[0].Snippet(tc.width, "[", "]"); result != tc.expected {
+			t.Errorf("Snippet(%q): expected %q, got %q", tc.query, tc.expected, result)
+		}
+	}
+}
+
+// TestSnippetCut tests shortening text without spaces and matches longer than the width
+func TestSnippetCut(t *testing.T) {
+	text := "神說要有光就有了光神看光是好的就把光暗分開了"
+	light := strings.Index(text, "光是")
+	testCases := []struct {
+		result   Result
+		width    int
+		expected string
+	}{
+		{Result{Verse: Verse{Text: text}, Highlights: []Span{{Start: light, End: light + len("光")}}}, 20, "…神看[光]是好的…"},
+		{Result{Verse: Verse{Text: text}, Highlights: []Span{{Start: light, End: light + len("光")}}}, 22, "…神看[光]是好的就…"},
+		{Result{Verse: Verse{Text: text}, Highlights: []Span{{Start: 0, End: len("神說要有光")}}}, 7, "[神說要有光]…"},
+		{Result{Verse: Verse{Text: "the earth was formless and void"}, Highlights: []Span{{Start: 14, End: 22}}}, 7, "…[formless]…"},
+		{Result{Verse: Verse{Text: "formless and void"}, Highlights: []Span{{Start: 0, End: 8}, {Start: 13, End: 17}}}, 15, "[formless] and…"},
+	}
+
+	for _, tc := range testCases {
+		result := tc.result.Snippet(tc.width, "[", "]")
+		if !utf8.ValidString(result) || result != tc.expected {
+			t.Errorf("Snippet(%d) of %q: expected %q, got %q", tc.width, tc.result.Verse.Text, tc.expected, result)
+		}
+	}
+}
+
+// TestSaveLoad tests that a saved index gives the same results
+func TestSaveLoad(t *testing.T) {
+	index := buildTestIndex(t)
+
+	var buffer bytes.Buffer
+	if err := index.Save(&buffer); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	loaded, err := Load(&buffer)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if loaded.Len() != index.Len() {
+		t.Errorf("Expected %d verses, got %d", index.Len(), loaded.Len())
+	}
+	for _, query := range []string{`"living water"`, "shepherd*", "water -living"} {
+		expected, _ := index.Search(query, Options{})
+		results, err := loaded.Search(query, Options{})
+		if err != nil {
+			t.Fatalf("Search(%q) failed: %v", query, err)
+		}
+		if resultReferences(results) != resultReferences(expected) {
+			t.Errorf("Search(%q): expected %q after loading, got %q", query, resultReferences(expected), resultReferences(results))
+		}
+	}
+
+	if _, err := Load(strings.NewReader("not an index")); err == nil {
+		t.Error("Expected an error for an invalid index file")
+	}
+}
@@ -0,0 +1,54 @@
+package search
+
+import (
+	"encoding/gob"
+	"fmt"
+	"io"
+)
+
+// indexVersion is the version of the saved index format, increased whenever
+// the format or the tokenization changes so that old index files are rebuilt
+const indexVersion = 1
+
+// indexFile is the saved form of an Index
+type indexFile struct {
+	Version int
+	Verses  []Verse
+	Terms   map[string][]posting
+}
+
+// Save writes the index in a binary format that Load reads.
+//
+// Example:
+//
+//	f, err := os.Create("bsb.idx")
+//	if err != nil {
+//		return err
+//	}
+//	defer f.Close()
+//	err = index.Save(f)
+func (ix *Index) Save(w io.Writer) error {
+	file := indexFile{Version: indexVersion, Verses: ix.verses, Terms: ix.terms}
+	if err := gob.NewEncoder(w).Encode(file); err != nil {
+		return fmt.Errorf("error writing index: %w", err)
+	}
+	return nil
+}
+
+// Load reads an index written by Save.
+func Load(r io.Reader) (*Index, error) {
+	var file indexFile
+	if err := gob.NewDecoder(r).Decode(&file); err != nil {
+		return nil, fmt.Errorf("error reading index: %w", err)
+	}
+	if file.Version != indexVersion {
+		return nil, fmt.Errorf("unsupported index version %d (expected %d); rebuild the index", file.Version, indexVersion)
+	}
+
+	index := &Index{verses: file.Verses, terms: file.Terms}
+	if index.terms == nil {
+		index.terms = make(map[string][]posting)
+	}
+	index.sortTerms()
+	return index, nil
+}
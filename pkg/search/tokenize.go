@@ -0,0 +1,159 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Token is a word of verse text together with its position in the text.
+type Token struct {
+	Term  string // Normalized word used for matching (see Normalize)
+	Start int    // Byte offset of the word in the text
+	End   int    // Byte offset just past the word
+}
+
+// Tokenize splits text into words. Words are runs of letters, digits, and combining marks;
+// everything else, including apostrophes and hyphens, separates words, so "God's" is
+// found by a search for "God".
+//
+// Example:
+//
+//	tokens := search.Tokenize("God's well-watered garden")
+//	// god, s, well, watered, garden
+func Tokenize(text string) []Token {
+	var tokens []Token
+	start := -1
+
+	for i, r := range text {
+		if isWordRune(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			tokens = append(tokens, Token{Term: Normalize(text[start:i]), Start: start, End: i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, Token{Term: Normalize(text[start:]), Start: start, End: len(text)})
+	}
+
+	return tokens
+}
+
+// Normalize returns the form of a word used for matching: lowercase and with diacritics
+// removed, so "Élie", "ELIE", and "élie" all become "elie". Combining marks such as Hebrew vowel points are dropped, and ligatures are spelled out.
+func Normalize(word string) string {
+	var result strings.Builder
+	result.Grow(len(word))
+
+	for _, r := range word {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		r = unicode.ToLower(r)
+		if folded, ok := foldTable[r]; ok {
+			result.WriteString(folded)
+		} else if base := greekBase(r); base != 0 {
+			result.WriteRune(base)
+		} else {
+			result.WriteRune(r)
+		}
+	}
+
+	return result.String()
+}
+
+// isWordRune reports whether a rune is part of a word
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r)
+}
+
+// foldTable maps lowercase letters with diacritics and ligatures to plain letters
+var foldTable = buildFoldTable(map[string]string{
+	"a":  "àáâãäåāăąǎǟǡǻȁȃȧạảấầẩẫậắằẳẵặ",
+	"c":  "çćĉċč",
+	"d":  "ďđḍ",
+	"e":  "èéêëēĕėęěȅȇȩẹẻẽếềểễệ",
+	"g":  "ĝğġģǧ",
+	"h":  "ĥħḥḫ",
+	"i":  "ìíîïĩīĭįıǐȉȋịỉ",
+	"j":  "ĵǰ",
+	"k":  "ķǩḳ",
+	"l":  "ĺļľŀłḷ",
+	"n":  "ñńņňṅṇ",
+	"o":  "òóôõöøōŏőơǒǫȍȏȯọỏốồổỗộớờởỡợ",
+	"r":  "ŕŗřȑȓṛ",
+	"s":  "śŝşšșṡṣ",
+	"t":  "ţťŧțṭ",
+	"u":  "ùúûüũūŭůűųưǔǖǘǚǜȕȗụủứừửữự",
+	"w":  "ŵẁẃẅ",
+	"y":  "ýÿŷỳỹ",
+	"z":  "źżžẓ",
+	"ae": "æǽ",
+	"oe": "œ",
+	"ss": "ß",
+	"th": "þ",
+	"σ":  "ς",
+})
+
+// buildFoldTable inverts a map of plain spellings to the letters that fold to them
+func buildFoldTable(letters map[string]string) map[rune]string {
+	table := make(map[rune]string)
+	for plain, variants := range letters {
+		for _, r := range variants {
+			table[r] = plain
+		}
+	}
+	return table
+}
+
+// greekBase returns the plain lowercase letter of a Greek letter with accents or breathings,
+// or 0 if the rune is not one
+func greekBase(r rune) rune {
+	switch r {
+	case 'ά':
+		return 'α'
+	case 'έ':
+		return 'ε'
+	case 'ή':
+		return 'η'
+	case 'ί', 'ϊ', 'ΐ':
+		return 'ι'
+	case 'ό':
+		return 'ο'
+	case 'ύ', 'ϋ', 'ΰ':
+		return 'υ'
+	case 'ώ':
+		return 'ω'
+	}
+
+	// Greek Extended: polytonic letters in blocks that share a base letter
+	if r < 0x1F00 || r > 0x1FFF {
+		return 0
+	}
+	switch {
+	case r <= 0x1F0F, r >= 0x1F80 && r <= 0x1F8F, r >= 0x1FB0 && r <= 0x1FBB:
+		return 'α'
+	case r <= 0x1F1F:
+		return 'ε'
+	case r <= 0x1F2F, r >= 0x1F90 && r <= 0x1F9F, r >= 0x1FC2 && r <= 0x1FCC:
+		return 'η'
+	case r <= 0x1F3F, r >= 0x1FD0 && r <= 0x1FDB:
+		return 'ι'
+	case r <= 0x1F4F:
+		return 'ο'
+	case r <= 0x1F5F, r >= 0x1FE0 && r <= 0x1FE3, r >= 0x1FE6 && r <= 0x1FEB:
+		return 'υ'
+	case r <= 0x1F6F, r >= 0x1FA0 && r <= 0x1FAF, r >= 0x1FF2 && r <= 0x1FFC:
+		return 'ω'
+	case r == 0x1FE4, r == 0x1FE5, r == 0x1FEC:
+		return 'ρ'
+	case r <= 0x1F7D:
+		// Pairs of grave and acute accents: ὰά ὲέ ὴή ὶί ὸό ὺύ ὼώ
+		return []rune("αεηιουω")[(r-0x1F70)/2]
+	}
+	return 0
+}
@@ -0,0 +1,177 @@
+// Package stats computes statistics of parsed USFM documents: verse, word, character,
+// footnote, cross-reference, and section counts, average verse length, the longest and
+// shortest verses, and the number of different words, per chapter, per book, and in total.
+//
+// Example:
+//
+//	report := stats.Compute(bible.Documents)
+//	fmt.Println(report.Total.Verses, report.Total.Words) // 31086 719645
+//	for _, book := range report.Books {
+//		fmt.Println(book.Name, book.AverageVerseWords)
+//	}
+package stats
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/arenzana/usfmp/pkg/ref"
+	"github.com/arenzana/usfmp/pkg/search"
+	"github.com/arenzana/usfmp/pkg/usfm"
+)
+
+// Stats holds the statistics of a chapter, a book, or all books.
+type Stats struct {
+	Name              string       `json:"name"`                     // "Genesis 1", "Genesis", or "Total"
+	Book              string       `json:"book,omitempty"`           // USFM book code, for a chapter or book
+	Chapter           int          `json:"chapter,omitempty"`        // Chapter number, for a chapter
+	Books             int          `json:"books,omitempty"`          // Number of books, for the total
+	Chapters          int          `json:"chapters,omitempty"`       // Number of chapters, for a book or the total
+	Verses            int          `json:"verses"`                   // Number of verses; a verse bridge counts each of its verses
+	Words             int          `json:"words"`                    // Number of words in verse text
+	Characters        int          `json:"characters"`               // Number of characters in verse text, without markup or footnotes
+	UniqueWords       int          `json:"unique_words"`             // Number of different words, ignoring case and diacritics
+	AverageVerseWords float64      `json:"average_verse_words"`      // Words per verse
+	Footnotes         int          `json:"footnotes"`                // Number of footnotes
+	CrossReferences   int          `json:"cross_references"`         // Number of \r cross-reference lines under section headings
+	Sections          int          `json:"sections"`                 // Number of section headings
+	Longest           *VerseLength `json:"longest_verse,omitempty"`  // Verse with the most words (the first one if several)
+	Shortest          *VerseLength `json:"shortest_verse,omitempty"` // Verse with the fewest words (the first one if several)
+
+	words map[string]bool // Different words, for UniqueWords
+}
+
+// VerseLength is the length of a verse, or verse bridge, in words.
+type VerseLength struct {
+	Reference ref.Range `json:"reference"`
+	Words     int       `json:"words"`
+}
+
+// BookStats holds the statistics of a book and of each of its chapters.
+type BookStats struct {
+	Stats
+	ChapterStats []Stats `json:"chapter_stats"`
+}
+
+// Report holds the statistics of a set of documents.
+type Report struct {
+	Total Stats       `json:"total"`
+	Books []BookStats `json:"books"`
+}
+
+// Compute returns the statistics of documents, with the books in the order given.
+func Compute(documents []*usfm.Document) *Report {
+	report := &Report{Total: Stats{Name: "Total", words: make(map[string]bool)}}
+
+	for _, doc := range documents {
+		code := doc.BookCode()
+		book := BookStats{Stats: Stats{Name: bookName(doc), Book: code, words: make(map[string]bool)}}
+
+		for _, chapter := range doc.Chapters {
+			chapterStats := Stats{
+				Name:    fmt.Sprintf("%s %d", book.Name, chapter.Number),
+				Book:    code,
+				Chapter: chapter.Number,
+				words:   make(map[string]bool),
+			}
+			levels := []*Stats{&chapterStats, &book.Stats, &report.Total}
+
+			for _, section := range chapter.Sections {
+				for _, level := range levels {
+					if section.Title != "" {
+						level.Sections++
+					}
+					if section.Reference != "" {
+						level.CrossReferences++
+					}
+				}
+
+				for _, verse := range section.Verses {
+					addVerse(levels, code, chapter.Number, verse)
+				}
+			}
+
+			chapterStats.finish()
+			book.ChapterStats = append(book.ChapterStats, chapterStats)
+			book.Chapters++
+			report.Total.Chapters++
+		}
+
+		book.finish()
+		report.Books = append(report.Books, book)
+		report.Total.Books++
+	}
+
+	report.Total.finish()
+	return report
+}
+
+// addVerse adds the counts of a verse to each level of statistics
+func addVerse(levels []*Stats, book string, chapter int, verse usfm.Verse) {
+	text := usfm.PlainText(verse.Text)
+	words := Words(text)
+
+	length := &VerseLength{
+		Reference: ref.Range{Start: ref.Reference{Book: book, Chapter: chapter, Verse: verse.Number}},
+		Words:     len(words),
+	}
+	if verse.EndNumber > 0 {
+		length.Reference.End = ref.Reference{Book: book, Chapter: chapter, Verse: verse.EndNumber}
+	}
+
+	for _, level := range levels {
+		level.Verses += verse.LastNumber() - verse.Number + 1
+		level.Words += len(words)
+		level.Characters += utf8.RuneCountInString(text)
+		level.Footnotes += len(verse.Footnotes)
+		for _, word := range words {
+			level.words[search.Normalize(word)] = true
+		}
+		if level.Longest == nil || length.Words > level.Longest.Words {
+			level.Longest = length
+		}
+		if level.Shortest == nil || length.Words < level.Shortest.Words {
+			level.Shortest = length
+		}
+	}
+}
+
+// finish computes the statistics that are derived from the counts
+func (s *Stats) finish() {
+	s.UniqueWords = len(s.words)
+	s.words = nil
+	if s.Verses > 0 {
+		s.AverageVerseWords = float64(s.Words) / float64(s.Verses)
+	}
+}
+
+// Words splits verse text into words at whitespace, leaving out punctuation around the words
+// and anything without a letter or digit (such as a dash between spaces).
+func Words(text string) []string {
+	var words []string
+	for _, field := range strings.Fields(text) {
+		word := strings.TrimFunc(field, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		if word != "" {
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+// bookName names a book by its registry name, its main title, or its code
+func bookName(doc *usfm.Document) string {
+	if book, ok := usfm.LookupBook(doc.BookCode()); ok {
+		return book.Name
+	}
+	if doc.MainTitle != "" {
+		return doc.MainTitle
+	}
+	if code := doc.BookCode(); code != "" {
+		return code
+	}
+	return doc.SourceFile
+}
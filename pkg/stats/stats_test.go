@@ -0,0 +1,111 @@
+package stats
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/arenzana/usfmp/pkg/usfm"
+)
+
+// computeTestReport computes the statistics of a short book with two chapters
+func computeTestReport(t *testing.T) *Report {
+	t.Helper()
+
+	input := `\id RUT Ruth
+\c 1
+\s1 Naomi Loses Her Husband and Sons
+\r (Judges 2:6)
+\p
+\v 1 In the days when the judges ruled, there was a famine in the land.
+\v 2 The man's name was Elimelech.\f + \fr 1:2 \ft Elimelech means My God is King.\f*
+\s1 Ruth's Loyalty
+\p
+\v 3-4 Then Elimelech died — and Naomi was left with her two sons.
+\c 2
+\p
+\v 1 Now Naomi had a relative.
+\v 2 Ruth said: “Let me go.”`
+
+	doc, err := usfm.NewParser(usfm.DefaultParseOptions()).Parse(strings.NewReader(input), "RUT.usfm")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	return Compute([]*usfm.Document{doc})
+}
+
+// TestCompute tests counting per chapter, per book, and in total
+func TestCompute(t *testing.T) {
+	report := computeTestReport(t)
+
+	if len(report.Books) != 1 || len(report.Books[0].ChapterStats) != 2 {
+		t.Fatalf("Expected 1 book with 2 chapters, got %+v", report.Books)
+	}
+	book := report.Books[0]
+
+	testCases := []struct {
+		name     string
+		stats    Stats
+		expected [6]int // verses, words, footnotes, cross-references, sections, unique words
+	}{
+		{"Ruth 1", book.ChapterStats[0], [6]int{4, 30, 1, 1, 2, 23}},
+		{"Ruth 2", book.ChapterStats[1], [6]int{2, 10, 0, 0, 0, 10}},
+		{"Ruth", book.Stats, [6]int{6, 40, 1, 1, 2, 31}},
+		{"Total", report.Total, [6]int{6, 40, 1, 1, 2, 31}},
+	}
+
+	for _, tc := range testCases {
+		s := tc.stats
+		result := [6]int{s.Verses, s.Words, s.Footnotes, s.CrossReferences, s.Sections, s.UniqueWords}
+		if s.Name != tc.name {
+			t.Errorf("Expected name %q, got %q", tc.name, s.Name)
+		}
+		if result != tc.expected {
+			t.Errorf("%s: expected counts %v, got %v", tc.name, tc.expected, result)
+		}
+	}
+
+	if book.Book != "RUT" || book.Chapters != 2 || report.Total.Books != 1 || report.Total.Chapters != 2 {
+		t.Errorf("Unexpected book and chapter counts: %+v, %+v", book.Stats, report.Total)
+	}
+	if book.ChapterStats[1].Chapter != 2 {
+		t.Errorf("Expected chapter 2, got %d", book.ChapterStats[1].Chapter)
+	}
+	if average := report.Total.AverageVerseWords; average != 40.0/6 {
+		t.Errorf("Expected 6.7 words per verse, got %v", average)
+	}
+	if longest := book.Longest; longest == nil || longest.Reference.String() != "Ruth 1:1" || longest.Words != 14 {
+		t.Errorf("Expected Ruth 1:1 to be the longest verse, got %+v", longest)
+	}
+	if shortest := book.Shortest; shortest == nil || shortest.Reference.String() != "Ruth 1:2" || shortest.Words != 5 {
+		t.Errorf("Expected Ruth 1:2, the first of three 5-word verses, to be the shortest verse, got %+v", shortest)
+	}
+	if longest := book.ChapterStats[1].Longest; longest.Reference.String() != "Ruth 2:1" {
+		t.Errorf("Expected Ruth 2:1 to be the longest verse of chapter 2, got %+v", longest)
+	}
+	if words := book.ChapterStats[0].Words; words != 30 {
+		t.Errorf("Expected the verse bridge and not the footnote to count, got %d words", words)
+	}
+	if len(Compute(nil).Books) != 0 {
+		t.Error("Expected no books for no documents")
+	}
+}
+
+// TestWords tests splitting verse text into words
+func TestWords(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected []string
+	}{
+		{"In the beginning, God created", []string{"In", "the", "beginning", "God", "created"}},
+		{"“Let me go.” — said she", []string{"Let", "me", "go", "said", "she"}},
+		{"The man's name (Elimelech)", []string{"The", "man's", "name", "Elimelech"}},
+		{"  ", nil},
+	}
+
+	for _, tc := range testCases {
+		if result := Words(tc.input); !reflect.DeepEqual(result, tc.expected) {
+			t.Errorf("Words(%q): expected %q, got %q", tc.input, tc.expected, result)
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package usfm
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/arenzana/usfmp/pkg/ref"
+)
+
+// Bible is a translation made up of several books, each parsed from its own USFM document.
+// Books are indexed by their USFM code; BookCodes and chapter navigation follow canonical order,
+// while Documents keeps the order the books were given in.
+//
+// Example:
+//
+//	bible, err := usfm.LoadBible("samples/bsb_usfm", usfm.DefaultParseOptions())
+//	if err != nil {
+//		return err
+//	}
+//	john, ok := bible.Book("JHN")
+type Bible struct {
+	Name      string      // Short name of the translation, used in file names and headers (e.g., "bsb_usfm")
+	Title     string      // Title given by the \id line of every book (e.g., "Berean Standard Bible"), or "" if they differ
+	Documents []*Document // Books in the order they were given
+
+	books    map[string]*Document // Books by USFM code
+	codes    []string             // Book codes in canonical order; peripheral books with other codes come first
+	chapters []ref.Reference      // Every chapter of every book, in canonical order
+}
+
+// NewBible builds a translation from parsed documents.
+// It returns an error if two documents have the same book code.
+// Documents without an \id book code are kept but cannot be looked up by code.
+func NewBible(name string, documents []*Document) (*Bible, error) {
+	bible := &Bible{
+		Name:      name,
+		Documents: slices.Clone(documents),
+		books:     make(map[string]*Document, len(documents)),
+	}
+
+	for _, doc := range documents {
+		code := doc.BookCode()
+		if code == "" {
+			continue
+		}
+		if existing, ok := bible.books[code]; ok {
+			return nil, fmt.Errorf("duplicate book %s in %s and %s", code, existing.SourceFile, doc.SourceFile)
+		}
+		bible.books[code] = doc
+	}
+
+	canonical := slices.Clone(documents)
+	slices.SortStableFunc(canonical, func(a, b *Document) int {
+		return ref.Reference{Book: a.BookCode()}.Compare(ref.Reference{Book: b.BookCode()})
+	})
+	for _, doc := range canonical {
+		if code := doc.BookCode(); code != "" {
+			bible.codes = append(bible.codes, code)
+			for _, chapter := range doc.Chapters {
+				bible.chapters = append(bible.chapters, ref.Reference{Book: code, Chapter: chapter.Number})
+			}
+		}
+	}
+
+	for i, doc := range bible.Documents {
+		title := idDescription(doc)
+		if i == 0 {
+			bible.Title = title
+		} else if title != bible.Title {
+			bible.Title = ""
+		}
+	}
+
+	return bible, nil
+}
+
+// LoadBible parses a USFM file, or all USFM files in a directory and its subdirectories,
+// as a translation named after the file or directory.
+func LoadBible(path string, options ParseOptions) (*Bible, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot access input path: %w", err)
+	}
+
+	files := []string{path}
+	if info.IsDir() {
+		if files, err = FindFiles(path); err != nil {
+			return nil, fmt.Errorf("error finding USFM files: %w", err)
+		}
+		if len(files) == 0 {
+			return nil, fmt.Errorf("no USFM files found in directory: %s", path)
+		}
+	}
+
+	parser := NewParser(options)
+	documents := make([]*Document, 0, len(files))
+	for _, file := range files {
+		doc, err := parseFile(parser, file)
+		if err != nil {
+			return nil, err
+		}
+		documents = append(documents, doc)
+	}
+
+	name := filepath.Base(filepath.Clean(path))
+	return NewBible(strings.TrimSuffix(name, filepath.Ext(name)), documents)
+}
+
+// parseFile parses a single USFM file
+func parseFile(parser *Parser, file string) (*Document, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("error opening file %s: %w", file, err)
+	}
+	defer f.Close()
+
+	doc, err := parser.Parse(f, file)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing file %s: %w", file, err)
+	}
+	return doc, nil
+}
+
+// FindFiles returns the USFM files (*.sfm and *.usfm, in any case) in a directory
+// and its subdirectories, in lexical order.
+func FindFiles(dir string) ([]string, error) {
+	var files []string
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !info.IsDir() && IsUSFMFile(path) {
+			files = append(files, path)
+		}
+
+		return nil
+	})
+
+	return files, err
+}
+
+// IsUSFMFile reports whether a file name has a USFM extension (.sfm or .usfm).
+func IsUSFMFile(filename string) bool {
+	ext := strings.ToLower(filepath.Ext(filename))
+	return ext == ".sfm" || ext == ".usfm"
+}
+
+// idDescription returns the text after the book code on the \id line
+// (e.g., "Berean Standard Bible" for "GEN - Berean Standard Bible")
+func idDescription(doc *Document) string {
+	description := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(doc.ID), doc.BookCode()))
+	description = strings.TrimSpace(strings.TrimLeft(description, "-–—"))
+	return strings.Join(strings.Fields(description), " ")
+}
+
+// Book returns the document of a book by its USFM code (case-insensitive).
+func (b *Bible) Book(code string) (*Document, bool) {
+	doc, ok := b.books[strings.ToUpper(code)]
+	return doc, ok
+}
+
+// BookCodes returns the codes of the books in the translation, in canonical order.
+func (b *Bible) BookCodes() []string {
+	return slices.Clone(b.codes)
+}
+
+// Chapter returns a chapter of a book.
+func (b *Bible) Chapter(code string, number int) (*Chapter, bool) {
+	doc, ok := b.Book(code)
+	if !ok {
+		return nil, false
+	}
+	for i := range doc.Chapters {
+		if doc.Chapters[i].Number == number {
+			return &doc.Chapters[i], true
+		}
+	}
+	return nil, false
+}
+
+// NextChapter returns the chapter that follows the chapter of a reference, continuing with
+// the first chapter of the next book. It reports false after the last chapter of the translation.
+func (b *Bible) NextChapter(reference ref.Reference) (ref.Reference, bool) {
+	current := ref.Reference{Book: strings.ToUpper(reference.Book), Chapter: reference.Chapter}
+	for _, chapter := range b.chapters {
+		if chapter.Compare(current) > 0 {
+			return chapter, true
+		}
+	}
+	return ref.Reference{}, false
+}
+
+// PreviousChapter returns the chapter before the chapter of a reference, continuing with
+// the last chapter of the previous book. It reports false before the first chapter of the translation.
+func (b *Bible) PreviousChapter(reference ref.Reference) (ref.Reference, bool) {
+	current := ref.Reference{Book: strings.ToUpper(reference.Book), Chapter: reference.Chapter}
+	for i := len(b.chapters) - 1; i >= 0; i-- {
+		if b.chapters[i].Compare(current) < 0 {
+			return b.chapters[i], true
+		}
+	}
+	return ref.Reference{}, false
+}
+
+// Passages looks up references in the translation (see Passages).
+func (b *Bible) Passages(ranges ...ref.Range) []*Document {
+	return Passages(b.Documents, ranges...)
+}
@@ -0,0 +1,124 @@
+package usfm
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/arenzana/usfmp/pkg/ref"
+)
+
+// TestNewBible tests indexing books and navigating between chapters
+func TestNewBible(t *testing.T) {
+	// Books are given out of order
+	bible, err := NewBible("test", []*Document{
+		parsePassageTestDocument(t, "EXO"),
+		parsePassageTestDocument(t, "GEN"),
+		parsePassageTestDocument(t, "FRT"),
+	})
+	if err != nil {
+		t.Fatalf("NewBible failed: %v", err)
+	}
+
+	if codes := strings.Join(bible.BookCodes(), ","); codes != "FRT,GEN,EXO" {
+		t.Errorf("Expected books FRT,GEN,EXO, got %s", codes)
+	}
+	if doc := bible.Documents[0]; doc.BookCode() != "EXO" {
+		t.Errorf("Expected documents in the order given, got %s first", doc.BookCode())
+	}
+	if bible.Title != "Test Bible" {
+		t.Errorf("Expected title %q, got %q", "Test Bible", bible.Title)
+	}
+	if doc, ok := bible.Book("exo"); !ok || doc.BookCode() != "EXO" {
+		t.Error("Expected to find Exodus by code")
+	}
+	if _, ok := bible.Book("LEV"); ok {
+		t.Error("Expected Leviticus to be missing")
+	}
+	if chapter, ok := bible.Chapter("GEN", 2); !ok || chapter.Number != 2 {
+		t.Error("Expected to find Genesis 2")
+	}
+	if _, ok := bible.Chapter("GEN", 3); ok {
+		t.Error("Expected Genesis 3 to be missing")
+	}
+
+	testCases := []struct {
+		reference ref.Reference
+		previous  string
+		next      string
+	}{
+		{ref.Reference{Book: "GEN", Chapter: 1}, "FRT 2", "Genesis 2"},
+		{ref.Reference{Book: "GEN", Chapter: 2, Verse: 1}, "Genesis 1", "Exodus 1"},
+		{ref.Reference{Book: "EXO", Chapter: 2}, "Exodus 1", ""},
+		{ref.Reference{Book: "FRT", Chapter: 1}, "", "FRT 2"},
+	}
+
+	for _, tc := range testCases {
+		if result := chapterName(bible.PreviousChapter(tc.reference)); result != tc.previous {
+			t.Errorf("PreviousChapter(%s): expected %q, got %q", tc.reference, tc.previous, result)
+		}
+		if result := chapterName(bible.NextChapter(tc.reference)); result != tc.next {
+			t.Errorf("NextChapter(%s): expected %q, got %q", tc.reference, tc.next, result)
+		}
+	}
+}
+
+// chapterName formats a chapter found by navigation, or "" if there is none
+func chapterName(chapter ref.Reference, ok bool) string {
+	if !ok {
+		return ""
+	}
+	return chapter.String()
+}
+
+// TestNewBibleDuplicateBooks tests that a book cannot appear twice in a translation
+func TestNewBibleDuplicateBooks(t *testing.T) {
+	_, err := NewBible("test", []*Document{
+		parsePassageTestDocument(t, "GEN"),
+		parsePassageTestDocument(t, "GEN"),
+	})
+	if err == nil || !strings.Contains(err.Error(), "duplicate book GEN") {
+		t.Errorf("Expected a duplicate book error, got %v", err)
+	}
+}
+
+// TestLoadBible tests loading a translation from a directory
+func TestLoadBible(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"02EXO.SFM":    "\\id EXO - Sample Bible\n\\c 1\n\\v 1 These are the names.",
+		"01GEN.usfm":   "\\id GEN - Sample Bible\n\\c 1\n\\v 1 In the beginning.",
+		"readme.txt":   "not USFM",
+		"nested/x.sfm": "\\id RUT - Sample Bible\n\\c 1\n\\v 1 In the days.",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, "sample", filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	bible, err := LoadBible(filepath.Join(dir, "sample"), DefaultParseOptions())
+	if err != nil {
+		t.Fatalf("LoadBible failed: %v", err)
+	}
+	if bible.Name != "sample" || bible.Title != "Sample Bible" {
+		t.Errorf("Unexpected name %q and title %q", bible.Name, bible.Title)
+	}
+	if codes := strings.Join(bible.BookCodes(), ","); codes != "GEN,EXO,RUT" {
+		t.Errorf("Expected books GEN,EXO,RUT, got %s", codes)
+	}
+
+	passages := bible.Passages(ref.Range{Start: ref.Reference{Book: "EXO", Chapter: 1, Verse: 1}})
+	if len(passages) != 1 || passages[0].Chapters[0].Sections[0].Verses[0].Text != "These are the names." {
+		t.Error("Expected to look up Exodus 1:1")
+	}
+
+	if _, err := LoadBible(filepath.Join(dir, "missing"), DefaultParseOptions()); err == nil {
+		t.Error("Expected an error for a missing path")
+	}
+}
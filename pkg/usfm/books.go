@@ -0,0 +1,36 @@
+package usfm
+
+import (
+	"strings"
+
+	"github.com/arenzana/usfmp/internal/canon"
+)
+
+// Book describes a canonical book of the Bible as identified by its USFM code.
+// Books are listed in canonical order, starting with the Old Testament,
+// followed by the New Testament and the deuterocanonical books.
+type Book = canon.Book
+
+// Books returns the canonical book registry in canonical order.
+// The returned slice is a copy and may be modified by the caller.
+func Books() []Book {
+	return canon.Books()
+}
+
+// LookupBook returns the registry entry for a USFM book code such as "GEN" or "1KI".
+// The lookup is case-insensitive. The second return value reports whether the code is known.
+func LookupBook(code string) (Book, bool) {
+	return canon.Lookup(code)
+}
+
+// BookCode returns the USFM book code from the document's \id marker.
+// The \id content usually carries a description after the code
+// (e.g., "GEN - Berean Standard Bible"), so only the first word is returned.
+// An empty string is returned when the document has no \id marker.
+func (d *Document) BookCode() string {
+	fields := strings.Fields(d.ID)
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.ToUpper(fields[0])
+}
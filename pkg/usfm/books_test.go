@@ -0,0 +1,30 @@
+package usfm
+
+import (
+	"testing"
+)
+
+// TestLookupBook tests the canonical book registry
+func TestLookupBook(t *testing.T) {
+	book, ok := LookupBook("gen")
+	if !ok {
+		t.Fatal("Expected GEN to be a known book")
+	}
+	if book.Number != 1 || book.Name != "Genesis" || book.OSIS != "Gen" || book.Testament != "OT" {
+		t.Errorf("Unexpected registry entry for GEN: %+v", book)
+	}
+
+	book, ok = LookupBook("REV")
+	if !ok || book.Number != 66 {
+		t.Errorf("Expected REV to be book 66, got %+v", book)
+	}
+
+	if _, ok := LookupBook("XYZ"); ok {
+		t.Error("Expected XYZ to be unknown")
+	}
+
+	doc := &Document{ID: "1KI - Berean Standard Bible"}
+	if code := doc.BookCode(); code != "1KI" {
+		t.Errorf("Expected book code '1KI', got '%s'", code)
+	}
+}
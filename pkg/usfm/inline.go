@@ -0,0 +1,151 @@
+package usfm
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Span represents a run of verse text that shares the same character markup.
+// Character markers such as \w, \wj, \nd, and \add are kept in verse text by the parser;
+// ParseInline splits such text into spans so formatters can render the markup
+// (red letters, small caps, italics) without dealing with USFM syntax.
+type Span struct {
+	Text       string            `json:"text"`                 // Plain text of the span
+	Markers    []string          `json:"markers,omitempty"`    // Enclosing character markers, outermost first (e.g., ["wj", "w"])
+	Attributes map[string]string `json:"attributes,omitempty"` // Attributes of the innermost marker (e.g., strong="H0430")
+}
+
+// Has reports whether the span is enclosed by the given character marker.
+func (s Span) Has(marker string) bool {
+	for _, m := range s.Markers {
+		if m == marker {
+			return true
+		}
+	}
+	return false
+}
+
+// inlineMarkerRegex matches an inline marker such as \w, \+w, or \w* at the start of a string.
+var inlineMarkerRegex = regexp.MustCompile(`^\\(\+?)([a-z][a-z0-9]*)(\*?)`)
+
+// attributeRegex matches key="value" pairs in a character marker attribute list.
+var attributeRegex = regexp.MustCompile(`([a-zA-Z][\w-]*)\s*=\s*"([^"]*)"`)
+
+// ParseInline splits verse text into spans of plain text with their enclosing character markers.
+// Nested markers (\+w) are supported, and word-level attributes such as
+// \w names|strong="H8034"\w* are returned on the span that they annotate.
+// Unclosed markers extend to the end of the text.
+//
+// Example:
+//
+//	spans := usfm.ParseInline(`Now these \add are\add* the \w names|strong="H8034"\w*`)
+//	// spans[1] = {Text: "are", Markers: ["add"]}
+//	// spans[3] = {Text: "names", Markers: ["w"], Attributes: {"strong": "H8034"}}
+func ParseInline(text string) []Span {
+	var spans []Span
+	var stack []string
+	var buffer strings.Builder
+
+	// flush emits the buffered text as a span with the current marker stack
+	flush := func(attributes map[string]string) {
+		if buffer.Len() == 0 {
+			return
+		}
+		span := Span{Text: buffer.String(), Attributes: attributes}
+		if len(stack) > 0 {
+			span.Markers = append([]string(nil), stack...)
+		}
+		spans = append(spans, span)
+		buffer.Reset()
+	}
+
+	for i := 0; i < len(text); {
+		if text[i] != '\\' {
+			buffer.WriteByte(text[i])
+			i++
+			continue
+		}
+
+		match := inlineMarkerRegex.FindStringSubmatch(text[i:])
+		if match == nil {
+			// A lone backslash is kept as literal text
+			buffer.WriteByte(text[i])
+			i++
+			continue
+		}
+		i += len(match[0])
+		tag := match[2]
+
+		if match[3] == "*" {
+			// Closing marker: split off the attribute list and pop the stack
+			content, attributes := splitAttributes(buffer.String(), tag)
+			buffer.Reset()
+			buffer.WriteString(content)
+			flush(attributes)
+
+			for j := len(stack) - 1; j >= 0; j-- {
+				if stack[j] == tag {
+					stack = stack[:j]
+					break
+				}
+			}
+			continue
+		}
+
+		// Opening marker: the single space separating it from its content is not text
+		flush(nil)
+		stack = append(stack, tag)
+		if i < len(text) && text[i] == ' ' {
+			i++
+		}
+	}
+	flush(nil)
+
+	return spans
+}
+
+// PlainText removes character markup and word attributes from verse text,
+// returning only the readable text with whitespace collapsed.
+//
+// Example:
+//
+//	usfm.PlainText(`the \w names|strong="H8034"\w* of`) // "the names of"
+func PlainText(text string) string {
+	var result strings.Builder
+	for _, span := range ParseInline(text) {
+		result.WriteString(span.Text)
+	}
+	return strings.Join(strings.Fields(result.String()), " ")
+}
+
+// splitAttributes separates the text of a character span from its attribute list.
+// A bare attribute value (without key) is assigned to the marker's default attribute.
+func splitAttributes(content, tag string) (string, map[string]string) {
+	pipe := strings.LastIndex(content, "|")
+	if pipe < 0 {
+		return content, nil
+	}
+
+	raw := strings.TrimSpace(content[pipe+1:])
+	attributes := make(map[string]string)
+	for _, match := range attributeRegex.FindAllStringSubmatch(raw, -1) {
+		attributes[match[1]] = match[2]
+	}
+	if len(attributes) == 0 && raw != "" {
+		attributes[defaultAttribute(tag)] = raw
+	}
+
+	return content[:pipe], attributes
+}
+
+// defaultAttribute returns the attribute name used for bare attribute values of a marker
+func defaultAttribute(tag string) string {
+	switch tag {
+	case "w":
+		return "lemma"
+	case "rb":
+		return "gloss"
+	default:
+		return "default"
+	}
+}
@@ -0,0 +1,70 @@
+package usfm
+
+import (
+	"testing"
+)
+
+// TestParseInline tests splitting verse text into character markup spans
+func TestParseInline(t *testing.T) {
+	input := `Now these \add are\add* the \w names|strong="H8034"\w* of \wj \+w Jesus|strong="G2424"\+w*\wj*`
+
+	spans := ParseInline(input)
+
+	expected := []struct {
+		text    string
+		markers []string
+		strong  string
+	}{
+		{"Now these ", nil, ""},
+		{"are", []string{"add"}, ""},
+		{" the ", nil, ""},
+		{"names", []string{"w"}, "H8034"},
+		{" of ", nil, ""},
+		{"Jesus", []string{"wj", "w"}, "G2424"},
+	}
+
+	if len(spans) != len(expected) {
+		t.Fatalf("Expected %d spans, got %d: %+v", len(expected), len(spans), spans)
+	}
+
+	for i, exp := range expected {
+		span := spans[i]
+		if span.Text != exp.text {
+			t.Errorf("Span %d: expected text '%s', got '%s'", i, exp.text, span.Text)
+		}
+		if len(span.Markers) != len(exp.markers) {
+			t.Errorf("Span %d: expected markers %v, got %v", i, exp.markers, span.Markers)
+			continue
+		}
+		for j := range exp.markers {
+			if span.Markers[j] != exp.markers[j] {
+				t.Errorf("Span %d: expected markers %v, got %v", i, exp.markers, span.Markers)
+			}
+		}
+		if span.Attributes["strong"] != exp.strong {
+			t.Errorf("Span %d: expected strong '%s', got '%s'", i, exp.strong, span.Attributes["strong"])
+		}
+	}
+}
+
+// TestPlainText tests removing character markup from verse text
+func TestPlainText(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{"In the beginning God created", "In the beginning God created"},
+		{`Now these \add are\add* the \w names|strong="H8034"\w* of`, "Now these are the names of"},
+		{`\wj  \+w How|strong="G5101"\+w* \+w is it that|strong="G3754"\+w*?\wj*`, "How is it that?"},
+		{`the \nd LORD\nd* is my shepherd`, "the LORD is my shepherd"},
+		{`\w grace|lemma\w*`, "grace"},
+		{"", ""},
+	}
+
+	for i, tc := range testCases {
+		result := PlainText(tc.input)
+		if result != tc.expected {
+			t.Errorf("Test case %d: expected '%s', got '%s'", i+1, tc.expected, result)
+		}
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/arenzana/usfmp/pkg/ref"
 )
 
 // Parser handles the parsing of USFM content into structured Document objects.
@@ -37,7 +39,7 @@ type Parser struct {
 func NewParser(options ParseOptions) *Parser {
 	return &Parser{
 		options:       options,
-		markerRegex:   regexp.MustCompile(`^\\([a-z0-9]+)\*?\s*(.*)`),
+		markerRegex:   regexp.MustCompile(`^` + markerPattern + `\s*(.*)`),
 		chapterRegex:  regexp.MustCompile(`^\\c\s+(\d+)`),
 		verseRegex:    regexp.MustCompile(`^\\v\s+(\d+)\s*(.*)`),
 		footnoteRegex: regexp.MustCompile(`\\f\s*([^\\]*?)\\fr\s*([^\\]*?)\\ft\s*([^\\]*?)\\f\*`),
@@ -82,6 +84,7 @@ func (p *Parser) Parse(reader io.Reader, sourceFile string) (*Document, error) {
 
 	var currentChapter *Chapter
 	var currentSection *Section
+	var paragraph paragraphState
 
 	for scanner.Scan() {
 		lineNumber++
@@ -109,21 +112,27 @@ func (p *Parser) Parse(reader io.Reader, sourceFile string) (*Document, error) {
 			if err := p.handleChapter(doc, marker, &currentChapter, &currentSection, lineNumber); err != nil {
 				return nil, err
 			}
+			paragraph.pending = true
 		case "s1", "s2", "s3", "r":
-			p.handleSection(marker, &currentChapter, &currentSection)
+			p.handleSection(doc, marker, &currentChapter, &currentSection)
+			if marker.Tag != "r" {
+				paragraph.pending = true
+			}
 		case "v":
-			if err := p.handleVerse(marker, &currentSection, lineNumber); err != nil {
+			if err := p.handleVerse(marker, &currentSection, &paragraph, lineNumber); err != nil {
 				return nil, err
 			}
 		case "q1", "q2", "q", "p", "m", "pi", "pmo", "pm", "pmc", "pmr", "pi1", "pi2", "pi3":
 			// Poetry and paragraph text continuation markers - append to current verse
-			p.handleTextContinuation(marker, &currentSection)
+			p.handleTextContinuation(marker, &currentSection, &paragraph)
 		case "d":
 			// Descriptive title - could be part of section or standalone
 			p.handleDescriptiveTitle(marker, &currentChapter, &currentSection)
+			paragraph.pending = true
 		case "b":
 			// Blank line/paragraph break - no text content, just formatting
 			// Skip but don't error in strict mode
+			paragraph.pending = true
 		default:
 			// Handle unknown markers in strict mode
 			if p.options.StrictMode {
@@ -145,9 +154,20 @@ func (p *Parser) Parse(reader io.Reader, sourceFile string) (*Document, error) {
 		return nil, fmt.Errorf("error reading input: %w", err)
 	}
 
+	if p.options.ParseReferences {
+		parseFootnoteReferences(doc)
+	}
+
 	return doc, nil
 }
 
+// paragraphState tracks the paragraph or poetry marker in effect while parsing verses,
+// so that each verse can record where its text starts a new paragraph or poetry line
+type paragraphState struct {
+	marker  string // Marker of the current paragraph or poetry line (e.g., "p", "q1")
+	pending bool   // Whether a new paragraph or line started since the last verse text
+}
+
 // handleDocumentMetadata processes document-level markers like id, h, toc, mt1
 func (p *Parser) handleDocumentMetadata(doc *Document, marker *Marker) {
 	switch marker.Tag {
@@ -220,11 +240,14 @@ func (p *Parser) handleChapter(doc *Document, marker *Marker, currentChapter **C
 }
 
 // handleSection processes section markers (s1, s2, s3) and references
-func (p *Parser) handleSection(marker *Marker, currentChapter **Chapter, currentSection **Section) {
+func (p *Parser) handleSection(doc *Document, marker *Marker, currentChapter **Chapter, currentSection **Section) {
 	if marker.Tag == "r" {
 		// Reference/cross-reference - attach to current section
 		if p.options.IncludeReferences && *currentSection != nil {
 			(*currentSection).Reference = marker.Content
+			if p.options.ParseReferences {
+				(*currentSection).ParsedReference = parseReference(marker.Content, doc.BookCode(), *currentChapter)
+			}
 		}
 		return
 	}
@@ -246,12 +269,16 @@ func (p *Parser) handleSection(marker *Marker, currentChapter **Chapter, current
 }
 
 // handleVerse processes verse markers and ensures proper section structure
-func (p *Parser) handleVerse(marker *Marker, currentSection **Section, lineNumber int) error {
+func (p *Parser) handleVerse(marker *Marker, currentSection **Section, paragraph *paragraphState, lineNumber int) error {
 	verse, err := p.parseVerse(marker.Content, p.options.IncludeFootnotes)
 	if err != nil {
 		return fmt.Errorf("line %d: %w", lineNumber, err)
 	}
 
+	// The verse text is the first line of the verse within the current paragraph
+	verse.Lines = []Line{{Marker: paragraph.marker, Text: verse.Text, Break: paragraph.pending}}
+	paragraph.pending = false
+
 	// Ensure we have a section to add the verse to
 	if *currentSection == nil {
 		*currentSection = &Section{
@@ -272,13 +299,13 @@ func (p *Parser) parseMarker(line string, lineNumber int) (*Marker, error) {
 	}
 
 	matches := p.markerRegex.FindStringSubmatch(line)
-	if len(matches) < 3 {
+	if len(matches) < 5 {
 		return nil, fmt.Errorf("invalid marker format")
 	}
 
 	return &Marker{
 		Tag:     matches[1],
-		Content: strings.TrimSpace(matches[2]),
+		Content: strings.TrimSpace(matches[4]),
 		Line:    lineNumber,
 	}, nil
 }
@@ -299,9 +326,9 @@ func (p *Parser) parseVerse(content string, includeFootnotes bool) (*Verse, erro
 		return nil, fmt.Errorf("invalid verse format")
 	}
 
-	verseNum, err := strconv.Atoi(parts[0])
+	verseNum, endNum, err := ParseVerseNumber(parts[0])
 	if err != nil {
-		return nil, fmt.Errorf("invalid verse number: %w", err)
+		return nil, err
 	}
 
 	verseText := ""
@@ -311,6 +338,7 @@ func (p *Parser) parseVerse(content string, includeFootnotes bool) (*Verse, erro
 
 	verse := &Verse{
 		Number:    verseNum,
+		EndNumber: endNum,
 		Text:      verseText,
 		Footnotes: make([]Footnote, 0),
 	}
@@ -327,6 +355,64 @@ func (p *Parser) parseVerse(content string, includeFootnotes bool) (*Verse, erro
 	return verse, nil
 }
 
+// ParseVerseNumber parses the number of a \v marker, a verse number or a verse bridge
+// such as "1-2". It returns the first and last verse number; the last is 0 for a single verse.
+func ParseVerseNumber(value string) (int, int, error) {
+	first, last, bridge := strings.Cut(value, "-")
+
+	start, err := strconv.Atoi(first)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid verse number: %w", err)
+	}
+	if !bridge {
+		return start, 0, nil
+	}
+
+	end, err := strconv.Atoi(last)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid verse bridge end: %w", err)
+	}
+	if end <= start {
+		return 0, 0, fmt.Errorf("invalid verse bridge %q: end must be after start", value)
+	}
+	return start, end, nil
+}
+
+// parseReference parses reference text relative to the current book and chapter.
+// References that cannot be parsed, such as descriptive text, are left unparsed.
+func parseReference(text, book string, chapter *Chapter) []ref.Range {
+	context := ref.Reference{Book: book}
+	if chapter != nil {
+		context.Chapter = chapter.Number
+	}
+	ranges, err := ref.ParseRelative(text, context)
+	if err != nil {
+		return nil
+	}
+	return ranges
+}
+
+// parseFootnoteReferences parses the \fr location of every footnote in the document
+func parseFootnoteReferences(doc *Document) {
+	book := doc.BookCode()
+	for c := range doc.Chapters {
+		chapter := &doc.Chapters[c]
+		parse := func(footnotes []Footnote) {
+			for f := range footnotes {
+				if footnotes[f].Reference != "" {
+					footnotes[f].ParsedReference = parseReference(footnotes[f].Reference, book, chapter)
+				}
+			}
+		}
+		for s := range chapter.Sections {
+			parse(chapter.Sections[s].Footnotes)
+			for v := range chapter.Sections[s].Verses {
+				parse(chapter.Sections[s].Verses[v].Footnotes)
+			}
+		}
+	}
+}
+
 // getSectionLevel returns the numeric level for section markers
 func (p *Parser) getSectionLevel(tag string) int {
 	switch tag {
@@ -375,7 +461,11 @@ func (p *Parser) removeFootnoteMarkers(text string) string {
 
 // handleTextContinuation handles poetry and paragraph markers that contain text
 // which should be appended to the current verse
-func (p *Parser) handleTextContinuation(marker *Marker, currentSection **Section) {
+func (p *Parser) handleTextContinuation(marker *Marker, currentSection **Section, paragraph *paragraphState) {
+	// Every paragraph or poetry marker starts a new line, even without content
+	paragraph.marker = marker.Tag
+	paragraph.pending = true
+
 	// Skip empty content
 	if marker.Content == "" {
 		return
@@ -390,48 +480,63 @@ func (p *Parser) handleTextContinuation(marker *Marker, currentSection **Section
 	lastVerseIndex := len((*currentSection).Verses) - 1
 	lastVerse := &((*currentSection).Verses[lastVerseIndex])
 
-	// Append the text to the last verse, with a space separator if needed
-	if lastVerse.Text != "" && !strings.HasSuffix(lastVerse.Text, " ") {
-		lastVerse.Text += " "
-	}
-	lastVerse.Text += marker.Content
-
 	// Handle footnotes in the continuation text if enabled
+	content := marker.Content
 	if p.options.IncludeFootnotes {
-		footnotes := p.extractFootnotes(marker.Content)
+		footnotes := p.extractFootnotes(content)
 		lastVerse.Footnotes = append(lastVerse.Footnotes, footnotes...)
 
 		// Remove footnote markers from the appended text
-		cleanedContent := p.removeFootnoteMarkers(marker.Content)
+		content = p.removeFootnoteMarkers(content)
+	}
 
-		// Replace the text with cleaned version
-		if lastVerse.Text != "" && !strings.HasSuffix(strings.TrimSuffix(lastVerse.Text, marker.Content), " ") {
-			lastVerse.Text = strings.TrimSuffix(lastVerse.Text, marker.Content) + " " + cleanedContent
-		} else {
-			lastVerse.Text = strings.TrimSuffix(lastVerse.Text, marker.Content) + cleanedContent
-		}
+	// Append the text to the last verse, with a space separator if needed
+	if lastVerse.Text != "" && !strings.HasSuffix(lastVerse.Text, " ") {
+		lastVerse.Text += " "
+	}
+	lastVerse.Text += content
+
+	// Record the continuation as a new line; a verse whose own text was empty
+	// (e.g. "\v 1" followed by "\q1 text") starts directly on this line
+	line := Line{Marker: marker.Tag, Text: content, Break: true}
+	if n := len(lastVerse.Lines); n > 0 && lastVerse.Lines[n-1].Text == "" {
+		lastVerse.Lines[n-1] = line
+	} else {
+		lastVerse.Lines = append(lastVerse.Lines, line)
 	}
+	paragraph.pending = false
 }
 
 // handleDescriptiveTitle handles descriptive title markers (\d) which provide
 // additional information about psalms or sections
 func (p *Parser) handleDescriptiveTitle(marker *Marker, currentChapter **Chapter, currentSection **Section) {
+	// Footnotes of the title (such as on a musical term of a Psalm title) are kept
+	// with the section, like those of a verse, and never left in the title text
+	title := p.removeFootnoteMarkers(marker.Content)
+	var footnotes []Footnote
+	if p.options.IncludeFootnotes {
+		footnotes = p.extractFootnotes(marker.Content)
+	}
+
 	// For now, treat descriptive titles as section titles if no section exists
 	// or append to section reference if section exists
 	if *currentSection == nil {
 		// Create a new section with the descriptive title
 		section := Section{
 			Level:  1,
-			Title:  marker.Content,
+			Title:  title,
 			Verses: make([]Verse, 0),
 		}
 		*currentSection = &section
 	} else {
 		// If section already has a reference, append; otherwise set it
 		if (*currentSection).Reference != "" {
-			(*currentSection).Reference += "; " + marker.Content
+			(*currentSection).Reference += "; " + title
 		} else {
-			(*currentSection).Reference = marker.Content
+			(*currentSection).Reference = title
 		}
 	}
+	if len(footnotes) > 0 {
+		(*currentSection).Footnotes = append((*currentSection).Footnotes, footnotes...)
+	}
 }
@@ -140,6 +140,40 @@ func TestParseFootnotes(t *testing.T) {
 	}
 }
 
+// TestParseDescriptiveTitleFootnotes tests that footnotes of a \d title are kept with the
+// section instead of in the title text
+func TestParseDescriptiveTitleFootnotes(t *testing.T) {
+	input := `\id PSA - Test Bible
+\c 6
+\s1 A Plea for Mercy
+\d For the choirmaster. According to Sheminith.\f + \fr 6:0 \ft Sheminith is probably a musical term.\f* A Psalm of David.
+\q1
+\v 1 O LORD, do not rebuke me in Your anger.`
+
+	for _, includeFootnotes := range []bool{true, false} {
+		options := DefaultParseOptions()
+		options.IncludeFootnotes = includeFootnotes
+		doc, err := NewParser(options).Parse(strings.NewReader(input), "test.sfm")
+		if err != nil {
+			t.Fatalf("Parse failed: %v", err)
+		}
+
+		section := doc.Chapters[0].Sections[0]
+		if expected := "For the choirmaster. According to Sheminith. A Psalm of David."; section.Reference != expected {
+			t.Errorf("Expected title %q, got %q", expected, section.Reference)
+		}
+		if !includeFootnotes {
+			if len(section.Footnotes) != 0 {
+				t.Errorf("Expected no footnotes, got %+v", section.Footnotes)
+			}
+			continue
+		}
+		if len(section.Footnotes) != 1 || section.Footnotes[0].Reference != "6:0" || section.Footnotes[0].Text != "Sheminith is probably a musical term." {
+			t.Errorf("Expected the Sheminith footnote, got %+v", section.Footnotes)
+		}
+	}
+}
+
 // TestParseMultipleSections tests parsing multiple section levels
 func TestParseMultipleSections(t *testing.T) {
 	input := `\id GEN - Test Bible
@@ -269,3 +303,135 @@ func TestMarkerParsing(t *testing.T) {
 		}
 	}
 }
+
+// TestParsePoetryLines tests that paragraph and poetry markers are recorded as verse lines
+func TestParsePoetryLines(t *testing.T) {
+	input := `\id PSA - Test Bible
+\c 1
+\s1 The Two Paths
+\q1
+\v 1 Blessed is the man
+\q2 who does not walk in the counsel of the wicked,
+\p
+\v 2 Prose verse.
+\v 3 Same paragraph.`
+
+	parser := NewParser(DefaultParseOptions())
+	doc, err := parser.Parse(strings.NewReader(input), "test.sfm")
+
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	verses := doc.Chapters[0].Sections[0].Verses
+	if len(verses) != 3 {
+		t.Fatalf("Expected 3 verses, got %d", len(verses))
+	}
+
+	// Verse 1 spans two poetry lines
+	expectedText := "Blessed is the man who does not walk in the counsel of the wicked,"
+	if verses[0].Text != expectedText {
+		t.Errorf("Expected verse text '%s', got '%s'", expectedText, verses[0].Text)
+	}
+
+	expectedLines := []Line{
+		{Marker: "q1", Text: "Blessed is the man", Break: true},
+		{Marker: "q2", Text: "who does not walk in the counsel of the wicked,", Break: true},
+	}
+	if len(verses[0].Lines) != len(expectedLines) {
+		t.Fatalf("Expected %d lines in verse 1, got %d", len(expectedLines), len(verses[0].Lines))
+	}
+	for i, expected := range expectedLines {
+		if verses[0].Lines[i] != expected {
+			t.Errorf("Line %d: expected %+v, got %+v", i, expected, verses[0].Lines[i])
+		}
+	}
+
+	if verses[0].Lines[1].Indent() != 2 || !verses[0].Lines[1].IsPoetry() {
+		t.Errorf("Expected second line to be poetry at indent 2")
+	}
+
+	// Verse 2 starts a paragraph, verse 3 continues it
+	if !verses[1].Lines[0].Break || verses[1].Lines[0].Marker != "p" {
+		t.Errorf("Expected verse 2 to start a new paragraph, got %+v", verses[1].Lines[0])
+	}
+	if verses[2].Lines[0].Break {
+		t.Errorf("Expected verse 3 to continue the paragraph, got %+v", verses[2].Lines[0])
+	}
+}
+
+// TestParseVerseBridge tests that verse bridges such as \v 1-2 record the last verse
+func TestParseVerseBridge(t *testing.T) {
+	input := `\id GEN - Test Bible
+\c 1
+\p
+\v 1-2 Bridged verses.
+\v 3 Single verse.`
+
+	parser := NewParser(DefaultParseOptions())
+	doc, err := parser.Parse(strings.NewReader(input), "test.sfm")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	verses := doc.Chapters[0].Sections[0].Verses
+	if len(verses) != 2 {
+		t.Fatalf("Expected 2 verses, got %d", len(verses))
+	}
+	if verses[0].Number != 1 || verses[0].EndNumber != 2 || verses[0].LastNumber() != 2 {
+		t.Errorf("Expected verse bridge 1-2, got %d-%d", verses[0].Number, verses[0].EndNumber)
+	}
+	if verses[0].Text != "Bridged verses." {
+		t.Errorf("Expected text 'Bridged verses.', got '%s'", verses[0].Text)
+	}
+	if verses[1].EndNumber != 0 || verses[1].LastNumber() != 3 {
+		t.Errorf("Expected single verse 3, got %d-%d", verses[1].Number, verses[1].EndNumber)
+	}
+
+	for _, invalid := range []string{`\v 2-1 Backwards.`, `\v 1-x Not a number.`} {
+		_, err := parser.Parse(strings.NewReader("\\id GEN\n\\c 1\n"+invalid), "test.sfm")
+		if err == nil {
+			t.Errorf("Expected error for %q", invalid)
+		}
+	}
+}
+
+// TestParseReferences tests parsing \r and \fr references into structured ranges
+func TestParseReferences(t *testing.T) {
+	input := `\id GEN - Test Bible
+\c 2
+\s1 The Seventh Day
+\r (Exodus 16:22–30; Hebrews 4:1–11)
+\v 1 Thus the heavens and the earth were completed.\f + \fr 2:1 \ft Or hosts\f*
+\s1 Untitled
+\r see the notes`
+
+	options := DefaultParseOptions()
+	options.ParseReferences = true
+	doc, err := NewParser(options).Parse(strings.NewReader(input), "test.sfm")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	sections := doc.Chapters[0].Sections
+	parsed := sections[0].ParsedReference
+	if len(parsed) != 2 || parsed[0].Start.Book != "EXO" || parsed[1].End.Verse != 11 {
+		t.Errorf("Expected two parsed cross-references, got %+v", parsed)
+	}
+	footnote := sections[0].Verses[0].Footnotes[0]
+	if len(footnote.ParsedReference) != 1 || footnote.ParsedReference[0].Start.String() != "Genesis 2:1" {
+		t.Errorf("Expected footnote location Genesis 2:1, got %+v", footnote.ParsedReference)
+	}
+	if sections[1].Reference != "see the notes" || sections[1].ParsedReference != nil {
+		t.Errorf("Expected unparsable reference to be kept as text only, got %+v", sections[1])
+	}
+
+	// References are not parsed by default
+	doc, err = NewParser(DefaultParseOptions()).Parse(strings.NewReader(input), "test.sfm")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if doc.Chapters[0].Sections[0].ParsedReference != nil {
+		t.Error("Expected no parsed references without ParseReferences")
+	}
+}
@@ -0,0 +1,92 @@
+package usfm
+
+import "github.com/arenzana/usfmp/pkg/ref"
+
+// Passage returns a copy of the document that keeps only the verses within the given ranges,
+// together with the chapters and section headings they belong to and their footnotes.
+// Sections and chapters without a matching verse are left out, and verse segments ("5a")
+// select the whole verse. A verse bridge is kept if any of its verses is in a range.
+// The second return value reports whether any verse matched. The copy shares the text,
+// footnotes, and table of contents of d.
+//
+// Example:
+//
+//	ranges, err := ref.Parse("Genesis 1:3-5")
+//	if err != nil {
+//		return err
+//	}
+//	passage, ok := doc.Passage(ranges...)
+func (d *Document) Passage(ranges ...ref.Range) (*Document, bool) {
+	book := d.BookCode()
+	result := *d
+	result.Chapters = make([]Chapter, 0)
+
+	for _, chapter := range d.Chapters {
+		selected := Chapter{Number: chapter.Number, Sections: make([]Section, 0)}
+		for _, section := range chapter.Sections {
+			verses := make([]Verse, 0)
+			for _, verse := range section.Verses {
+				if verseInRanges(book, chapter.Number, verse, ranges) {
+					verses = append(verses, verse)
+				}
+			}
+			if len(verses) > 0 {
+				section.Verses = verses
+				selected.Sections = append(selected.Sections, section)
+			}
+		}
+		if len(selected.Sections) > 0 {
+			result.Chapters = append(result.Chapters, selected)
+		}
+	}
+
+	return &result, len(result.Chapters) > 0
+}
+
+// Passages looks up references across the documents of several books, such as a whole
+// translation. Consecutive ranges within the same book are looked up together, and each
+// group yields one trimmed document per book it covers (see Document.Passage) in the order
+// of the ranges, so "Heb 11:1, 6; Gen 1" lists Hebrews before Genesis.
+// Ranges that match no verse are skipped.
+func Passages(documents []*Document, ranges ...ref.Range) []*Document {
+	var result []*Document
+	for start := 0; start < len(ranges); {
+		// Group the following ranges that stay within the same book
+		end := start + 1
+		if book := ranges[start].Start.Book; book == ranges[start].Last().Book {
+			for end < len(ranges) && ranges[end].Start.Book == book && ranges[end].Last().Book == book {
+				end++
+			}
+		}
+		group := ranges[start:end]
+		start = end
+
+		for _, doc := range documents {
+			if !bookInRange(doc.BookCode(), group[0]) {
+				continue
+			}
+			if passage, ok := doc.Passage(group...); ok {
+				result = append(result, passage)
+			}
+		}
+	}
+	return result
+}
+
+// bookInRange reports whether a range starts, ends, or spans the given book
+func bookInRange(book string, r ref.Range) bool {
+	return r.Contains(ref.Reference{Book: book}) ||
+		book == r.Start.Book || book == r.Last().Book
+}
+
+// verseInRanges reports whether any verse of a verse or verse bridge is within one of the ranges
+func verseInRanges(book string, chapter int, verse Verse, ranges []ref.Range) bool {
+	for _, r := range ranges {
+		for number := verse.Number; number <= verse.LastNumber(); number++ {
+			if r.Contains(ref.Reference{Book: book, Chapter: chapter, Verse: number}) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,120 @@
+package usfm
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/arenzana/usfmp/pkg/ref"
+)
+
+// parsePassageTestDocument parses a book with two chapters and several sections
+func parsePassageTestDocument(t *testing.T, code string) *Document {
+	t.Helper()
+
+	input := `\id ` + code + ` - Test Bible
+\c 1
+\s1 First
+\v 1 One.
+\v 2 Two.\f + \fr 1:2 \ft A note.\f*
+\s1 Second
+\v 3 Three.
+\v 4-5 Four and five.
+\c 2
+\s1 Third
+\v 1 Chapter two, one.
+\v 2 Chapter two, two.`
+
+	doc, err := NewParser(DefaultParseOptions()).Parse(strings.NewReader(input), code+".sfm")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	return doc
+}
+
+// passageVerses lists the chapter:verse numbers and section titles of a passage
+func passageVerses(doc *Document) string {
+	var result []string
+	for _, chapter := range doc.Chapters {
+		for _, section := range chapter.Sections {
+			result = append(result, "["+section.Title+"]")
+			for _, verse := range section.Verses {
+				result = append(result, ref.Reference{Chapter: chapter.Number, Verse: verse.Number}.String())
+			}
+		}
+	}
+	return strings.Join(result, " ")
+}
+
+// TestDocumentPassage tests selecting verses of a single document
+func TestDocumentPassage(t *testing.T) {
+	doc := parsePassageTestDocument(t, "GEN")
+
+	testCases := []struct {
+		reference string
+		expected  string
+	}{
+		{"Gen 1:2", "[First] 1:2"},
+		{"Gen 1:2-3", "[First] 1:2 [Second] 1:3"},
+		{"Gen 1:5", "[Second] 1:4"},
+		{"Gen 1:4b-2:1", "[Second] 1:4 [Third] 2:1"},
+		{"Gen 2", "[Third] 2:1 2:2"},
+		{"Gen 1:1, 2:2", "[First] 1:1 [Third] 2:2"},
+		{"Genesis", "[First] 1:1 1:2 [Second] 1:3 1:4 [Third] 2:1 2:2"},
+	}
+
+	for _, tc := range testCases {
+		ranges, err := ref.Parse(tc.reference)
+		if err != nil {
+			t.Fatalf("Parse(%q) failed: %v", tc.reference, err)
+		}
+		passage, ok := doc.Passage(ranges...)
+		if !ok {
+			t.Errorf("%s: expected verses", tc.reference)
+			continue
+		}
+		if result := passageVerses(passage); result != tc.expected {
+			t.Errorf("%s: expected %s, got %s", tc.reference, tc.expected, result)
+		}
+	}
+
+	// Footnotes stay with their verses, and the document itself is unchanged
+	ranges, _ := ref.Parse("Gen 1:2")
+	passage, _ := doc.Passage(ranges...)
+	if footnotes := passage.Chapters[0].Sections[0].Verses[0].Footnotes; len(footnotes) != 1 || footnotes[0].Text != "A note." {
+		t.Errorf("Expected the footnote of verse 2, got %+v", footnotes)
+	}
+	if len(doc.Chapters) != 2 || len(doc.Chapters[0].Sections[0].Verses) != 2 {
+		t.Error("Expected Passage to leave the document unchanged")
+	}
+
+	for _, reference := range []string{"Gen 3:1", "Exodus 1:1"} {
+		ranges, _ := ref.Parse(reference)
+		if passage, ok := doc.Passage(ranges...); ok || len(passage.Chapters) != 0 {
+			t.Errorf("%s: expected no verses, got %s", reference, passageVerses(passage))
+		}
+	}
+}
+
+// TestPassages tests looking up references across several books
+func TestPassages(t *testing.T) {
+	documents := []*Document{
+		parsePassageTestDocument(t, "GEN"),
+		parsePassageTestDocument(t, "EXO"),
+		parsePassageTestDocument(t, "LEV"),
+	}
+
+	ranges, err := ref.Parse("Lev 1:1, 3; Gen 2:2-Exo 1:1; Num 1:1")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	passages := Passages(documents, ranges...)
+
+	var books []string
+	for _, passage := range passages {
+		books = append(books, passage.BookCode()+" "+passageVerses(passage))
+	}
+	expected := "LEV [First] 1:1 [Second] 1:3; GEN [Third] 2:2; EXO [First] 1:1"
+	if result := strings.Join(books, "; "); result != expected {
+		t.Errorf("Expected %s, got %s", expected, result)
+	}
+}
@@ -0,0 +1,57 @@
+package usfm
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Token is a marker in a line of USFM source, with the text that follows it up to the
+// next marker on the same line as its Content.
+type Token struct {
+	Marker
+	Closing   bool `json:"closing,omitempty"`   // Whether the marker is a closing marker such as \w*
+	Milestone bool `json:"milestone,omitempty"` // Whether the marker is a milestone such as \qt-s
+}
+
+// markerPattern matches a marker such as \v, \+w, \w*, or the milestone \qt-s,
+// capturing the tag, the milestone suffix, and the closing asterisk
+const markerPattern = `\\\+?([a-z][a-z0-9]*)(-[se])?(\*?)`
+
+// tokenRegex matches the markers of a line
+var tokenRegex = regexp.MustCompile(markerPattern)
+
+// Tokenize splits a line of USFM source into its markers, both the paragraph marker at
+// the start of the line and the character and note markers within it. The tag of a
+// token has no backslash, plus sign, or asterisk (\+w* is "w"). Text before the first
+// marker continues the line before it and is returned as a token with an empty tag.
+//
+// Example:
+//
+//	tokens := usfm.Tokenize(`\v 1 In \w God\w* we trust`, 12)
+//	// tokens[0] = {Tag: "v", Content: " 1 In ", Line: 12}
+//	// tokens[2] = {Tag: "w", Content: " we trust", Line: 12, Closing: true}
+func Tokenize(line string, lineNumber int) []Token {
+	matches := tokenRegex.FindAllStringSubmatchIndex(line, -1)
+
+	var tokens []Token
+	start := len(line)
+	if len(matches) > 0 {
+		start = matches[0][0]
+	}
+	if strings.TrimSpace(line[:start]) != "" {
+		tokens = append(tokens, Token{Marker: Marker{Content: line[:start], Line: lineNumber}})
+	}
+
+	for i, m := range matches {
+		end := len(line)
+		if i+1 < len(matches) {
+			end = matches[i+1][0]
+		}
+		tokens = append(tokens, Token{
+			Marker:    Marker{Tag: line[m[2]:m[3]], Content: line[m[1]:end], Line: lineNumber},
+			Milestone: m[4] >= 0,
+			Closing:   m[6] < m[7],
+		})
+	}
+	return tokens
+}
@@ -0,0 +1,41 @@
+package usfm
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// TestTokenize tests splitting a line into its markers
+func TestTokenize(t *testing.T) {
+	testCases := []struct {
+		line     string
+		expected []string
+	}{
+		{`\v 1 In \w God|strong="H0430"\w* we trust`, []string{`v " 1 In "`, `w " God|strong=\"H0430\""`, `w* " we trust"`}},
+		{`\q1 \wj \+nd Lord\+nd*\wj*`, []string{`q1 " "`, `wj " "`, `nd " Lord"`, `nd* ""`, `wj* ""`}},
+		{`\qt-s |who="Jesus"\*Follow me.`, []string{`qt-s " |who=\"Jesus\"\\*Follow me."`}},
+		{`continued text \f + \fr 1:1\f*`, []string{` "continued text "`, `f " + "`, `fr " 1:1"`, `f* ""`}},
+		{`   `, nil},
+	}
+
+	for _, tc := range testCases {
+		var result []string
+		for _, token := range Tokenize(tc.line, 7) {
+			if token.Line != 7 {
+				t.Errorf("%s: expected line 7, got %d", tc.line, token.Line)
+			}
+			tag := token.Tag
+			if token.Milestone {
+				tag += "-s"
+			}
+			if token.Closing {
+				tag += "*"
+			}
+			result = append(result, fmt.Sprintf("%s %q", tag, token.Content))
+		}
+		if strings.Join(result, "\n") != strings.Join(tc.expected, "\n") {
+			t.Errorf("%s: expected\n%s\ngot\n%s", tc.line, strings.Join(tc.expected, "\n"), strings.Join(result, "\n"))
+		}
+	}
+}
@@ -19,7 +19,13 @@
 //	}
 package usfm
 
-import "time"
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/arenzana/usfmp/pkg/ref"
+)
 
 // Document represents a complete USFM document containing all parsed content.
 // It includes metadata, structure, and the full hierarchy of chapters, sections, and verses.
@@ -54,26 +60,82 @@ type Chapter struct {
 // Section represents a section within a chapter, typically marked by \s1, \s2, or \s3.
 // Sections group related verses together and may include cross-references.
 type Section struct {
-	Level     int     `json:"level"`               // Section level: 1 (\s1), 2 (\s2), or 3 (\s3)
-	Title     string  `json:"title"`               // Section title text
-	Reference string  `json:"reference,omitempty"` // Cross-reference text from \r marker
-	Verses    []Verse `json:"verses"`              // Verses contained in this section
+	Level           int         `json:"level"`                      // Section level: 1 (\s1), 2 (\s2), or 3 (\s3)
+	Title           string      `json:"title"`                      // Section title text
+	Reference       string      `json:"reference,omitempty"`        // Cross-reference text from \r marker
+	ParsedReference []ref.Range `json:"parsed_reference,omitempty"` // Cross-references parsed from Reference (with ParseReferences)
+	Footnotes       []Footnote  `json:"footnotes,omitempty"`        // Footnotes of a \d descriptive title (with IncludeFootnotes)
+	Verses          []Verse     `json:"verses"`                     // Verses contained in this section
 }
 
 // Verse represents a single verse from a \v marker.
 // Verses contain the main biblical text and may include footnotes.
 type Verse struct {
-	Number    int        `json:"number"`              // Verse number from \v marker
-	Text      string     `json:"text"`                // Main verse text with footnotes removed
-	Footnotes []Footnote `json:"footnotes,omitempty"` // Footnotes extracted from the text
+	Number    int        `json:"number"`               // Verse number from \v marker (the first verse of a bridge)
+	EndNumber int        `json:"end_number,omitempty"` // Last verse of a verse bridge such as \v 1-2, or 0 for a single verse
+	Text      string     `json:"text"`                 // Main verse text with footnotes removed
+	Footnotes []Footnote `json:"footnotes,omitempty"`  // Footnotes extracted from the text
+	Lines     []Line     `json:"-"`                    // Verse text split by paragraph and poetry markers, for layout formats; not written to JSON
+}
+
+// LastNumber returns the last verse number covered by the verse:
+// the end of a verse bridge, or the verse number itself.
+func (v Verse) LastNumber() int {
+	if v.EndNumber > 0 {
+		return v.EndNumber
+	}
+	return v.Number
+}
+
+// Line represents the part of a verse that falls within one paragraph or poetry line.
+// A verse that spans several poetry lines (\q1, \q2) has one Line per poetry line,
+// which lets formatters reproduce the original line breaks and indentation.
+type Line struct {
+	Marker string `json:"marker,omitempty"` // Paragraph or poetry marker in effect (e.g., "p", "m", "q1", "q2")
+	Text   string `json:"text"`             // Line text with footnotes removed
+	Break  bool   `json:"break,omitempty"`  // Whether the line starts a new paragraph or poetry line
+}
+
+// IsPoetry reports whether the line belongs to a poetry marker (\q, \q1, \q2, ...).
+func (l Line) IsPoetry() bool {
+	return strings.HasPrefix(l.Marker, "q")
+}
+
+// Indent returns the indentation level of the line: the poetry level for \q markers
+// and the indent level for \pi markers, or 0 for regular paragraphs.
+//
+// Example:
+//
+//	usfm.Line{Marker: "q2"}.Indent() // 2
+//	usfm.Line{Marker: "p"}.Indent()  // 0
+func (l Line) Indent() int {
+	var level string
+	switch {
+	case strings.HasPrefix(l.Marker, "q"):
+		level = strings.TrimPrefix(l.Marker, "q")
+	case strings.HasPrefix(l.Marker, "pi"):
+		level = strings.TrimPrefix(l.Marker, "pi")
+	default:
+		return 0
+	}
+
+	if level == "" {
+		return 1
+	}
+	n, err := strconv.Atoi(level)
+	if err != nil {
+		return 1
+	}
+	return n
 }
 
 // Footnote represents a footnote within a verse, marked by \f...\f* tags.
 // Footnotes provide additional information about the biblical text.
 type Footnote struct {
-	Caller    string `json:"caller"`    // Footnote caller symbol (usually "+")
-	Reference string `json:"reference"` // Reference text from \fr marker
-	Text      string `json:"text"`      // Footnote content from \ft marker
+	Caller          string      `json:"caller"`                     // Footnote caller symbol (usually "+")
+	Reference       string      `json:"reference"`                  // Reference text from \fr marker
+	Text            string      `json:"text"`                       // Footnote content from \ft marker
+	ParsedReference []ref.Range `json:"parsed_reference,omitempty"` // Location parsed from Reference (with ParseReferences)
 }
 
 // Marker represents a parsed USFM marker with its content.
@@ -90,6 +152,7 @@ type ParseOptions struct {
 	StrictMode        bool // Whether to fail on unknown/unrecognized markers
 	IncludeFootnotes  bool // Whether to parse and extract footnotes from verse text
 	IncludeReferences bool // Whether to parse cross-reference markers (\r)
+	ParseReferences   bool // Whether to parse \r and \fr references into structured ranges (ParsedReference)
 }
 
 // DefaultParseOptions returns sensible default parsing options.
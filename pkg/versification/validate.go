@@ -0,0 +1,170 @@
+package versification
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/arenzana/usfmp/pkg/ref"
+	"github.com/arenzana/usfmp/pkg/usfm"
+)
+
+// Kinds of differences between a text and a versification
+const (
+	UnknownBook    = "unknown-book"    // The book is not covered by the scheme
+	MissingChapter = "missing-chapter" // A chapter of the scheme is not in the text
+	ExtraChapter   = "extra-chapter"   // A chapter of the text is not in the scheme
+	MissingVerse   = "missing-verse"   // A verse of the scheme is not in the text
+	ExtraVerse     = "extra-verse"     // A verse of the text is beyond the verses of its chapter in the scheme
+	ExcludedVerse  = "excluded-verse"  // A verse of the text is excluded from the scheme
+)
+
+// messageStyle writes the references of differences with their chapter even in books
+// with a single chapter, where "Obadiah 2" could be a chapter of the text or a verse
+var messageStyle = func() ref.Style {
+	style := ref.Default
+	style.SingleChapter = false
+	return style
+}()
+
+// Difference is a chapter or verse, or a run of them, where a text does not follow a versification.
+type Difference struct {
+	Kind    string    `json:"kind"`      // Kind of difference (e.g., MissingVerse)
+	Range   ref.Range `json:"reference"` // Chapters or verses that differ
+	Message string    `json:"message"`   // Description of the difference
+}
+
+// String returns the message of the difference.
+func (d Difference) String() string {
+	return d.Message
+}
+
+// Validate compares the chapters and verses of a book with the scheme. Verse bridges count
+// as all the verses they span. Differences are returned in canonical order.
+func (s *Scheme) Validate(doc *usfm.Document) []Difference {
+	book := doc.BookCode()
+
+	var chapters []int
+	for _, chapter := range doc.Chapters {
+		chapters = append(chapters, chapter.Number)
+	}
+	differences := s.CheckBook(book, chapters)
+	if len(differences) > 0 && differences[0].Kind == UnknownBook {
+		return differences
+	}
+
+	var verses []Difference
+	for _, chapter := range doc.Chapters {
+		if chapter.Number > s.Chapters(book) {
+			continue
+		}
+		var numbers []int
+		for _, section := range chapter.Sections {
+			for _, verse := range section.Verses {
+				for number := verse.Number; number <= max(verse.Number, verse.EndNumber); number++ {
+					numbers = append(numbers, number)
+				}
+			}
+		}
+		verses = append(verses, s.CheckChapter(book, chapter.Number, numbers)...)
+	}
+
+	differences = append(differences, verses...)
+	slices.SortStableFunc(differences, func(a, b Difference) int {
+		return a.Range.Start.Compare(b.Range.Start)
+	})
+	return differences
+}
+
+// CheckBook compares the chapter numbers of a book with the scheme.
+func (s *Scheme) CheckBook(book string, chapters []int) []Difference {
+	last := s.Chapters(book)
+	if last == 0 {
+		return []Difference{{
+			Kind:    UnknownBook,
+			Range:   ref.Range{Start: ref.Reference{Book: book}},
+			Message: fmt.Sprintf("book %s is not in the %s versification", book, s.Name),
+		}}
+	}
+
+	present := make(map[int]bool, len(chapters))
+	var extra []int
+	for _, chapter := range chapters {
+		if chapter > last && !present[chapter] {
+			extra = append(extra, chapter)
+		}
+		present[chapter] = true
+	}
+	var missing []int
+	for chapter := 1; chapter <= last; chapter++ {
+		if !present[chapter] {
+			missing = append(missing, chapter)
+		}
+	}
+	slices.Sort(extra)
+
+	chapter := func(number int) ref.Reference {
+		return ref.Reference{Book: book, Chapter: number}
+	}
+	differences := group(MissingChapter, missing, chapter, "missing")
+	return append(differences, group(ExtraChapter, extra, chapter, "not in the "+s.Name+" versification")...)
+}
+
+// CheckChapter compares the verse numbers of a chapter with the scheme.
+func (s *Scheme) CheckChapter(book string, chapter int, verses []int) []Difference {
+	last := s.Verses(book, chapter)
+	present := make(map[int]bool, len(verses))
+	var extra, excluded []int
+	for _, number := range verses {
+		if number < 1 || present[number] {
+			continue
+		}
+		present[number] = true
+		verse := ref.Reference{Book: book, Chapter: chapter, Verse: number}
+		switch {
+		case number > last:
+			extra = append(extra, number)
+		case s.Excluded(verse):
+			excluded = append(excluded, number)
+		}
+	}
+	var missing []int
+	for number := 1; number <= last; number++ {
+		if !present[number] && !s.Excluded(ref.Reference{Book: book, Chapter: chapter, Verse: number}) {
+			missing = append(missing, number)
+		}
+	}
+	slices.Sort(extra)
+	slices.Sort(excluded)
+
+	verse := func(number int) ref.Reference {
+		return ref.Reference{Book: book, Chapter: chapter, Verse: number}
+	}
+	differences := group(MissingVerse, missing, verse, "missing")
+	differences = append(differences, group(ExtraVerse, extra, verse, "not in the "+s.Name+" versification")...)
+	return append(differences, group(ExcludedVerse, excluded, verse, "excluded from the "+s.Name+" versification")...)
+}
+
+// group turns sorted chapter or verse numbers into differences, one for each run of consecutive numbers
+func group(kind string, numbers []int, reference func(int) ref.Reference, description string) []Difference {
+	var differences []Difference
+	for i := 0; i < len(numbers); {
+		j := i
+		for j+1 < len(numbers) && numbers[j+1] == numbers[j]+1 {
+			j++
+		}
+
+		r := ref.Range{Start: reference(numbers[i])}
+		verb := "is"
+		if j > i {
+			r.End = reference(numbers[j])
+			verb = "are"
+		}
+		differences = append(differences, Difference{
+			Kind:    kind,
+			Range:   r,
+			Message: fmt.Sprintf("%s %s %s", ref.Format([]ref.Range{r}, messageStyle), verb, description),
+		})
+		i = j + 1
+	}
+	return differences
+}
@@ -0,0 +1,433 @@
+// Package versification describes how Bible translations divide books into chapters and verses.
+//
+// A Scheme lists the number of verses in each chapter of each book, which is what tools
+// need to line up translations verse by verse or to notice missing verses. Schemes are
+// read from Paratext .vrs files, and map their verses to the Original versification so that
+// a verse can be found in any other scheme.
+//
+// Example:
+//
+//	scheme := versification.English
+//	fmt.Println(scheme.Verses("GEN", 1)) // 31
+//
+//	verse, _ := versification.Map(ref.Reference{Book: "PSA", Chapter: 51, Verse: 1}, versification.English, versification.Vulgate)
+//	fmt.Println(verse) // Psalms 50:3
+package versification
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/arenzana/usfmp/pkg/ref"
+	"github.com/arenzana/usfmp/pkg/usfm"
+)
+
+// Scheme is a versification: the books it covers and the number of verses in each of their chapters.
+type Scheme struct {
+	Name         string                          // Name of the scheme (e.g., "English")
+	books        []string                        // Book codes in the order they were listed
+	verses       map[string][]int                // Last verse number of each chapter, by book code
+	excluded     map[ref.Reference]bool          // Verses left out of the scheme, such as -MAT 17:21
+	toOriginal   map[ref.Reference]ref.Reference // Verses numbered differently in the Original versification
+	fromOriginal map[ref.Reference]ref.Reference // The reverse of toOriginal
+}
+
+// Books returns the codes of the books covered by the scheme, in canonical order.
+func (s *Scheme) Books() []string {
+	return append([]string(nil), s.books...)
+}
+
+// Chapters returns the number of chapters of a book, or 0 if the book is not covered by the scheme.
+func (s *Scheme) Chapters(book string) int {
+	return len(s.verses[strings.ToUpper(book)])
+}
+
+// Verses returns the number of verses of a chapter, or 0 if the chapter is not covered by the scheme.
+func (s *Scheme) Verses(book string, chapter int) int {
+	chapters := s.verses[strings.ToUpper(book)]
+	if chapter < 1 || chapter > len(chapters) {
+		return 0
+	}
+	return chapters[chapter-1]
+}
+
+// Excluded reports whether a verse is left out of the scheme, as verses that modern
+// translations omit (MAT 17:21) are in some custom versifications.
+func (s *Scheme) Excluded(verse ref.Reference) bool {
+	return s.excluded[verseKey(verse)]
+}
+
+// Contains reports whether a verse is part of the scheme.
+func (s *Scheme) Contains(verse ref.Reference) bool {
+	return verse.Verse >= 1 && verse.Verse <= s.Verses(verse.Book, verse.Chapter) && !s.Excluded(verse)
+}
+
+// ToOriginal returns the verse of the Original versification that a verse of the scheme
+// stands for. Verses that the scheme numbers like the Original, chapters, and books are
+// returned unchanged.
+func (s *Scheme) ToOriginal(verse ref.Reference) ref.Reference {
+	if original, ok := s.toOriginal[verseKey(verse)]; ok {
+		original.Segment = verse.Segment
+		return original
+	}
+	return verse
+}
+
+// FromOriginal returns the verse of the scheme that stands for a verse of the Original
+// versification. It reports false if the scheme has no such verse, such as for a Psalm
+// title in a scheme that does not number titles.
+func (s *Scheme) FromOriginal(verse ref.Reference) (ref.Reference, bool) {
+	if verse.Verse == 0 {
+		return verse, true
+	}
+	key := verseKey(verse)
+	if mapped, ok := s.fromOriginal[key]; ok {
+		mapped.Segment = verse.Segment
+		return mapped, true
+	}
+	if _, ok := s.toOriginal[key]; ok {
+		// The scheme uses this number for a different verse of the Original
+		return ref.Reference{}, false
+	}
+	if _, ok := s.verses[key.Book]; ok && !s.Contains(key) {
+		return ref.Reference{}, false
+	}
+	return verse, true
+}
+
+// Map returns the verse of the scheme to that stands for a verse of the scheme from,
+// going through the Original versification (e.g., English Psalm 51:1 is Vulgate Psalm 50:3).
+// It reports false if the verse has no counterpart in the scheme to.
+func Map(verse ref.Reference, from, to *Scheme) (ref.Reference, bool) {
+	return to.FromOriginal(from.ToOriginal(verse))
+}
+
+// verseKey identifies a whole verse, without its segment
+func verseKey(verse ref.Reference) ref.Reference {
+	return ref.Reference{Book: strings.ToUpper(verse.Book), Chapter: verse.Chapter, Verse: verse.Verse}
+}
+
+var (
+	//go:embed english.vrs
+	englishVRS string
+	//go:embed original.vrs
+	originalVRS string
+	//go:embed septuagint.vrs
+	septuagintVRS string
+	//go:embed vulgate.vrs
+	vulgateVRS string
+)
+
+// English is the versification of the King James Version and most English translations,
+// covering the 66 books of the Protestant canon (31,102 verses).
+var English = mustParse("English", englishVRS)
+
+// Original is the versification of the Hebrew Masoretic text and the Greek New Testament,
+// which counts Psalm titles as verses (31,171 verses). The other schemes map their verses to it.
+var Original = mustParse("Original", originalVRS)
+
+// Septuagint is the versification of the Greek Old Testament: the Psalms in the Greek
+// numbering, with Psalm 151, the deuterocanonical books (Tobit through the Prayer of Manasseh),
+// and the other books as in the Original versification.
+var Septuagint = mustParse("Septuagint", septuagintVRS)
+
+// Vulgate is the versification of the Latin Vulgate: the Psalms in the Greek numbering, Esther
+// with the Greek additions, Daniel with the Prayer of Azariah, Susanna, and Bel, Baruch with the
+// Letter of Jeremiah, the other deuterocanonical books, and the remaining books as in the
+// English versification.
+var Vulgate = mustParse("Vulgate", vulgateVRS)
+
+// Lookup returns the built-in scheme with the given name, ignoring case: "english", "original",
+// "septuagint", or "vulgate", or the Paratext abbreviations "eng", "org", "lxx", and "vul".
+func Lookup(name string) (*Scheme, bool) {
+	switch strings.ToLower(name) {
+	case "english", "eng":
+		return English, true
+	case "original", "org":
+		return Original, true
+	case "septuagint", "lxx":
+		return Septuagint, true
+	case "vulgate", "vul":
+		return Vulgate, true
+	}
+	return nil, false
+}
+
+// mustParse parses a built-in scheme and panics if it is invalid
+func mustParse(name, data string) *Scheme {
+	scheme, err := parse(name, strings.NewReader(data))
+	if err != nil {
+		panic(fmt.Sprintf("versification: invalid built-in scheme %s: %v", name, err))
+	}
+	return scheme
+}
+
+// Load reads a scheme in the Paratext .vrs format:
+//
+//	# Comment
+//	GEN 1:31 2:25 3:24        Book line: CHAPTER:LAST_VERSE for every chapter of a book
+//	-MAT 17:21                Excluded verse, left out of the scheme
+//	PSA 51:1-19 = PSA 51:3-21 Mapping of verses to the Original versification
+//	*MAT 1:1,-,a,b            Verse segments (ignored)
+func Load(name string, r io.Reader) (*Scheme, error) {
+	return parse(name, r)
+}
+
+// LoadFile reads a scheme from a Paratext .vrs file, named after the file (e.g., "lxx" for lxx.vrs).
+func LoadFile(path string) (*Scheme, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open versification: %w", err)
+	}
+	defer file.Close()
+
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	scheme, err := parse(name, file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return scheme, nil
+}
+
+// Customize returns a copy of the scheme with the changes of a Paratext custom.vrs file
+// applied: book lines change the verse counts of the chapters they list (and may add
+// chapters or books), excluded verses are left out, and mappings replace those of the scheme.
+func (s *Scheme) Customize(r io.Reader) (*Scheme, error) {
+	custom := &Scheme{
+		Name:         s.Name + " (custom)",
+		books:        s.Books(),
+		verses:       make(map[string][]int, len(s.verses)),
+		excluded:     make(map[ref.Reference]bool, len(s.excluded)),
+		toOriginal:   make(map[ref.Reference]ref.Reference, len(s.toOriginal)),
+		fromOriginal: make(map[ref.Reference]ref.Reference, len(s.fromOriginal)),
+	}
+	for book, chapters := range s.verses {
+		custom.verses[book] = append([]int(nil), chapters...)
+	}
+	for verse := range s.excluded {
+		custom.excluded[verse] = true
+	}
+	for verse, original := range s.toOriginal {
+		custom.toOriginal[verse] = original
+	}
+	for original, verse := range s.fromOriginal {
+		custom.fromOriginal[original] = verse
+	}
+
+	if err := custom.read(r, true); err != nil {
+		return nil, err
+	}
+	return custom, nil
+}
+
+// parse reads a scheme in the Paratext .vrs format (see Load)
+func parse(name string, r io.Reader) (*Scheme, error) {
+	scheme := &Scheme{
+		Name:         name,
+		verses:       make(map[string][]int),
+		excluded:     make(map[ref.Reference]bool),
+		toOriginal:   make(map[ref.Reference]ref.Reference),
+		fromOriginal: make(map[ref.Reference]ref.Reference),
+	}
+	if err := scheme.read(r, false); err != nil {
+		return nil, err
+	}
+	return scheme, nil
+}
+
+// read adds the lines of a .vrs file to the scheme. Book lines of a custom file change
+// the listed chapters of a book; otherwise they list every chapter of a new book.
+func (s *Scheme) read(r io.Reader, custom bool) error {
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(strings.TrimPrefix(scanner.Text(), "\ufeff"))
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "*") {
+			continue
+		}
+
+		var err error
+		switch {
+		case strings.HasPrefix(line, "-"):
+			err = s.readExcluded(strings.TrimPrefix(line, "-"))
+		case strings.Contains(line, "="):
+			err = s.readMapping(line)
+		case custom:
+			err = s.readCustomBook(line)
+		default:
+			err = s.readBook(line)
+		}
+		if err != nil {
+			return fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read versification: %w", err)
+	}
+	return nil
+}
+
+// readBook reads a book line listing every chapter of a book
+func (s *Scheme) readBook(line string) error {
+	fields := strings.Fields(line)
+	book, err := bookCode(fields[0])
+	if err != nil {
+		return err
+	}
+	if _, ok := s.verses[book]; ok {
+		return fmt.Errorf("book %s is listed twice", book)
+	}
+
+	chapters := make([]int, 0, len(fields)-1)
+	for _, field := range fields[1:] {
+		chapter, verses, err := parseChapter(field)
+		if err != nil {
+			return err
+		}
+		if chapter != len(chapters)+1 {
+			return fmt.Errorf("expected chapter %d of %s, got %d", len(chapters)+1, book, chapter)
+		}
+		chapters = append(chapters, verses)
+	}
+
+	s.books = append(s.books, book)
+	s.verses[book] = chapters
+	return nil
+}
+
+// readCustomBook reads a book line of a custom.vrs file, which changes the chapters it lists
+func (s *Scheme) readCustomBook(line string) error {
+	fields := strings.Fields(line)
+	book, err := bookCode(fields[0])
+	if err != nil {
+		return err
+	}
+	if _, ok := s.verses[book]; !ok {
+		s.books = append(s.books, book)
+	}
+
+	chapters := s.verses[book]
+	for _, field := range fields[1:] {
+		chapter, verses, err := parseChapter(field)
+		if err != nil {
+			return err
+		}
+		for len(chapters) < chapter {
+			chapters = append(chapters, 0)
+		}
+		chapters[chapter-1] = verses
+	}
+	s.verses[book] = chapters
+	return nil
+}
+
+// readExcluded reads the verse or verses of an excluded verse line such as -MAT 17:21
+func (s *Scheme) readExcluded(text string) error {
+	verses, err := parseVerses(text)
+	if err != nil {
+		return err
+	}
+	for _, verse := range verses {
+		s.excluded[verse] = true
+	}
+	return nil
+}
+
+// readMapping reads a mapping line such as "PSA 51:1-19 = PSA 51:3-21", whose left side
+// is numbered in the scheme and whose right side in the Original versification. When one
+// side has fewer verses, its last verse stands for the remaining verses of the other side.
+func (s *Scheme) readMapping(line string) error {
+	left, right, _ := strings.Cut(line, "=")
+	verses, err := parseVerses(strings.TrimPrefix(strings.TrimSpace(left), "&"))
+	if err != nil {
+		return err
+	}
+	originals, err := parseVerses(strings.TrimPrefix(strings.TrimSpace(right), "&"))
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < max(len(verses), len(originals)); i++ {
+		verse := verses[min(i, len(verses)-1)]
+		original := originals[min(i, len(originals)-1)]
+		if i < len(verses) {
+			s.toOriginal[verse] = original
+		}
+		if _, ok := s.fromOriginal[original]; !ok || i < len(originals) {
+			s.fromOriginal[original] = verse
+		}
+	}
+	return nil
+}
+
+// parseVerses parses a verse or a range of verses within a chapter, such as "GEN 32:1-32"
+func parseVerses(text string) ([]ref.Reference, error) {
+	fields := strings.Fields(text)
+	if len(fields) != 2 {
+		return nil, fmt.Errorf("invalid verse reference %q", text)
+	}
+	book, err := bookCode(fields[0])
+	if err != nil {
+		return nil, err
+	}
+
+	chapterText, verseText, ok := strings.Cut(fields[1], ":")
+	if !ok {
+		return nil, fmt.Errorf("invalid verse reference %q", text)
+	}
+	firstText, lastText, isRange := strings.Cut(verseText, "-")
+	if !isRange {
+		lastText = firstText
+	}
+	chapter, err := strconv.Atoi(chapterText)
+	if err != nil || chapter < 1 {
+		return nil, fmt.Errorf("invalid chapter number in %q", text)
+	}
+	first, err := strconv.Atoi(firstText)
+	if err != nil || first < 0 {
+		return nil, fmt.Errorf("invalid verse number in %q", text)
+	}
+	last, err := strconv.Atoi(lastText)
+	if err != nil || last < first {
+		return nil, fmt.Errorf("invalid verse range in %q", text)
+	}
+
+	verses := make([]ref.Reference, 0, last-first+1)
+	for verse := first; verse <= last; verse++ {
+		verses = append(verses, ref.Reference{Book: book, Chapter: chapter, Verse: verse})
+	}
+	return verses, nil
+}
+
+// bookCode checks that a book code of a .vrs line is in the book registry
+func bookCode(code string) (string, error) {
+	book := strings.ToUpper(code)
+	if _, ok := usfm.LookupBook(book); !ok {
+		return "", fmt.Errorf("unknown book code %q", code)
+	}
+	return book, nil
+}
+
+// parseChapter parses a CHAPTER:LAST_VERSE entry of a .vrs book line
+func parseChapter(field string) (int, int, error) {
+	chapterText, verseText, ok := strings.Cut(field, ":")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid chapter entry %q", field)
+	}
+	chapter, err := strconv.Atoi(chapterText)
+	if err != nil || chapter < 1 {
+		return 0, 0, fmt.Errorf("invalid chapter number in %q", field)
+	}
+	verses, err := strconv.Atoi(verseText)
+	if err != nil || verses < 1 {
+		return 0, 0, fmt.Errorf("invalid verse count in %q", field)
+	}
+	return chapter, verses, nil
+}
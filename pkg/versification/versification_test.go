@@ -0,0 +1,302 @@
+package versification
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/arenzana/usfmp/pkg/ref"
+	"github.com/arenzana/usfmp/pkg/usfm"
+)
+
+// TestEnglish tests the built-in English versification
+func TestEnglish(t *testing.T) {
+	books := English.Books()
+	if len(books) != 66 || books[0] != "GEN" || books[65] != "REV" {
+		t.Fatalf("Expected 66 books from GEN to REV, got %d", len(books))
+	}
+
+	testCases := []struct {
+		book     string
+		chapter  int
+		expected int
+	}{
+		{"GEN", 1, 31},
+		{"PSA", 119, 176},
+		{"MAL", 4, 6},
+		{"3JN", 1, 14},
+		{"rev", 22, 21},
+		{"GEN", 51, 0},
+		{"TOB", 1, 0},
+	}
+	for _, tc := range testCases {
+		if verses := English.Verses(tc.book, tc.chapter); verses != tc.expected {
+			t.Errorf("%s %d: expected %d verses, got %d", tc.book, tc.chapter, tc.expected, verses)
+		}
+	}
+
+	if chapters := English.Chapters("PSA"); chapters != 150 {
+		t.Errorf("Expected 150 chapters in PSA, got %d", chapters)
+	}
+
+	total := 0
+	for _, book := range books {
+		for chapter := 1; chapter <= English.Chapters(book); chapter++ {
+			total += English.Verses(book, chapter)
+		}
+	}
+	if total != 31102 {
+		t.Errorf("Expected 31102 verses, got %d", total)
+	}
+}
+
+// TestParse tests parsing of .vrs book lines and their errors
+func TestParse(t *testing.T) {
+	scheme, err := parse("Test", strings.NewReader("# comment\n\nGEN 1:31 2:25\nOBA 1:21\n"))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	if strings.Join(scheme.Books(), ",") != "GEN,OBA" || scheme.Verses("GEN", 2) != 25 {
+		t.Errorf("Unexpected scheme %+v", scheme)
+	}
+
+	invalid := []string{
+		"XYZ 1:1",
+		"GEN 1:31 3:24",
+		"GEN 1-31",
+		"GEN 1:x",
+		"GEN 1:31\nGEN 1:31",
+	}
+	for _, input := range invalid {
+		if _, err := parse("Test", strings.NewReader(input)); err == nil {
+			t.Errorf("Expected error for %q", input)
+		}
+	}
+}
+
+// TestBuiltIn tests the verse totals and lookup of the built-in schemes
+func TestBuiltIn(t *testing.T) {
+	testCases := []struct {
+		name     string
+		scheme   *Scheme
+		books    int
+		psalms   int
+		expected int
+	}{
+		{"eng", English, 66, 150, 31102},
+		{"Original", Original, 66, 150, 31171},
+		{"LXX", Septuagint, 82, 151, 36901},
+		{"vulgate", Vulgate, 73, 150, 35824},
+	}
+
+	for _, tc := range testCases {
+		scheme, ok := Lookup(tc.name)
+		if !ok || scheme != tc.scheme {
+			t.Errorf("Expected %s to be the %s scheme", tc.name, tc.scheme.Name)
+			continue
+		}
+		if len(scheme.Books()) != tc.books {
+			t.Errorf("%s: expected %d books, got %d", scheme.Name, tc.books, len(scheme.Books()))
+		}
+		if chapters := scheme.Chapters("PSA"); chapters != tc.psalms {
+			t.Errorf("%s: expected %d Psalms, got %d", scheme.Name, tc.psalms, chapters)
+		}
+
+		total := 0
+		for _, book := range scheme.Books() {
+			for chapter := 1; chapter <= scheme.Chapters(book); chapter++ {
+				total += scheme.Verses(book, chapter)
+			}
+		}
+		if total != tc.expected {
+			t.Errorf("%s: expected %d verses, got %d", scheme.Name, tc.expected, total)
+		}
+	}
+
+	// Deuterocanonical books
+	deuterocanonical := []struct {
+		scheme            *Scheme
+		book              string
+		chapters, chapter int
+		verses            int
+	}{
+		{Septuagint, "TOB", 14, 6, 17},
+		{Septuagint, "SIR", 51, 24, 34},
+		{Septuagint, "BAR", 5, 3, 37},
+		{Septuagint, "LJE", 1, 1, 73},
+		{Septuagint, "2MA", 15, 15, 39},
+		{Vulgate, "TOB", 14, 6, 22},
+		{Vulgate, "BAR", 6, 6, 72},
+		{Vulgate, "EST", 16, 15, 19},
+		{Vulgate, "LJE", 0, 1, 0},
+		{English, "TOB", 0, 1, 0},
+	}
+	for _, tc := range deuterocanonical {
+		if chapters := tc.scheme.Chapters(tc.book); chapters != tc.chapters {
+			t.Errorf("%s: expected %d chapters of %s, got %d", tc.scheme.Name, tc.chapters, tc.book, chapters)
+		}
+		if verses := tc.scheme.Verses(tc.book, tc.chapter); verses != tc.verses {
+			t.Errorf("%s: expected %d verses in %s %d, got %d", tc.scheme.Name, tc.verses, tc.book, tc.chapter, verses)
+		}
+	}
+
+	if _, ok := Lookup("kjv"); ok {
+		t.Error("Expected no scheme named kjv")
+	}
+}
+
+// TestMap tests mapping verses between the built-in schemes
+func TestMap(t *testing.T) {
+	testCases := []struct {
+		verse    ref.Reference
+		from, to *Scheme
+		expected ref.Reference
+		ok       bool
+	}{
+		{ref.Reference{Book: "PSA", Chapter: 51, Verse: 1}, English, Original, ref.Reference{Book: "PSA", Chapter: 51, Verse: 3}, true},
+		{ref.Reference{Book: "PSA", Chapter: 51, Verse: 1}, English, Vulgate, ref.Reference{Book: "PSA", Chapter: 50, Verse: 3}, true},
+		{ref.Reference{Book: "PSA", Chapter: 10, Verse: 1}, English, Septuagint, ref.Reference{Book: "PSA", Chapter: 9, Verse: 22}, true},
+		{ref.Reference{Book: "PSA", Chapter: 3, Verse: 8, Segment: "b"}, English, Original, ref.Reference{Book: "PSA", Chapter: 3, Verse: 9, Segment: "b"}, true},
+		{ref.Reference{Book: "GEN", Chapter: 31, Verse: 55}, English, Original, ref.Reference{Book: "GEN", Chapter: 32, Verse: 1}, true},
+		{ref.Reference{Book: "GEN", Chapter: 32, Verse: 1}, Original, English, ref.Reference{Book: "GEN", Chapter: 31, Verse: 55}, true},
+		{ref.Reference{Book: "MAL", Chapter: 4, Verse: 6}, English, Vulgate, ref.Reference{Book: "MAL", Chapter: 4, Verse: 6}, true},
+		{ref.Reference{Book: "MAL", Chapter: 3, Verse: 24}, Septuagint, Vulgate, ref.Reference{Book: "MAL", Chapter: 4, Verse: 6}, true},
+		{ref.Reference{Book: "DAN", Chapter: 3, Verse: 24}, English, Vulgate, ref.Reference{Book: "DAN", Chapter: 3, Verse: 91}, true},
+		{ref.Reference{Book: "3JN", Chapter: 1, Verse: 15}, Original, English, ref.Reference{Book: "3JN", Chapter: 1, Verse: 14}, true},
+		{ref.Reference{Book: "3JN", Chapter: 1, Verse: 15}, Septuagint, Vulgate, ref.Reference{Book: "3JN", Chapter: 1, Verse: 14}, true},
+		{ref.Reference{Book: "3JN", Chapter: 1, Verse: 14}, English, Original, ref.Reference{Book: "3JN", Chapter: 1, Verse: 14}, true},
+		{ref.Reference{Book: "REV", Chapter: 12, Verse: 18}, Original, English, ref.Reference{Book: "REV", Chapter: 12, Verse: 17}, true},
+		{ref.Reference{Book: "REV", Chapter: 12, Verse: 18}, Original, Vulgate, ref.Reference{Book: "REV", Chapter: 12, Verse: 17}, true},
+		{ref.Reference{Book: "JHN", Chapter: 3, Verse: 16}, English, Vulgate, ref.Reference{Book: "JHN", Chapter: 3, Verse: 16}, true},
+		{ref.Reference{Book: "JHN", Chapter: 3}, English, Original, ref.Reference{Book: "JHN", Chapter: 3}, true},
+		{ref.Reference{Book: "PSA", Chapter: 51, Verse: 1}, Original, English, ref.Reference{}, false},
+		{ref.Reference{Book: "DAN", Chapter: 13, Verse: 1}, Vulgate, Septuagint, ref.Reference{Book: "SUS", Chapter: 1, Verse: 1}, true},
+		{ref.Reference{Book: "BEL", Chapter: 1, Verse: 42}, Septuagint, Vulgate, ref.Reference{Book: "DAN", Chapter: 14, Verse: 42}, true},
+		{ref.Reference{Book: "BAR", Chapter: 6, Verse: 1}, Vulgate, Septuagint, ref.Reference{Book: "LJE", Chapter: 1, Verse: 2}, true},
+		{ref.Reference{Book: "LJE", Chapter: 1, Verse: 73}, Septuagint, Vulgate, ref.Reference{Book: "BAR", Chapter: 6, Verse: 72}, true},
+		{ref.Reference{Book: "EST", Chapter: 15, Verse: 4}, Vulgate, Septuagint, ref.Reference{Book: "ESG", Chapter: 15, Verse: 1}, true},
+		{ref.Reference{Book: "SIR", Chapter: 1, Verse: 1}, Septuagint, Vulgate, ref.Reference{Book: "SIR", Chapter: 1, Verse: 1}, true},
+		{ref.Reference{Book: "SIR", Chapter: 1, Verse: 40}, Vulgate, Septuagint, ref.Reference{}, false},
+	}
+
+	for _, tc := range testCases {
+		result, ok := Map(tc.verse, tc.from, tc.to)
+		if ok != tc.ok || result != tc.expected {
+			t.Errorf("%s %s in %s: expected %+v (%t), got %+v (%t)", tc.from.Name, tc.verse, tc.to.Name, tc.expected, tc.ok, result, ok)
+		}
+	}
+}
+
+// TestLoad tests loading mapping and excluded verse lines
+func TestLoad(t *testing.T) {
+	input := "\ufeff# Test scheme\nPSA 1:6 2:12 3:9 4:8\n-PSA 4:8\nPSA 3:1-8 = PSA 3:2-9\nPSA 4:1 = PSA 4:1-2\n*PSA 3:1,a,b\n"
+	scheme, err := Load("Test", strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	psalm := func(chapter, verse int) ref.Reference {
+		return ref.Reference{Book: "PSA", Chapter: chapter, Verse: verse}
+	}
+	if original := scheme.ToOriginal(psalm(3, 8)); original != psalm(3, 9) {
+		t.Errorf("Expected PSA 3:8 to map to PSA 3:9, got %+v", original)
+	}
+	if original := scheme.ToOriginal(psalm(3, 9)); original != psalm(3, 9) {
+		t.Errorf("Expected PSA 3:9 to be unmapped, got %+v", original)
+	}
+	if verse, ok := scheme.FromOriginal(psalm(3, 1)); ok {
+		t.Errorf("Expected the title PSA 3:1 to have no verse, got %+v", verse)
+	}
+	if verse, ok := scheme.FromOriginal(psalm(4, 2)); !ok || verse != psalm(4, 1) {
+		t.Errorf("Expected PSA 4:2 to map to PSA 4:1, got %+v (%t)", verse, ok)
+	}
+	if !scheme.Excluded(psalm(4, 8)) || scheme.Contains(psalm(4, 8)) || !scheme.Contains(psalm(4, 7)) {
+		t.Error("Expected PSA 4:8 to be excluded")
+	}
+
+	invalid := []string{
+		"PSA 3:9\nPSA 3:1-8 = XYZ 3:2-9",
+		"PSA 3:9\nPSA 3:8-1 = PSA 3:9",
+		"PSA 3:9\nPSA 3 = PSA 3:2",
+		"PSA 3:9\n-PSA 3",
+	}
+	for _, input := range invalid {
+		if _, err := Load("Test", strings.NewReader(input)); err == nil {
+			t.Errorf("Expected error for %q", input)
+		}
+	}
+
+	if _, err := LoadFile("testdata/missing.vrs"); err == nil {
+		t.Error("Expected error for a missing file")
+	}
+}
+
+// TestCustomize tests applying a custom.vrs file to a built-in scheme
+func TestCustomize(t *testing.T) {
+	custom, err := English.Customize(strings.NewReader("# custom.vrs\n3JN 1:15\n-MAT 17:21\n"))
+	if err != nil {
+		t.Fatalf("Customize failed: %v", err)
+	}
+
+	if custom.Verses("3JN", 1) != 15 || English.Verses("3JN", 1) != 14 {
+		t.Errorf("Expected 15 verses in 3JN 1, got %d (English %d)", custom.Verses("3JN", 1), English.Verses("3JN", 1))
+	}
+	if custom.Verses("GEN", 1) != 31 {
+		t.Errorf("Expected other chapters to be unchanged, got %d verses in GEN 1", custom.Verses("GEN", 1))
+	}
+	verse := ref.Reference{Book: "MAT", Chapter: 17, Verse: 21}
+	if !custom.Excluded(verse) || English.Excluded(verse) {
+		t.Error("Expected MAT 17:21 to be excluded from the custom scheme only")
+	}
+	if original := custom.ToOriginal(ref.Reference{Book: "PSA", Chapter: 51, Verse: 1}); original.Verse != 3 {
+		t.Errorf("Expected the mappings of the English scheme, got %+v", original)
+	}
+}
+
+// TestValidate tests comparing a parsed book with a scheme
+func TestValidate(t *testing.T) {
+	scheme, err := Load("Test", strings.NewReader("RUT 1:5 2:3 3:2\n-RUT 2:3"))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	input := `\id RUT
+\c 1
+\p
+\v 1 In the days when the judges ruled.
+\v 2-3 The man's name was Elimelech.
+\c 2
+\p
+\v 1 Naomi had a relative.
+\v 2 Ruth said to Naomi.
+\v 3 So she went out.
+\v 4 Boaz came from Bethlehem.
+\v 5 Boaz asked.
+\c 4
+\p
+\v 1 Boaz went up to the gate.`
+
+	doc, err := usfm.NewParser(usfm.ParseOptions{}).Parse(strings.NewReader(input), "RUT.usfm")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	expected := []string{
+		"missing-verse Ruth 1:4–5 are missing",
+		"excluded-verse Ruth 2:3 is excluded from the Test versification",
+		"extra-verse Ruth 2:4–5 are not in the Test versification",
+		"missing-chapter Ruth 3 is missing",
+		"extra-chapter Ruth 4 is not in the Test versification",
+	}
+	var result []string
+	for _, difference := range scheme.Validate(doc) {
+		result = append(result, difference.Kind+" "+difference.Message)
+	}
+	if strings.Join(result, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected differences:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(result, "\n"))
+	}
+
+	doc.ID = "TOB"
+	if differences := scheme.Validate(doc); len(differences) != 1 || differences[0].Kind != UnknownBook {
+		t.Errorf("Expected an unknown book, got %v", differences)
+	}
+}